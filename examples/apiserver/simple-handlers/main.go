@@ -36,7 +36,7 @@ func apiDefiner(ctx context.Context, config cfg.Config, logger mon.Logger) (*api
 
 	group.GET("/authenticated", apiserver.CreateHandler(&AdminAuthenticatedHandler{}))
 
-	crud.AddCrudHandlers(logger, definitions, 0, "/myEntity", &MyEntityHandler{
+	crud.AddCrudHandlers(config, logger, definitions, 0, "/myEntity", &MyEntityHandler{
 		repo: &MyEntityRepository{},
 	})
 
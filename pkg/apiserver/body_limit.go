@@ -0,0 +1,77 @@
+package apiserver
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+)
+
+// ErrRequestBodyTooLarge is returned while reading a body limited with LimitRequestBody beyond its limit. If binding
+// the input of a handler fails with it, the request is answered with a 413 instead of a 400.
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// LimitRequestBody wraps the body of the request into a http.MaxBytesReader, so at most maxBytes of it can be read.
+// A maxBytes of 0 or less doesn't limit the body.
+func LimitRequestBody(ginCtx *gin.Context, maxBytes int64) {
+	if maxBytes <= 0 || ginCtx.Request.Body == nil {
+		return
+	}
+
+	ginCtx.Request.Body = &limitedBody{
+		ReadCloser: http.MaxBytesReader(ginCtx.Writer, ginCtx.Request.Body, maxBytes),
+		remaining:  maxBytes,
+	}
+}
+
+type maxBodySizeKey struct{}
+
+// MaxBodySizeMiddleware stores the max body size in bytes, configured with api_max_body_size, in the context of every
+// request. It doesn't limit the body itself: handlers accepting bodies, like the crud write handlers, read it with
+// MaxBodySizeFromContext and pass it to LimitRequestBody.
+func MaxBodySizeMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ctx := ContextWithMaxBodySize(ginCtx.Request.Context(), maxBytes)
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+
+		ginCtx.Next()
+	}
+}
+
+func ContextWithMaxBodySize(ctx context.Context, maxBytes int64) context.Context {
+	return context.WithValue(ctx, maxBodySizeKey{}, maxBytes)
+}
+
+// MaxBodySizeFromContext returns the max body size stored with ContextWithMaxBodySize, 0 if there is none.
+func MaxBodySizeFromContext(ctx context.Context) int64 {
+	maxBytes, _ := ctx.Value(maxBodySizeKey{}).(int64)
+
+	return maxBytes
+}
+
+// limitedBody replaces the unexported error of the http.MaxBytesReader with ErrRequestBodyTooLarge. The reader only
+// fails with it after returning all of the allowed bytes.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	if err != nil && err != io.EOF && b.remaining <= 0 {
+		return n, ErrRequestBodyTooLarge
+	}
+
+	return n, err
+}
+
+func getBindErrorStatus(err error) int {
+	if errors.Is(err, ErrRequestBodyTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
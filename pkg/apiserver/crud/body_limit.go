@@ -0,0 +1,34 @@
+package crud
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySizeHandler can be implemented by a create, update or upsert handler to limit the size of its request bodies
+// in bytes, e.g. to allow bigger payloads for a single endpoint. It takes precedence over the api_max_body_size
+// setting of the api server.
+//
+//go:generate mockery -name MaxBodySizeHandler
+type MaxBodySizeHandler interface {
+	GetMaxBodySize() int64
+}
+
+// withMaxBodySize limits the request body to the size of the handler or the api_max_body_size setting of the api
+// server, see apiserver.MaxBodySizeMiddleware. 0 disables the limit. Bigger bodies are answered with 413 before they
+// are decoded.
+func withMaxBodySize(transformer interface{}, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		apiserver.LimitRequestBody(ginCtx, getMaxBodySize(ginCtx.Request.Context(), transformer))
+		handler(ginCtx)
+	}
+}
+
+func getMaxBodySize(ctx context.Context, transformer interface{}) int64 {
+	if sizeHandler, ok := transformer.(MaxBodySizeHandler); ok {
+		return sizeHandler.GetMaxBodySize()
+	}
+
+	return apiserver.MaxBodySizeFromContext(ctx)
+}
@@ -0,0 +1,140 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/apiserver/sql"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/inflection"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// BulkDeleteQueryParamConfirm has to be set to true for a bulk delete request, so a mass delete can't happen by
+// accident.
+const BulkDeleteQueryParamConfirm = "confirm"
+
+const bulkDeleteBatchSize = 100
+
+type BulkDeleteOutput struct {
+	Deleted int `json:"deleted"`
+}
+
+type bulkDeleteHandler struct {
+	transformer BaseHandler
+	logger      mon.Logger
+}
+
+// NewBulkDeleteHandler returns a handler deleting all rows matching the filter of a list request. The filter is
+// checked against the same field mappings as the one of a list request, the page and the order are ignored. The rows
+// are read and deleted in batches, including their cascade rules, and the number of deleted rows is returned as
+// BulkDeleteOutput. All rows are deleted in a single transaction if the repository implements
+// db_repo.TransactionalRepository, so a failing delete rolls back the rows deleted up to then.
+func NewBulkDeleteHandler(logger mon.Logger, transformer BaseHandler) gin.HandlerFunc {
+	bh := bulkDeleteHandler{
+		transformer: transformer,
+		logger:      logger,
+	}
+
+	return apiserver.CreateJsonHandler(bh)
+}
+
+// AddBulkDeleteHandler adds the bulk delete handler next to the list handler, e.g. POST /v1/users/delete?confirm=true
+// for the base path user.
+func AddBulkDeleteHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler BaseHandler) {
+	plural := inflection.Plural(basePath)
+	path := fmt.Sprintf("/v%d/%s/delete", version, plural)
+	d.POST(path, NewBulkDeleteHandler(logger, handler))
+}
+
+func (bh bulkDeleteHandler) GetInput() interface{} {
+	return sql.NewInput()
+}
+
+func (bh bulkDeleteHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	if confirmed, err := strconv.ParseBool(request.Url.Query().Get(BulkDeleteQueryParamConfirm)); err != nil || !confirmed {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, fmt.Errorf("a bulk delete has to be confirmed with ?%s=true", BulkDeleteQueryParamConfirm)), nil
+	}
+
+	inp := request.Body.(*sql.Input)
+
+	if len(inp.Filter.Matches) == 0 && len(inp.Filter.Groups) == 0 {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, fmt.Errorf("a bulk delete requires a filter")), nil
+	}
+
+	repo := bh.transformer.GetRepository()
+	lqb := sql.NewOrmQueryBuilder(repo.GetMetadata())
+	qb, err := lqb.Build(inp)
+
+	if err != nil {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+	}
+
+	qb.Page(0, bulkDeleteBatchSize)
+
+	var deleted int
+
+	err = db_repo.RunInTransaction(ctx, repo, func(ctx context.Context) (err error) {
+		deleted, err = bh.deleteAll(ctx, repo, qb)
+
+		return err
+	})
+
+	if errors.Is(err, &validation.Error{}) {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+	}
+
+	if err != nil {
+		bh.logger.WithContext(ctx).Warnf("bulk delete failed after deleting %d rows in its transaction", deleted)
+		return nil, err
+	}
+
+	return apiserver.NewJsonResponse(BulkDeleteOutput{
+		Deleted: deleted,
+	}), nil
+}
+
+// deleteAll reads the first batch of matching rows until there are none left. A row still matching after it was
+// deleted would make this run forever, so it fails instead.
+func (bh bulkDeleteHandler) deleteAll(ctx context.Context, repo Repository, qb *db_repo.QueryBuilder) (int, error) {
+	deleted := make(map[uint]bool)
+	modelType := reflect.TypeOf(bh.transformer.GetModel())
+	rules := getCascadeRules(bh.transformer)
+
+	for {
+		results := reflect.New(reflect.SliceOf(modelType))
+
+		if err := repo.Query(ctx, qb, results.Interface()); err != nil && !db_repo.IsNoQueryResultsError(err) {
+			return len(deleted), err
+		}
+
+		if results.Elem().Len() == 0 {
+			return len(deleted), nil
+		}
+
+		for i := 0; i < results.Elem().Len(); i++ {
+			model := results.Elem().Index(i).Interface().(db_repo.ModelBased)
+			id := *model.GetId()
+
+			if deleted[id] {
+				return len(deleted), fmt.Errorf("the row %d still matches the filter after deleting it", id)
+			}
+
+			if _, err := cascadeDelete(ctx, rules, model, false); err != nil {
+				return len(deleted), err
+			}
+
+			if err := repo.Delete(ctx, model); err != nil {
+				return len(deleted), err
+			}
+
+			deleted[id] = true
+		}
+	}
+}
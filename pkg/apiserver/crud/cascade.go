@@ -0,0 +1,102 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"reflect"
+)
+
+// CascadeRule deletes the rows of the child repository whose foreign key references a deleted parent.
+type CascadeRule struct {
+	Repository Repository
+	// Model is an instance of the child model, the rows to delete are read into values of its type
+	Model db_repo.ModelBased
+	// ForeignKey is the column of the child table holding the id of the parent
+	ForeignKey string
+	// Rules are applied to every deleted child, e.g. to delete the children of the children
+	Rules []CascadeRule
+}
+
+// CascadeDeletion describes a row deleted (or, in a dry run, to be deleted) by a delete handler.
+type CascadeDeletion struct {
+	Table string `json:"table"`
+	Id    uint   `json:"id"`
+}
+
+type CascadeDryRunOutput struct {
+	Deletions []CascadeDeletion `json:"deletions"`
+}
+
+// CascadeDeleteHandler can be implemented by a handler to make its delete handlers delete the children of a model
+// first. The rules of a rule are applied to the children it deletes, so the rows are deleted in dependency order, the
+// deepest children first.
+//
+// The children and the model are deleted in a single transaction if the repository of the handler implements
+// db_repo.TransactionalRepository, like the repositories of db_repo do. The child repositories have to use the same
+// database to be part of the transaction.
+//
+//go:generate mockery -name CascadeDeleteHandler
+type CascadeDeleteHandler interface {
+	GetCascadeRules() []CascadeRule
+}
+
+func getCascadeRules(handler interface{}) []CascadeRule {
+	if cascadeHandler, ok := handler.(CascadeDeleteHandler); ok {
+		return cascadeHandler.GetCascadeRules()
+	}
+
+	return nil
+}
+
+// cascadeDelete deletes the children of the parent model in dependency order and returns what it deleted. In a dry
+// run it only returns what it would delete.
+func cascadeDelete(ctx context.Context, rules []CascadeRule, parent db_repo.ModelBased, dryRun bool) ([]CascadeDeletion, error) {
+	deletions := make([]CascadeDeletion, 0)
+
+	for _, rule := range rules {
+		if rule.Repository == nil || rule.Model == nil || rule.ForeignKey == "" {
+			return nil, fmt.Errorf("a cascade rule needs a repository, a model and a foreign key")
+		}
+
+		qb := db_repo.NewQueryBuilder()
+		qb.Where(fmt.Sprintf("%s = ?", rule.ForeignKey), *parent.GetId())
+
+		results := reflect.New(reflect.SliceOf(reflect.TypeOf(rule.Model)))
+		err := rule.Repository.Query(ctx, qb, results.Interface())
+
+		if db_repo.IsNoQueryResultsError(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can not read the children of %d with the foreign key %s: %w", *parent.GetId(), rule.ForeignKey, err)
+		}
+
+		table := rule.Repository.GetMetadata().TableName
+
+		for i := 0; i < results.Elem().Len(); i++ {
+			child := results.Elem().Index(i).Interface().(db_repo.ModelBased)
+			childDeletions, err := cascadeDelete(ctx, rule.Rules, child, dryRun)
+
+			if err != nil {
+				return nil, err
+			}
+
+			deletions = append(deletions, childDeletions...)
+
+			if !dryRun {
+				if err := rule.Repository.Delete(ctx, child); err != nil {
+					return nil, fmt.Errorf("can not delete %d of table %s: %w", *child.GetId(), table, err)
+				}
+			}
+
+			deletions = append(deletions, CascadeDeletion{
+				Table: table,
+				Id:    *child.GetId(),
+			})
+		}
+	}
+
+	return deletions, nil
+}
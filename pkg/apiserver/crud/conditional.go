@@ -0,0 +1,69 @@
+package crud
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIfModifiedSince = "If-Modified-Since"
+	HeaderIfNoneMatch     = "If-None-Match"
+	HeaderLastModified    = "Last-Modified"
+)
+
+// getLastModified returns the update time of the model in the precision of http dates. Models without an update time
+// have no last modification.
+func getLastModified(model db_repo.ModelBased) (time.Time, bool) {
+	timestamps, ok := model.(db_repo.TimestampAware)
+
+	if !ok || timestamps.GetUpdatedAt() == nil || timestamps.GetUpdatedAt().IsZero() {
+		return time.Time{}, false
+	}
+
+	return timestamps.GetUpdatedAt().UTC().Truncate(time.Second), true
+}
+
+// isNotModified reports whether the model wasn't modified since the If-Modified-Since date of the request. The date
+// is ignored if the request contains If-None-Match, as entity tags take precedence over dates (RFC 7232, section 3.3).
+func isNotModified(header http.Header, lastModified time.Time) bool {
+	if header.Get(HeaderIfNoneMatch) != "" {
+		return false
+	}
+
+	since, err := http.ParseTime(header.Get(HeaderIfModifiedSince))
+
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(since)
+}
+
+// withLastModified answers a conditional request with 304 and no body if the model wasn't modified since the date of
+// the client. Otherwise, it adds the Last-Modified header to the response created by the given function.
+func withLastModified(model db_repo.ModelBased, request *apiserver.Request, createResponse func() (*apiserver.Response, error)) (*apiserver.Response, error) {
+	lastModified, ok := getLastModified(model)
+
+	if !ok {
+		return createResponse()
+	}
+
+	if isNotModified(request.Header, lastModified) {
+		resp := apiserver.NewStatusResponse(http.StatusNotModified)
+		resp.AddHeader(HeaderLastModified, lastModified.Format(http.TimeFormat))
+
+		return resp, nil
+	}
+
+	resp, err := createResponse()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.AddHeader(HeaderLastModified, lastModified.Format(http.TimeFormat))
+
+	return resp, nil
+}
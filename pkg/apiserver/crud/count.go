@@ -0,0 +1,104 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/apiserver/sql"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/inflection"
+	"net/http"
+	"strconv"
+)
+
+const HeaderTotalCount = "X-Total-Count"
+
+type CountOutput struct {
+	Total int `json:"total"`
+}
+
+type countHandler struct {
+	transformer ListHandler
+	logger      mon.Logger
+	settings    *ListSettings
+}
+
+// NewCountHandler returns a handler counting the rows matching the filter of a list request without reading any of
+// them. The page and the order of the request are ignored. The count is returned as CountOutput and in the
+// X-Total-Count header. The query is limited by the same timeout as the list queries.
+func NewCountHandler(config cfg.Config, logger mon.Logger, transformer ListHandler) gin.HandlerFunc {
+	settings := &ListSettings{
+		Timeout: config.GetDuration("api_list_timeout", 0),
+	}
+
+	return NewCountHandlerWithInterfaces(logger, transformer, settings)
+}
+
+func NewCountHandlerWithInterfaces(logger mon.Logger, transformer ListHandler, settings *ListSettings) gin.HandlerFunc {
+	ch := countHandler{
+		transformer: transformer,
+		logger:      logger,
+		settings:    settings,
+	}
+
+	return apiserver.CreateJsonHandler(ch)
+}
+
+// AddCountHandler adds the count handler next to the list handler, e.g. POST /v1/users/count for the base path user.
+func AddCountHandler(config cfg.Config, logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler ListHandler) {
+	plural := inflection.Plural(basePath)
+	path := fmt.Sprintf("/v%d/%s/count", version, plural)
+	d.POST(path, NewCountHandler(config, logger, handler))
+}
+
+func (ch countHandler) GetInput() interface{} {
+	return sql.NewInput()
+}
+
+func (ch countHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	inp := request.Body.(*sql.Input)
+
+	repo := ch.transformer.GetRepository()
+	metadata := repo.GetMetadata()
+
+	lqb := sql.NewOrmQueryBuilder(metadata)
+	qb, err := lqb.Build(inp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withListTimeout(ctx, ch.transformer, ch.settings.Timeout)
+	defer cancel()
+
+	var total int
+
+	err = runWithDeadline(ctx, func() error {
+		var err error
+		total, err = repo.Count(ctx, qb, ch.transformer.GetModel())
+
+		return err
+	})
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apiserver.GetErrorHandler()(http.StatusGatewayTimeout, fmt.Errorf("the count query took too long: %w", err)), nil
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return apiserver.GetErrorHandler()(http.StatusServiceUnavailable, fmt.Errorf("the count query was canceled: %w", err)), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := apiserver.NewJsonResponse(CountOutput{
+		Total: total,
+	})
+	resp.AddHeader(HeaderTotalCount, strconv.Itoa(total))
+
+	return resp, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/validation"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"net/http"
 )
 
@@ -22,16 +23,32 @@ func NewCreateHandler(logger mon.Logger, transformer CreateHandler) gin.HandlerF
 		logger:      logger,
 	}
 
-	return apiserver.CreateJsonHandler(ch)
+	return withMaxBodySize(transformer, apiserver.CreateMultipleBindingsHandler(ch))
 }
 
 func (ch createHandler) GetInput() interface{} {
 	return ch.transformer.GetCreateInput()
 }
 
+func (ch createHandler) GetBindings() []binding.Binding {
+	return []binding.Binding{jsonDecodeBinding{}}
+}
+
 func (ch createHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	inputView, outputView := GetApiViews(ch.transformer, request.Header)
+	err := validateInput(ctx, ch.transformer, request.Body, inputView)
+
+	var inputErr *InputValidationError
+	if errors.As(err, &inputErr) {
+		return newInputValidationErrorResponse(inputErr), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
 	model := ch.transformer.GetModel()
-	err := ch.transformer.TransformCreate(request.Body, model)
+	err = ch.transformer.TransformCreate(request.Body, model)
 
 	if err != nil {
 		return nil, err
@@ -52,6 +69,10 @@ func (ch createHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return nil, err
 	}
 
+	if getReturnPreference(ch.transformer, request.Header) == ReturnMinimal {
+		return newMinimalResponse(http.StatusCreated, getCreatedLocation(ch.transformer, request, model.GetId())), nil
+	}
+
 	reload := ch.transformer.GetModel()
 	err = repo.Read(ctx, model.GetId(), reload)
 
@@ -59,8 +80,7 @@ func (ch createHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return nil, err
 	}
 
-	apiView := GetApiViewFromHeader(request.Header)
-	out, err := ch.transformer.TransformOutput(reload, apiView)
+	out, err := ch.transformer.TransformOutput(reload, outputView)
 
 	if err != nil {
 		return nil, err
@@ -6,10 +6,14 @@ import (
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/apiserver/crud"
 	"github.com/applike/gosoline/pkg/apiserver/crud/mocks"
+	"github.com/applike/gosoline/pkg/apiserver/sql"
+	cfgMocks "github.com/applike/gosoline/pkg/cfg/mocks"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/validation"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
@@ -196,6 +200,207 @@ func TestReadHandler_Handle(t *testing.T) {
 	transformer.Repo.AssertExpectations(t)
 }
 
+func TestReadHandler_Handle_IfModifiedSince(t *testing.T) {
+	updatedAt := time.Date(2021, 2, 3, 4, 5, 6, 700, time.UTC)
+
+	tests := map[string]struct {
+		header       http.Header
+		expectedCode int
+		expectedBody string
+	}{
+		"modified": {
+			header:       http.Header{"If-Modified-Since": {"Wed, 03 Feb 2021 04:05:05 GMT"}},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":1,"updatedAt":"2021-02-03T04:05:06.0000007Z","createdAt":null,"name":"foobar"}`,
+		},
+		"not modified": {
+			header:       http.Header{"If-Modified-Since": {"Wed, 03 Feb 2021 04:05:06 GMT"}},
+			expectedCode: http.StatusNotModified,
+		},
+		"if-none-match takes precedence": {
+			header:       http.Header{"If-Modified-Since": {"Wed, 03 Feb 2021 04:05:06 GMT"}, "If-None-Match": {`"abc"`}},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":1,"updatedAt":"2021-02-03T04:05:06.0000007Z","createdAt":null,"name":"foobar"}`,
+		},
+		"invalid date": {
+			header:       http.Header{"If-Modified-Since": {"yesterday"}},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":1,"updatedAt":"2021-02-03T04:05:06.0000007Z","createdAt":null,"name":"foobar"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := monMocks.NewLoggerMockedAll()
+			transformer := NewTransformer()
+			transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &Model{}).Run(func(args mock.Arguments) {
+				model := args.Get(2).(*Model)
+				model.Id = mdl.Uint(1)
+				model.Name = mdl.String("foobar")
+				model.UpdatedAt = &updatedAt
+			}).Return(nil)
+
+			handler := crud.NewReadHandler(logger, transformer)
+			response := apiserver.HttpTestWithHeader("GET", "/:id", "/1", "", test.header, handler)
+
+			assert.Equal(t, test.expectedCode, response.Code)
+			assert.Equal(t, "Wed, 03 Feb 2021 04:05:06 GMT", response.Header().Get("Last-Modified"))
+
+			if test.expectedBody == "" {
+				assert.Empty(t, response.Body.String())
+			} else {
+				assert.JSONEq(t, test.expectedBody, response.Body.String())
+			}
+
+			transformer.Repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReadByKeyHandler_Handle(t *testing.T) {
+	qb := db_repo.NewQueryBuilder()
+	qb.Where("slug = ?", "foo")
+	qb.Page(0, 2)
+
+	tests := map[string]struct {
+		models       []*Model
+		expectedCode int
+		expectedBody string
+	}{
+		"found": {
+			models: []*Model{
+				{
+					Model: db_repo.Model{
+						Id: mdl.Uint(1),
+						Timestamps: db_repo.Timestamps{
+							UpdatedAt: &time.Time{},
+							CreatedAt: &time.Time{},
+						},
+					},
+					Name: mdl.String("foobar"),
+				},
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":1,"updatedAt":"0001-01-01T00:00:00Z","createdAt":"0001-01-01T00:00:00Z","name":"foobar"}`,
+		},
+		"not found": {
+			models:       []*Model{},
+			expectedCode: http.StatusNotFound,
+		},
+		"not unique": {
+			models:       []*Model{{}, {}},
+			expectedCode: http.StatusConflict,
+			expectedBody: `{"err":"there is more than one model with slug foo"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := monMocks.NewLoggerMockedAll()
+			transformer := NewTransformer()
+			transformer.Repo.On("Query", mock.Anything, qb, mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+				result := args.Get(2).(*[]*Model)
+				*result = test.models
+			}).Return(nil)
+
+			handler := crud.NewReadByKeyHandler(logger, transformer, "slug")
+			response := apiserver.HttpTest("GET", "/by-slug/:slug", "/by-slug/foo", "", handler)
+
+			assert.Equal(t, test.expectedCode, response.Code)
+
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, response.Body.String())
+			}
+
+			transformer.Repo.AssertExpectations(t)
+		})
+	}
+}
+
+type CompositeKeyHandler struct {
+	Handler
+}
+
+func (h CompositeKeyHandler) GetKeyColumns() []string {
+	return []string{"tenant_id", "id"}
+}
+
+func TestReadHandler_Handle_CompositeKey(t *testing.T) {
+	qb := db_repo.NewQueryBuilder()
+	qb.Page(0, 1)
+	qb.Where("tenant_id = ?", "3")
+	qb.Where("id = ?", "1")
+
+	tests := map[string]struct {
+		path         string
+		models       []*Model
+		expectedCode int
+		expectedBody string
+	}{
+		"found": {
+			path: "/3/1",
+			models: []*Model{
+				{
+					Model: db_repo.Model{
+						Id: mdl.Uint(1),
+						Timestamps: db_repo.Timestamps{
+							UpdatedAt: &time.Time{},
+							CreatedAt: &time.Time{},
+						},
+					},
+					Name: mdl.String("foobar"),
+				},
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":1,"updatedAt":"0001-01-01T00:00:00Z","createdAt":"0001-01-01T00:00:00Z","name":"foobar"}`,
+		},
+		"not found": {
+			path:         "/3/1",
+			models:       []*Model{},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := monMocks.NewLoggerMockedAll()
+			transformer := CompositeKeyHandler{
+				Handler: NewTransformer(),
+			}
+			transformer.Repo.On("Query", mock.Anything, qb, mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+				result := args.Get(2).(*[]*Model)
+				*result = test.models
+			}).Return(nil)
+
+			handler := crud.NewReadHandler(logger, transformer)
+			response := apiserver.HttpTest("GET", "/:tenant_id/:id", test.path, "", handler)
+
+			assert.Equal(t, test.expectedCode, response.Code)
+
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, response.Body.String())
+			}
+
+			transformer.Repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReadHandler_Handle_MissingKeyComponent(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := CompositeKeyHandler{
+		Handler: NewTransformer(),
+	}
+
+	handler := crud.NewReadHandler(logger, transformer)
+	response := apiserver.HttpTest("GET", "/:id", "/1", "", handler)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"err":"no valid tenant_id provided"}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
 func TestUpdateHandler_Handle(t *testing.T) {
 	readModel := &Model{}
 	updateModel := &Model{
@@ -232,6 +437,80 @@ func TestUpdateHandler_Handle(t *testing.T) {
 	transformer.Repo.AssertExpectations(t)
 }
 
+type UpsertHandler struct {
+	Handler
+}
+
+func (h UpsertHandler) GetUpsertInput() interface{} {
+	return &UpdateInput{}
+}
+
+func (h UpsertHandler) TransformUpsert(inp interface{}, model db_repo.ModelBased) (err error) {
+	return h.TransformUpdate(inp, model)
+}
+
+func TestUpsertHandler_Handle(t *testing.T) {
+	tests := map[string]struct {
+		readErr      error
+		method       string
+		expectedCode int
+	}{
+		"created": {
+			readErr:      db_repo.NewRecordNotFoundError(1, "model", fmt.Errorf("record not found")),
+			method:       "Create",
+			expectedCode: http.StatusCreated,
+		},
+		"replaced": {
+			method:       "Update",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := monMocks.NewLoggerMockedAll()
+			transformer := UpsertHandler{
+				Handler: NewTransformer(),
+			}
+
+			transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &Model{}).Run(func(args mock.Arguments) {
+				if test.readErr != nil {
+					return
+				}
+
+				model := args.Get(2).(*Model)
+				model.Id = mdl.Uint(1)
+				model.Name = mdl.String("stored")
+			}).Return(test.readErr).Once()
+
+			transformer.Repo.On(test.method, mock.Anything, &Model{
+				Model: db_repo.Model{
+					Id: mdl.Uint(1),
+				},
+				Name: mdl.String("upserted"),
+			}).Return(nil)
+
+			transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &Model{}).Run(func(args mock.Arguments) {
+				model := args.Get(2).(*Model)
+				model.Id = mdl.Uint(1)
+				model.Name = mdl.String("upserted")
+				model.UpdatedAt = &time.Time{}
+				model.CreatedAt = &time.Time{}
+			}).Return(nil).Once()
+
+			handler := crud.NewUpsertHandler(logger, transformer)
+
+			body := `{"name": "upserted"}`
+			response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+			assert.Equal(t, test.expectedCode, response.Code)
+			assert.JSONEq(t, `{"id":1,"updatedAt":"0001-01-01T00:00:00Z","createdAt":"0001-01-01T00:00:00Z","name":"upserted"}`, response.Body.String())
+
+			transformer.Repo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdateHandler_Handle_ValidationError(t *testing.T) {
 	readModel := &Model{}
 	updateModel := &Model{
@@ -343,7 +622,7 @@ func TestDeleteHandler_Handle_ValidationError(t *testing.T) {
 func TestListHandler_Handle(t *testing.T) {
 	logger := monMocks.NewLoggerMockedAll()
 	transformer := NewTransformer()
-	handler := crud.NewListHandler(logger, transformer)
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
 
 	qb := db_repo.NewQueryBuilder()
 	qb.Table("footable")
@@ -366,7 +645,976 @@ func TestListHandler_Handle(t *testing.T) {
 	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
 
 	assert.Equal(t, http.StatusOK, response.Code)
-	assert.JSONEq(t, `{"total":1,"results":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}]}`, response.Body.String())
+	assert.JSONEq(t, `{"total":1,"limit":2,"results":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}]}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestCountHandler_Handle(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewCountHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	metadata := db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	}
+
+	inp := sql.NewInput()
+	inp.Filter.Matches = []sql.FilterMatch{{Dimension: "name", Operator: "=", Values: []interface{}{"foobar"}}}
+	inp.Filter.Bool = "and"
+	inp.Page = &sql.Page{Offset: 0, Limit: 2}
+	qb, err := sql.NewOrmQueryBuilder(metadata).Build(inp)
+	assert.NoError(t, err)
+
+	transformer.Repo.On("GetMetadata").Return(metadata)
+	transformer.Repo.On("Count", mock.Anything, qb, &Model{}).Return(42, nil).Once()
+
+	body := `{"filter":{"matches":[{"values":["foobar"],"dimension":"name","operator":"="}],"bool":"and"},"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("POST", "/count", "/count", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "42", response.Header().Get(crud.HeaderTotalCount))
+	assert.JSONEq(t, `{"total":42}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestBulkDeleteHandler_Handle(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewBulkDeleteHandler(logger, transformer)
+
+	metadata := db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	}
+
+	inp := sql.NewInput()
+	inp.Filter.Matches = []sql.FilterMatch{{Dimension: "name", Operator: "=", Values: []interface{}{"foobar"}}}
+	inp.Filter.Bool = "and"
+	qb, err := sql.NewOrmQueryBuilder(metadata).Build(inp)
+	assert.NoError(t, err)
+	qb.Page(0, 100)
+
+	transformer.Repo.On("GetMetadata").Return(metadata)
+	transformer.Repo.On("Query", mock.Anything, qb, mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+		result := args.Get(2).(*[]*Model)
+		*result = []*Model{newCascadeModel(1), newCascadeModel(2)}
+	}).Return(nil).Once()
+	transformer.Repo.On("Query", mock.Anything, qb, mock.AnythingOfType("*[]*crud_test.Model")).Return(nil).Once()
+	transformer.Repo.On("Delete", mock.Anything, newCascadeModel(1)).Return(nil).Once()
+	transformer.Repo.On("Delete", mock.Anything, newCascadeModel(2)).Return(nil).Once()
+
+	body := `{"filter":{"matches":[{"values":["foobar"],"dimension":"name","operator":"="}],"bool":"and"}}`
+
+	response := apiserver.HttpTest("POST", "/delete", "/delete", body, handler)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "a bulk delete without confirmation should be rejected")
+
+	response = apiserver.HttpTest("POST", "/delete", "/delete?confirm=true", `{"filter":{}}`, handler)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "a bulk delete without filter should be rejected")
+
+	response = apiserver.HttpTest("POST", "/delete", "/delete?confirm=true", body, handler)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"deleted":2}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestBulkDeleteHandler_Handle_Rollback(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := CascadeHandler{
+		Handler: NewTransformer(),
+	}
+	transformer.repo = &TransactionalRepository{
+		Repository: transformer.Repo,
+	}
+	handler := crud.NewBulkDeleteHandler(logger, transformer)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Query", mock.Anything, mock.Anything, mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+		result := args.Get(2).(*[]*Model)
+		*result = []*Model{newCascadeModel(1), newCascadeModel(2)}
+	}).Return(nil).Once()
+	transformer.Repo.On("Delete", mock.Anything, newCascadeModel(1)).Return(nil).Once()
+	transformer.Repo.On("Delete", mock.Anything, newCascadeModel(2)).Return(fmt.Errorf("lock wait timeout")).Once()
+
+	body := `{"filter":{"matches":[{"values":["foobar"],"dimension":"name","operator":"="}],"bool":"and"}}`
+	response := apiserver.HttpTest("POST", "/delete", "/delete?confirm=true", body, handler)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, 1, transformer.repo.transactions, "all rows should be deleted in a single transaction")
+	assert.Equal(t, 1, transformer.repo.rollbacks, "the failed delete should roll back the rows deleted before")
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+type DataEnvelopeHandler struct {
+	Handler
+}
+
+func (h DataEnvelopeHandler) WrapListOutput(results interface{}, meta crud.ListMeta, _ string) (interface{}, error) {
+	return crud.DataListEnvelope(results, meta), nil
+}
+
+func TestListHandler_Handle_Envelope(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := DataEnvelopeHandler{
+		Handler: NewTransformer(),
+	}
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.Anything, mock.AnythingOfType("*db_repo.QueryBuilder"), &Model{}).Return(1, nil)
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1?withTotal=true", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"meta":{"total":1,"count":1,"limit":2},"data":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}]}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestCreateHandler_Handle_InputValidationError(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+
+	handler := crud.NewCreateHandler(logger, transformer)
+
+	body := `{}`
+	response := apiserver.HttpTest("POST", "/create", "/create", body, handler)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.JSONEq(t, `{"errors":[{"field":"Name","rule":"required","message":"field Name failed on the 'required' rule"}]}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestListHandler_Handle_WithoutTotal(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := DataEnvelopeHandler{
+		Handler: NewTransformer(),
+	}
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"meta":{"count":1,"limit":2},"data":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}]}`, response.Body.String(), "the total should be opt-in")
+
+	response = apiserver.HttpTest("PUT", "/:id", "/1?withTotal=false", body, handler)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	response = apiserver.HttpTest("PUT", "/:id", "/1?withTotal=nope", body, handler)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "an invalid value should be rejected")
+
+	transformer.Repo.AssertExpectations(t)
+	transformer.Repo.AssertNotCalled(t, "Count", mock.Anything, mock.Anything, mock.Anything)
+}
+
+type LimitsHandler struct {
+	DataEnvelopeHandler
+}
+
+func (h LimitsHandler) GetListLimits() (int, int) {
+	return 10, 5
+}
+
+func TestListHandler_Handle_Limits(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := LimitsHandler{
+		DataEnvelopeHandler: DataEnvelopeHandler{
+			Handler: NewTransformer(),
+		},
+	}
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	metadata := db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	}
+
+	clamped := sql.NewInput()
+	clamped.Page = &sql.Page{Offset: 0, Limit: 5}
+	qb, err := sql.NewOrmQueryBuilder(metadata).Build(clamped)
+	assert.NoError(t, err)
+
+	transformer.Repo.On("GetMetadata").Return(metadata)
+	transformer.Repo.On("Count", mock.Anything, qb, &Model{}).Return(1, nil).Once()
+
+	body := `{"page":{"offset":0,"limit":1000000}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1?withTotal=true", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"meta":{"total":1,"count":1,"limit":5},"data":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}]}`, response.Body.String())
+
+	body = `{"page":{"offset":-1,"limit":2}}`
+	response = apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"err":"the offset and limit of a page can not be negative"}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+type DefaultEnvelopeLimitsHandler struct {
+	Handler
+}
+
+func (h DefaultEnvelopeLimitsHandler) GetListLimits() (int, int) {
+	return 10, 5
+}
+
+func TestListHandler_Handle_Limits_DefaultEnvelope(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := DefaultEnvelopeLimitsHandler{
+		Handler: NewTransformer(),
+	}
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.Anything, mock.Anything, &Model{}).Return(1, nil).Once()
+
+	body := `{"page":{"offset":0,"limit":1000000}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"total":1,"limit":5,"results":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}]}`, response.Body.String(), "the clamped limit should be returned")
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+type TimeoutHandler struct {
+	Handler
+}
+
+func (h TimeoutHandler) GetListTimeout() time.Duration {
+	return 10 * time.Millisecond
+}
+
+func TestListHandler_Handle_Timeout(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := TimeoutHandler{
+		Handler: NewTransformer(),
+	}
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.AnythingOfType("*context.timerCtx"), mock.Anything, mock.Anything).After(time.Second).Return(1, nil)
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusGatewayTimeout, response.Code)
+	assert.JSONEq(t, `{"err":"the list query took too long: context deadline exceeded"}`, response.Body.String())
+}
+
+func TestListHandler_Handle_ConfiguredTimeout(t *testing.T) {
+	config := new(cfgMocks.Config)
+	config.On("GetDuration", "api_list_timeout", time.Duration(0)).Return(10 * time.Millisecond)
+
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewListHandler(config, logger, transformer)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.AnythingOfType("*context.timerCtx"), mock.Anything, mock.Anything).After(time.Second).Return(1, nil)
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusGatewayTimeout, response.Code)
+	assert.JSONEq(t, `{"err":"the list query took too long: context deadline exceeded"}`, response.Body.String())
+	config.AssertExpectations(t)
+}
+
+func TestListHandler_Handle_Canceled(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewListHandlerWithInterfaces(logger, transformer, &crud.ListSettings{})
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.Anything, mock.Anything, mock.Anything).Return(0, context.Canceled)
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.JSONEq(t, `{"err":"the list query was canceled: context canceled"}`, response.Body.String())
+}
+
+type ApiViewsHandler struct {
+	Handler
+	outputView *string
+	validator  crud.InputValidator
+}
+
+func (h ApiViewsHandler) GetApiViews(requested string) (string, string) {
+	return "create", requested
+}
+
+func (h ApiViewsHandler) GetInputValidator() crud.InputValidator {
+	return h.validator
+}
+
+func (h ApiViewsHandler) TransformOutput(model db_repo.ModelBased, apiView string) (interface{}, error) {
+	*h.outputView = apiView
+
+	return h.Handler.TransformOutput(model, apiView)
+}
+
+type ApiViewValidator struct {
+	*mocks.InputValidator
+	*mocks.ApiViewInputValidator
+}
+
+func TestCreateHandler_Handle_ApiViews(t *testing.T) {
+	model := &Model{
+		Name: mdl.String("foobar"),
+	}
+
+	validator := ApiViewValidator{
+		InputValidator:        new(mocks.InputValidator),
+		ApiViewInputValidator: new(mocks.ApiViewInputValidator),
+	}
+	validator.ApiViewInputValidator.On("ValidateApiView", mock.Anything, &CreateInput{Name: mdl.String("foobar")}, "create").Return(nil).Once()
+
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := ApiViewsHandler{
+		Handler:    NewTransformer(),
+		outputView: new(string),
+		validator:  validator,
+	}
+
+	transformer.Repo.On("Create", mock.Anything, model).Run(func(args mock.Arguments) {
+		model := args.Get(1).(*Model)
+		model.Id = mdl.Uint(1)
+	}).Return(nil)
+	transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &Model{}).Return(nil)
+
+	handler := crud.NewCreateHandler(logger, transformer)
+
+	body := `{"name": "foobar"}`
+	response := apiserver.HttpTest("POST", "/create", "/create", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, crud.DefaultApiView, *transformer.outputView)
+
+	transformer.Repo.AssertExpectations(t)
+	validator.ApiViewInputValidator.AssertExpectations(t)
+	validator.InputValidator.AssertExpectations(t)
+}
+
+func TestGetApiViews(t *testing.T) {
+	header := http.Header{}
+	header.Set(apiserver.ApiViewKey, "requested")
+
+	input, output := crud.GetApiViews(NewTransformer(), header)
+	assert.Equal(t, "requested", input)
+	assert.Equal(t, "requested", output)
+
+	viewsHandler := new(mocks.ApiViewsHandler)
+	viewsHandler.On("GetApiViews", "requested").Return("in", "").Once()
+
+	input, output = crud.GetApiViews(viewsHandler, header)
+	assert.Equal(t, "in", input)
+	assert.Equal(t, "in", output, "the input view should be used if no output view is declared")
+
+	viewsHandler.On("GetApiViews", "requested").Return("in", "out").Once()
+
+	input, output = crud.GetApiViews(viewsHandler, header)
+	assert.Equal(t, "in", input)
+	assert.Equal(t, "out", output)
+
+	viewsHandler.AssertExpectations(t)
+}
+
+type IteratingRepository struct {
+	*mocks.Repository
+	models []*Model
+	err    error
+}
+
+func (r IteratingRepository) Iterate(_ context.Context, _ *db_repo.QueryBuilder, _ db_repo.ModelBased, callback db_repo.IterateCallback) error {
+	for _, model := range r.models {
+		if err := callback(model); err != nil {
+			return err
+		}
+	}
+
+	return r.err
+}
+
+type StreamingHandler struct {
+	Handler
+	repo IteratingRepository
+}
+
+func (h StreamingHandler) GetRepository() crud.Repository {
+	return h.repo
+}
+
+func TestStreamingListHandler_Handle(t *testing.T) {
+	date, err := time.Parse(time.RFC3339, "2006-01-02T15:04:05Z")
+	assert.NoError(t, err)
+
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := StreamingHandler{
+		Handler: NewTransformer(),
+	}
+	transformer.repo = IteratingRepository{
+		Repository: transformer.Repo,
+		models: []*Model{
+			{Model: db_repo.Model{Id: mdl.Uint(1), Timestamps: db_repo.Timestamps{UpdatedAt: &date, CreatedAt: &date}}, Name: mdl.String("foo")},
+			{Model: db_repo.Model{Id: mdl.Uint(2), Timestamps: db_repo.Timestamps{UpdatedAt: &date, CreatedAt: &date}}, Name: mdl.String("bar")},
+		},
+	}
+	handler := crud.NewStreamingListHandler(logger, transformer)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.Anything, mock.Anything, &Model{}).Return(2, nil)
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "api", response.Header().Get(apiserver.ApiViewKey))
+	assert.JSONEq(t, `{"total":2,"limit":2,"results":[
+		{"id":1,"updatedAt":"2006-01-02T15:04:05Z","createdAt":"2006-01-02T15:04:05Z","name":"foo"},
+		{"id":2,"updatedAt":"2006-01-02T15:04:05Z","createdAt":"2006-01-02T15:04:05Z","name":"bar"}
+	]}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestStreamingListHandler_Handle_ClientDisconnected(t *testing.T) {
+	date, err := time.Parse(time.RFC3339, "2006-01-02T15:04:05Z")
+	assert.NoError(t, err)
+
+	logger := monMocks.NewLoggerMockedUntilLevel(mon.Info)
+	transformer := StreamingHandler{
+		Handler: NewTransformer(),
+	}
+	transformer.repo = IteratingRepository{
+		Repository: transformer.Repo,
+		models: []*Model{
+			{Model: db_repo.Model{Id: mdl.Uint(1), Timestamps: db_repo.Timestamps{UpdatedAt: &date, CreatedAt: &date}}, Name: mdl.String("foo")},
+		},
+		err: context.Canceled,
+	}
+	handler := crud.NewStreamingListHandler(logger, transformer)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Count", mock.Anything, mock.Anything, &Model{}).Return(2, nil)
+
+	body := `{"page":{"offset":0,"limit":2}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"total":2,"limit":2,"results":[{"id":1,"name":"foo","updatedAt":"2006-01-02T15:04:05Z","createdAt":"2006-01-02T15:04:05Z"}`, response.Body.String())
+
+	logger.AssertCalled(t, "WithFields", mon.Fields{
+		mon.FieldEvent: "client_disconnected",
+		"results":      1,
+	})
+	logger.AssertCalled(t, "Infof", "the client disconnected after %d results of the list response were written", 1)
+	transformer.Repo.AssertExpectations(t)
+}
+
+// TransactionalRepository runs the transactions of the mocked repository without a database
+type TransactionalRepository struct {
+	*mocks.Repository
+	transactions int
+	rollbacks    int
+}
+
+func (r *TransactionalRepository) Transaction(ctx context.Context, fn db_repo.TransactionFunc) error {
+	r.transactions++
+
+	err := fn(ctx)
+
+	if err != nil {
+		r.rollbacks++
+	}
+
+	return err
+}
+
+type CascadeHandler struct {
+	Handler
+	repo  *TransactionalRepository
+	rules []crud.CascadeRule
+}
+
+func (h CascadeHandler) GetRepository() crud.Repository {
+	return h.repo
+}
+
+func (h CascadeHandler) GetCascadeRules() []crud.CascadeRule {
+	return h.rules
+}
+
+func newCascadeModel(id uint) *Model {
+	return &Model{
+		Model: db_repo.Model{
+			Id: mdl.Uint(id),
+		},
+	}
+}
+
+func newCascadeTransformer() (CascadeHandler, *mocks.Repository, *mocks.Repository) {
+	transformer := NewTransformer()
+	transformer.Repo.On("Read", mock.Anything, mock.AnythingOfType("*uint"), &Model{}).Run(func(args mock.Arguments) {
+		args.Get(2).(*Model).Id = id1
+	}).Return(nil)
+
+	childQb := db_repo.NewQueryBuilder()
+	childQb.Where("parent_id = ?", uint(1))
+
+	child := new(mocks.Repository)
+	child.On("GetMetadata").Return(db_repo.Metadata{TableName: "children"})
+	child.On("Query", mock.Anything, childQb, mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+		*args.Get(2).(*[]*Model) = []*Model{newCascadeModel(2), newCascadeModel(3)}
+	}).Return(nil).Once()
+
+	grandchild := new(mocks.Repository)
+	grandchild.On("GetMetadata").Return(db_repo.Metadata{TableName: "grandchildren"})
+
+	grandchildrenByChild := map[uint][]*Model{
+		2: {newCascadeModel(4)},
+		3: {},
+	}
+
+	for id, grandchildren := range grandchildrenByChild {
+		qb := db_repo.NewQueryBuilder()
+		qb.Where("child_id = ?", id)
+
+		models := grandchildren
+		grandchild.On("Query", mock.Anything, qb, mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+			*args.Get(2).(*[]*Model) = models
+		}).Return(nil).Once()
+	}
+
+	handler := CascadeHandler{
+		Handler: transformer,
+		repo: &TransactionalRepository{
+			Repository: transformer.Repo,
+		},
+		rules: []crud.CascadeRule{
+			{
+				Repository: child,
+				Model:      &Model{},
+				ForeignKey: "parent_id",
+				Rules: []crud.CascadeRule{
+					{Repository: grandchild, Model: &Model{}, ForeignKey: "child_id"},
+				},
+			},
+		},
+	}
+
+	return handler, child, grandchild
+}
+
+func TestDeleteHandler_Handle_Cascade(t *testing.T) {
+	transformer, child, grandchild := newCascadeTransformer()
+
+	deleted := make([]uint, 0)
+	recordDelete := func(args mock.Arguments) {
+		deleted = append(deleted, *args.Get(1).(*Model).Id)
+	}
+
+	grandchild.On("Delete", mock.Anything, newCascadeModel(4)).Run(recordDelete).Return(nil).Once()
+	child.On("Delete", mock.Anything, newCascadeModel(2)).Run(recordDelete).Return(nil).Once()
+	child.On("Delete", mock.Anything, newCascadeModel(3)).Run(recordDelete).Return(nil).Once()
+	transformer.Repo.On("Delete", mock.Anything, mock.AnythingOfType("*crud_test.Model")).Run(recordDelete).Return(nil).Once()
+
+	handler := crud.NewDeleteHandler(monMocks.NewLoggerMockedAll(), transformer)
+	response := apiserver.HttpTest("DELETE", "/:id", "/1", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, []uint{4, 2, 3, 1}, deleted, "the rows should be deleted in dependency order")
+	assert.Equal(t, 1, transformer.repo.transactions, "the rows should be deleted in a single transaction")
+
+	transformer.Repo.AssertExpectations(t)
+	child.AssertExpectations(t)
+	grandchild.AssertExpectations(t)
+}
+
+func TestDeleteHandler_Handle_CascadeRollback(t *testing.T) {
+	transformer, child, grandchild := newCascadeTransformer()
+
+	grandchild.On("Delete", mock.Anything, newCascadeModel(4)).Return(nil).Once()
+	child.On("Delete", mock.Anything, newCascadeModel(2)).Return(nil).Once()
+	child.On("Delete", mock.Anything, newCascadeModel(3)).Return(fmt.Errorf("lock wait timeout")).Once()
+
+	handler := crud.NewDeleteHandler(monMocks.NewLoggerMockedAll(), transformer)
+	response := apiserver.HttpTest("DELETE", "/:id", "/1", "", handler)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, 1, transformer.repo.rollbacks, "the failed cascade should roll back the transaction")
+
+	transformer.Repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	child.AssertExpectations(t)
+	grandchild.AssertExpectations(t)
+}
+
+func TestDeleteHandler_Handle_CascadeDryRun(t *testing.T) {
+	transformer, child, grandchild := newCascadeTransformer()
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{TableName: "parents"})
+
+	handler := crud.NewDeleteHandler(monMocks.NewLoggerMockedAll(), transformer)
+	response := apiserver.HttpTest("DELETE", "/:id", "/1?dryRun=true", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"deletions":[
+		{"table":"grandchildren","id":4},
+		{"table":"children","id":2},
+		{"table":"children","id":3},
+		{"table":"parents","id":1}
+	]}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+	child.AssertExpectations(t)
+	grandchild.AssertExpectations(t)
+}
+
+type MaxBodySizeHandler struct {
+	Handler
+}
+
+func (h MaxBodySizeHandler) GetMaxBodySize() int64 {
+	return 64
+}
+
+func withConfiguredMaxBodySize(maxBytes int64, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		apiserver.MaxBodySizeMiddleware(maxBytes)(ginCtx)
+		handler(ginCtx)
+	}
+}
+
+func TestCreateHandler_Handle_BodyTooLarge(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := withConfiguredMaxBodySize(16, crud.NewCreateHandler(logger, transformer))
+
+	body := `{"name":"a name which is too long"}`
+	response := apiserver.HttpTest("POST", "/create", "/create", body, handler)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+	assert.JSONEq(t, `{"err":"request body too large"}`, response.Body.String())
+
+	handler = withConfiguredMaxBodySize(16, crud.NewCreateHandler(logger, MaxBodySizeHandler{Handler: transformer}))
+	response = apiserver.HttpTest("POST", "/create", "/create", `{"name":"","padding":"longer than the default limit"}`, handler)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestCreateHandler_Handle_ReturnMinimal(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+
+	transformer.Repo.On("Create", mock.Anything, &Model{Name: mdl.String("foobar")}).Run(func(args mock.Arguments) {
+		model := args.Get(1).(*Model)
+		model.Id = mdl.Uint(3)
+	}).Return(nil)
+
+	handler := crud.NewCreateHandler(logger, transformer)
+
+	body := `{"name": "foobar"}`
+	header := http.Header{crud.HeaderPrefer: []string{"respond-async, return=minimal"}}
+	response := apiserver.HttpTestWithHeader("POST", "/v1/model", "/v1/model", body, header, handler)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, "/v1/model/3", response.Header().Get("Location"))
+	assert.Equal(t, "return=minimal", response.Header().Get(crud.HeaderPreferenceApplied))
+	assert.Empty(t, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+type MinimalHandler struct {
+	Handler
+}
+
+func (h MinimalHandler) GetReturnPreference() string {
+	return crud.ReturnMinimal
+}
+
+func TestUpdateHandler_Handle_ReturnPreference(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := MinimalHandler{
+		Handler: NewTransformer(),
+	}
+
+	transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &Model{}).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*Model)
+		model.Id = mdl.Uint(1)
+		model.Name = mdl.String("updated")
+	}).Return(nil)
+	transformer.Repo.On("Update", mock.Anything, mock.AnythingOfType("*crud_test.Model")).Return(nil)
+
+	handler := crud.NewUpdateHandler(logger, transformer)
+
+	body := `{"name": "updated"}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Empty(t, response.Body.String())
+
+	header := http.Header{crud.HeaderPrefer: []string{"return=representation"}}
+	response = apiserver.HttpTestWithHeader("PUT", "/:id", "/1", body, header, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"id":1,"updatedAt":null,"createdAt":null,"name":"updated"}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+type TaggedModel struct {
+	db_repo.Model `api:"api,admin"`
+	Name          *string `json:"name" api:"*"`
+	Email         string  `json:"email" api:"admin"`
+	Note          string  `json:"note,omitempty" api:"api,admin"`
+	Password      string  `json:"password" api:"-"`
+	Internal      string  `json:"internal"`
+}
+
+func TestTransformOutputByTags(t *testing.T) {
+	model := &TaggedModel{
+		Model: db_repo.Model{
+			Id: mdl.Uint(1),
+		},
+		Name:     mdl.String("foo"),
+		Email:    "foo@example.com",
+		Password: "secret",
+		Internal: "internal",
+	}
+
+	out, err := crud.TransformOutputByTags(model, "api")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Id":        mdl.Uint(1),
+		"UpdatedAt": (*time.Time)(nil),
+		"CreatedAt": (*time.Time)(nil),
+		"name":      mdl.String("foo"),
+	}, out)
+
+	out, err = crud.TransformOutputByTags(model, "admin")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Id":        mdl.Uint(1),
+		"UpdatedAt": (*time.Time)(nil),
+		"CreatedAt": (*time.Time)(nil),
+		"name":      mdl.String("foo"),
+		"email":     "foo@example.com",
+	}, out)
+
+	out, err = crud.TagTransformer{}.TransformOutput(model, "admin")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "email")
+
+	out, err = crud.TransformOutputByTags(model, "unknown")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, out, "an unknown view shouldn't expose any field")
+
+	_, err = crud.TransformOutputByTags((*TaggedModel)(nil), "api")
+	assert.EqualError(t, err, "can not transform a nil model of type *crud_test.TaggedModel")
+}
+
+type PatchModel struct {
+	db_repo.Model
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type PatchInput struct {
+	Name        *string `json:"name" binding:"required"`
+	Description *string `json:"description"`
+}
+
+type PatchHandler struct {
+	Handler
+}
+
+func (h PatchHandler) GetModel() db_repo.ModelBased {
+	return &PatchModel{}
+}
+
+func (h PatchHandler) GetUpdateInput() interface{} {
+	return &PatchInput{}
+}
+
+func (h PatchHandler) TransformUpdate(inp interface{}, model db_repo.ModelBased) (err error) {
+	input := inp.(*PatchInput)
+	m := model.(*PatchModel)
+
+	m.Name = input.Name
+	m.Description = input.Description
+
+	return nil
+}
+
+func (h PatchHandler) TransformOutput(model db_repo.ModelBased, _ string) (interface{}, error) {
+	return model, nil
+}
+
+func newPatchTransformer(stored *PatchModel) PatchHandler {
+	transformer := PatchHandler{
+		Handler: NewTransformer(),
+	}
+
+	transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &PatchModel{}).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*PatchModel)
+		*model = *stored
+	}).Return(nil)
+
+	return transformer
+}
+
+func TestMergePatchHandler_Handle(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	stored := &PatchModel{
+		Model: db_repo.Model{
+			Id: mdl.Uint(1),
+		},
+		Name:        mdl.String("name"),
+		Description: mdl.String("description"),
+	}
+	transformer := newPatchTransformer(stored)
+
+	transformer.Repo.On("Update", mock.Anything, &PatchModel{
+		Model: db_repo.Model{
+			Id: mdl.Uint(1),
+		},
+		Name: mdl.String("name"),
+	}).Run(func(args mock.Arguments) {
+		*stored = *args.Get(1).(*PatchModel)
+	}).Return(nil).Once()
+
+	handler := crud.NewMergePatchHandler(logger, transformer)
+
+	body := `{"description": null, "id": 5}`
+	response := apiserver.HttpTest("PATCH", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"Id":1,"UpdatedAt":null,"CreatedAt":null,"name":"name","description":null}`, response.Body.String(), "null should remove the description and leave the absent name as it is")
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestMergePatchHandler_Handle_ValidationError(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := newPatchTransformer(&PatchModel{
+		Model: db_repo.Model{
+			Id: mdl.Uint(1),
+		},
+		Name: mdl.String("name"),
+	})
+
+	handler := crud.NewMergePatchHandler(logger, transformer)
+
+	body := `{"name": null, "description": "description"}`
+	response := apiserver.HttpTest("PATCH", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.JSONEq(t, `{"errors":[{"field":"Name","rule":"required","message":"field Name failed on the 'required' rule"}]}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestMergePatchHandler_Handle_NotFound(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := PatchHandler{
+		Handler: NewTransformer(),
+	}
+
+	transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), &PatchModel{}).Return(db_repo.NewRecordNotFoundError(1, "patchModel", fmt.Errorf("not found")))
+
+	handler := crud.NewMergePatchHandler(logger, transformer)
+
+	response := apiserver.HttpTest("PATCH", "/:id", "/1", `{"name": "name"}`, handler)
 
+	assert.Equal(t, http.StatusNotFound, response.Code)
 	transformer.Repo.AssertExpectations(t)
 }
@@ -9,8 +9,13 @@ import (
 	"github.com/applike/gosoline/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 )
 
+// DeleteQueryParamDryRun lists the rows a delete request would delete, including the ones of cascade rules, instead
+// of deleting them.
+const DeleteQueryParamDryRun = "dryRun"
+
 type deleteHandler struct {
 	transformer BaseHandler
 	logger      mon.Logger
@@ -26,28 +31,25 @@ func NewDeleteHandler(logger mon.Logger, transformer BaseHandler) gin.HandlerFun
 }
 
 func (dh deleteHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
-	id, valid := apiserver.GetUintFromRequest(request, "id")
+	model, resp, err := readModel(ctx, dh.logger, dh.transformer, request, "delete")
 
-	if !valid {
-		return nil, errors.New("no valid id provided")
+	if resp != nil || err != nil {
+		return resp, err
 	}
 
 	repo := dh.transformer.GetRepository()
-	model := dh.transformer.GetModel()
-
-	err := repo.Read(ctx, id, model)
 
-	var notFound db_repo.RecordNotFoundError
-	if errors.As(err, &notFound) {
-		dh.logger.WithContext(ctx).Warnf("failed to delete model: %s", err)
-		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	if isDryRun(request) {
+		return dh.dryRun(ctx, repo, model)
 	}
 
-	if err != nil {
-		return nil, err
-	}
+	err = db_repo.RunInTransaction(ctx, repo, func(ctx context.Context) error {
+		if _, err := cascadeDelete(ctx, getCascadeRules(dh.transformer), model, false); err != nil {
+			return err
+		}
 
-	err = repo.Delete(ctx, model)
+		return repo.Delete(ctx, model)
+	})
 
 	if errors.Is(err, &validation.Error{}) {
 		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
@@ -57,7 +59,7 @@ func (dh deleteHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return nil, err
 	}
 
-	apiView := GetApiViewFromHeader(request.Header)
+	_, apiView := GetApiViews(dh.transformer, request.Header)
 	out, err := dh.transformer.TransformOutput(model, apiView)
 
 	if err != nil {
@@ -66,3 +68,26 @@ func (dh deleteHandler) Handle(ctx context.Context, request *apiserver.Request)
 
 	return apiserver.NewJsonResponse(out), nil
 }
+
+func (dh deleteHandler) dryRun(ctx context.Context, repo Repository, model db_repo.ModelBased) (*apiserver.Response, error) {
+	deletions, err := cascadeDelete(ctx, getCascadeRules(dh.transformer), model, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deletions = append(deletions, CascadeDeletion{
+		Table: repo.GetMetadata().TableName,
+		Id:    *model.GetId(),
+	})
+
+	return apiserver.NewJsonResponse(CascadeDryRunOutput{
+		Deletions: deletions,
+	}), nil
+}
+
+func isDryRun(request *apiserver.Request) bool {
+	dryRun, err := strconv.ParseBool(request.Url.Query().Get(DeleteQueryParamDryRun))
+
+	return err == nil && dryRun
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jinzhu/inflection"
@@ -73,12 +74,12 @@ type Handler interface {
 	BaseListHandler
 }
 
-func AddCrudHandlers(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler Handler) {
+func AddCrudHandlers(config cfg.Config, logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler Handler) {
 	AddCreateHandler(logger, d, version, basePath, handler)
 	AddReadHandler(logger, d, version, basePath, handler)
 	AddUpdateHandler(logger, d, version, basePath, handler)
 	AddDeleteHandler(logger, d, version, basePath, handler)
-	AddListHandler(logger, d, version, basePath, handler)
+	AddListHandler(config, logger, d, version, basePath, handler)
 }
 
 func AddCreateHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler CreateHandler) {
@@ -88,27 +89,37 @@ func AddCreateHandler(logger mon.Logger, d *apiserver.Definitions, version int,
 }
 
 func AddReadHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler BaseHandler) {
-	_, idPath := getHandlerPaths(version, basePath)
+	path, _ := getHandlerPaths(version, basePath)
+
+	d.GET(getKeyPath(path, handler), NewReadHandler(logger, handler))
+}
+
+// AddReadByKeyHandler reads a model by a unique column other than the primary key, e.g. GET /v1/users/by-email/:email
+// for the column email. The path uses the plural of the base path, as gin doesn't allow the static by-email next to
+// the :id of the read handler.
+func AddReadByKeyHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, column string, handler BaseHandler) {
+	plural := inflection.Plural(basePath)
+	path := fmt.Sprintf("/v%d/%s/by-%s/:%s", version, plural, column, column)
 
-	d.GET(idPath, NewReadHandler(logger, handler))
+	d.GET(path, NewReadByKeyHandler(logger, handler, column))
 }
 
 func AddUpdateHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler UpdateHandler) {
-	_, idPath := getHandlerPaths(version, basePath)
+	path, _ := getHandlerPaths(version, basePath)
 
-	d.PUT(idPath, NewUpdateHandler(logger, handler))
+	d.PUT(getKeyPath(path, handler), NewUpdateHandler(logger, handler))
 }
 
 func AddDeleteHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler BaseHandler) {
-	_, idPath := getHandlerPaths(version, basePath)
+	path, _ := getHandlerPaths(version, basePath)
 
-	d.DELETE(idPath, NewDeleteHandler(logger, handler))
+	d.DELETE(getKeyPath(path, handler), NewDeleteHandler(logger, handler))
 }
 
-func AddListHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler ListHandler) {
+func AddListHandler(config cfg.Config, logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler ListHandler) {
 	plural := inflection.Plural(basePath)
 	path := fmt.Sprintf("/v%d/%s", version, plural)
-	d.POST(path, NewListHandler(logger, handler))
+	d.POST(path, NewListHandler(config, logger, handler))
 }
 
 func getHandlerPaths(version int, basePath string) (path string, idPath string) {
@@ -125,3 +136,36 @@ func GetApiViewFromHeader(reqHeaders http.Header) string {
 
 	return DefaultApiView
 }
+
+// ApiViewsHandler can be implemented by handlers whose input schema differs from their output schema. It maps the
+// api view requested by the client to the view the input is validated with and the view passed to TransformOutput.
+// If one of them is empty, the other one is used for both.
+//
+//go:generate mockery -name ApiViewsHandler
+type ApiViewsHandler interface {
+	GetApiViews(requested string) (input string, output string)
+}
+
+// GetApiViews returns the input and output view of a request, which are both the requested view unless the handler
+// implements ApiViewsHandler.
+func GetApiViews(handler interface{}, reqHeaders http.Header) (input string, output string) {
+	requested := GetApiViewFromHeader(reqHeaders)
+	viewsHandler, ok := handler.(ApiViewsHandler)
+
+	if !ok {
+		return requested, requested
+	}
+
+	input, output = viewsHandler.GetApiViews(requested)
+
+	switch {
+	case input == "" && output == "":
+		return requested, requested
+	case input == "":
+		return output, output
+	case output == "":
+		return input, input
+	}
+
+	return input, output
+}
@@ -0,0 +1,107 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// KeysHandler can be implemented by handlers of tables with a composite primary key, e.g. tenant_id and id. The read,
+// update and delete handlers take every key column from the path parameter with the same name and the routes get one
+// segment per column in the given order, e.g. /v1/user/:tenant_id/:id. Without it, models are read by the id.
+//
+//go:generate mockery -name KeysHandler
+type KeysHandler interface {
+	GetKeyColumns() []string
+}
+
+func getKeyColumns(handler interface{}) []string {
+	if keysHandler, ok := handler.(KeysHandler); ok {
+		return keysHandler.GetKeyColumns()
+	}
+
+	return []string{"id"}
+}
+
+func getKeyPath(path string, handler interface{}) string {
+	columns := getKeyColumns(handler)
+	segments := make([]string, len(columns))
+
+	for i, column := range columns {
+		segments[i] = fmt.Sprintf(":%s", column)
+	}
+
+	return fmt.Sprintf("%s/%s", path, strings.Join(segments, "/"))
+}
+
+// readModel reads the model addressed by the key of the request. Instead of the model, it returns a response with
+// status 400 if a component of the key is missing and with status 404 if there is no such model.
+func readModel(ctx context.Context, logger mon.Logger, transformer BaseHandler, request *apiserver.Request, action string) (db_repo.ModelBased, *apiserver.Response, error) {
+	if _, ok := transformer.(KeysHandler); !ok {
+		return readModelById(ctx, logger, transformer, request, action)
+	}
+
+	repo := transformer.GetRepository()
+	model := transformer.GetModel()
+
+	columns := getKeyColumns(transformer)
+	key := make([]string, 0, len(columns))
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Page(0, 1)
+
+	for _, column := range columns {
+		value, found := request.Params.Get(column)
+
+		if !found || value == "" {
+			return nil, apiserver.GetErrorHandler()(http.StatusBadRequest, fmt.Errorf("no valid %s provided", column)), nil
+		}
+
+		qb.Where(fmt.Sprintf("%s = ?", column), value)
+		key = append(key, fmt.Sprintf("%s %s", column, value))
+	}
+
+	results := reflect.New(reflect.SliceOf(reflect.TypeOf(model)))
+	err := repo.Query(ctx, qb, results.Interface())
+
+	if db_repo.IsNoQueryResultsError(err) || (err == nil && results.Elem().Len() == 0) {
+		logger.WithContext(ctx).Warnf("failed to %s model: there is no model with %s", action, strings.Join(key, ", "))
+		return nil, apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return results.Elem().Index(0).Interface().(db_repo.ModelBased), nil, nil
+}
+
+func readModelById(ctx context.Context, logger mon.Logger, transformer BaseHandler, request *apiserver.Request, action string) (db_repo.ModelBased, *apiserver.Response, error) {
+	id, valid := apiserver.GetUintFromRequest(request, "id")
+
+	if !valid {
+		return nil, apiserver.GetErrorHandler()(http.StatusBadRequest, fmt.Errorf("no valid id provided")), nil
+	}
+
+	repo := transformer.GetRepository()
+	model := transformer.GetModel()
+	err := repo.Read(ctx, id, model)
+
+	var notFound db_repo.RecordNotFoundError
+	if errors.As(err, &notFound) {
+		logger.WithContext(ctx).Warnf("failed to %s model: %s", action, err)
+		return nil, apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return model, nil, nil
+}
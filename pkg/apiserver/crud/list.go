@@ -2,26 +2,112 @@ package crud
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/apiserver/sql"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mdl"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
 )
 
+// Output is the default list envelope. Limit is the page size which was actually applied, see ListMeta.
 type Output struct {
 	Total   int         `json:"total"`
+	Limit   *int        `json:"limit,omitempty"`
 	Results interface{} `json:"results"`
 }
 
+const ListQueryParamWithTotal = "withTotal"
+
+// ListMeta contains everything besides the results a list envelope might want to expose to the client.
+// Count is the number of returned results, Total the number of all rows matching the filter regardless
+// of the pagination. Counting needs an additional query, so Total is only set if the client asked for it
+// with ?withTotal=true. Limit is the page size which was actually applied, it is nil if the results weren't
+// limited.
+type ListMeta struct {
+	Total *int `json:"total,omitempty"`
+	Count int  `json:"count"`
+	Limit *int `json:"limit,omitempty"`
+}
+
+// DataOutput is an alternative list envelope of the shape {data: [...], meta: {...}}.
+type DataOutput struct {
+	Data interface{} `json:"data"`
+	Meta ListMeta    `json:"meta"`
+}
+
+// ListEnvelopeHandler can be implemented by a ListHandler to control the shape of the list response.
+// If it isn't implemented, the results are wrapped into an Output.
+//
+//go:generate mockery -name ListEnvelopeHandler
+type ListEnvelopeHandler interface {
+	WrapListOutput(results interface{}, meta ListMeta, apiView string) (out interface{}, err error)
+}
+
+func DefaultListEnvelope(results interface{}, meta ListMeta) interface{} {
+	return Output{
+		Total:   mdl.EmptyIntIfNil(meta.Total),
+		Limit:   meta.Limit,
+		Results: results,
+	}
+}
+
+func DataListEnvelope(results interface{}, meta ListMeta) interface{} {
+	return DataOutput{
+		Data: results,
+		Meta: meta,
+	}
+}
+
+// ListTimeoutHandler can be implemented by a ListHandler to limit the time the queries of a list request may take.
+// It takes precedence over the timeout of the ListSettings.
+//
+//go:generate mockery -name ListTimeoutHandler
+type ListTimeoutHandler interface {
+	GetListTimeout() time.Duration
+}
+
+// ListSettings apply to all list requests of a handler. Timeout limits the time the queries may take, 0 disables
+// the limit. A deadline of the request context is respected in any case.
+type ListSettings struct {
+	Timeout time.Duration
+}
+
+// ListLimitsHandler can be implemented by a ListHandler to limit the page size of its list requests. The default
+// limit is used for requests without a page, bigger pages are clamped to the max limit. 0 disables the respective
+// limit. The applied page size is returned as limit in the ListMeta and in the default Output envelope.
+//
+//go:generate mockery -name ListLimitsHandler
+type ListLimitsHandler interface {
+	GetListLimits() (defaultLimit int, maxLimit int)
+}
+
 type listHandler struct {
 	transformer ListHandler
 	logger      mon.Logger
+	settings    *ListSettings
+}
+
+// NewListHandler reads the default timeout of the list queries from api_list_timeout.
+func NewListHandler(config cfg.Config, logger mon.Logger, transformer ListHandler) gin.HandlerFunc {
+	settings := &ListSettings{
+		Timeout: config.GetDuration("api_list_timeout", 0),
+	}
+
+	return NewListHandlerWithInterfaces(logger, transformer, settings)
 }
 
-func NewListHandler(logger mon.Logger, transformer ListHandler) gin.HandlerFunc {
+func NewListHandlerWithInterfaces(logger mon.Logger, transformer ListHandler, settings *ListSettings) gin.HandlerFunc {
 	lh := listHandler{
 		transformer: transformer,
 		logger:      logger,
+		settings:    settings,
 	}
 
 	return apiserver.CreateJsonHandler(lh)
@@ -33,6 +119,18 @@ func (lh listHandler) GetInput() interface{} {
 
 func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
 	inp := request.Body.(*sql.Input)
+	limit, err := applyListLimits(lh.transformer, inp)
+
+	if err != nil {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+	}
+
+	_, customEnvelope := lh.transformer.(ListEnvelopeHandler)
+	countTotal, err := withTotal(request, !customEnvelope)
+
+	if err != nil {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+	}
 
 	repo := lh.transformer.GetRepository()
 	metadata := repo.GetMetadata()
@@ -44,23 +142,51 @@ func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 		return nil, err
 	}
 
-	apiView := GetApiViewFromHeader(request.Header)
-	results, err := lh.transformer.List(ctx, qb, apiView)
+	_, apiView := GetApiViews(lh.transformer, request.Header)
 
-	if err != nil {
-		return nil, err
+	ctx, cancel := withListTimeout(ctx, lh.transformer, lh.settings.Timeout)
+	defer cancel()
+
+	var results interface{}
+	meta := ListMeta{
+		Limit: limit,
 	}
 
-	model := lh.transformer.GetModel()
-	total, err := repo.Count(ctx, qb, model)
+	err = runWithDeadline(ctx, func() error {
+		var err error
+
+		if results, err = lh.transformer.List(ctx, qb, apiView); err != nil {
+			return err
+		}
+
+		meta.Count = countResults(results)
+
+		if !countTotal {
+			return nil
+		}
+
+		total, err := repo.Count(ctx, qb, lh.transformer.GetModel())
+		meta.Total = &total
+
+		return err
+	})
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apiserver.GetErrorHandler()(http.StatusGatewayTimeout, fmt.Errorf("the list query took too long: %w", err)), nil
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return apiserver.GetErrorHandler()(http.StatusServiceUnavailable, fmt.Errorf("the list query was canceled: %w", err)), nil
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	out := Output{
-		Total:   total,
-		Results: results,
+	out, err := lh.wrapOutput(results, meta, apiView)
+
+	if err != nil {
+		return nil, err
 	}
 
 	resp := apiserver.NewJsonResponse(out)
@@ -68,3 +194,114 @@ func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 
 	return resp, nil
 }
+
+// withListTimeout applies the timeout of the handler, see ListTimeoutHandler, or the given default timeout.
+func withListTimeout(ctx context.Context, handler ListHandler, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeoutHandler, ok := handler.(ListTimeoutHandler); ok {
+		timeout = timeoutHandler.GetListTimeout()
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// runWithDeadline returns as soon as the deadline of the context is exceeded. The orm doesn't pass the context to
+// the database driver, but the repository hands the deadline to mysql as maximum execution time of the select, so
+// the query is aborted by the server and the goroutine waiting for it ends shortly after.
+func runWithDeadline(ctx context.Context, f func() error) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return f()
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- f()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// applyListLimits rejects negative pages and applies the default and the maximum page size of the handler to the
+// input. It returns the limit the query will use, nil if it is unlimited.
+func applyListLimits(handler interface{}, inp *sql.Input) (*int, error) {
+	var defaultListLimit, maxListLimit int
+
+	if limitsHandler, ok := handler.(ListLimitsHandler); ok {
+		defaultListLimit, maxListLimit = limitsHandler.GetListLimits()
+	}
+
+	if inp.Page == nil {
+		if defaultListLimit <= 0 {
+			return nil, nil
+		}
+
+		inp.Page = &sql.Page{
+			Limit: defaultListLimit,
+		}
+
+		return mdl.Int(inp.Page.Limit), nil
+	}
+
+	if inp.Page.Offset < 0 || inp.Page.Limit < 0 {
+		return nil, fmt.Errorf("the offset and limit of a page can not be negative")
+	}
+
+	if maxListLimit > 0 && inp.Page.Limit > maxListLimit {
+		inp.Page.Limit = maxListLimit
+	}
+
+	return mdl.Int(inp.Page.Limit), nil
+}
+
+func (lh listHandler) wrapOutput(results interface{}, meta ListMeta, apiView string) (interface{}, error) {
+	if envelope, ok := lh.transformer.(ListEnvelopeHandler); ok {
+		return envelope.WrapListOutput(results, meta, apiView)
+	}
+
+	return DefaultListEnvelope(results, meta), nil
+}
+
+// withTotal reports if the client asked for the total count of matching rows with ?withTotal=true. Without the
+// parameter, the given default applies: the total is part of the Output envelope ever since, so it is counted for
+// it unless the client skips it with ?withTotal=false, while it is opt-in for custom envelopes.
+func withTotal(request *apiserver.Request, defaultValue bool) (bool, error) {
+	value := request.Url.Query().Get(ListQueryParamWithTotal)
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return false, fmt.Errorf("the value %s of %s is not a boolean", value, ListQueryParamWithTotal)
+	}
+
+	return enabled, nil
+}
+
+func countResults(results interface{}) int {
+	rv := reflect.ValueOf(results)
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rv.Len()
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return 0
+		}
+
+		return countResults(rv.Elem().Interface())
+	}
+
+	return 0
+}
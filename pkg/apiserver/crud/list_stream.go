@@ -0,0 +1,192 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/apiserver/sql"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/inflection"
+	"net/http"
+)
+
+const (
+	// the response is flushed to the client after this many results
+	streamingListFlushInterval = 100
+	// logged as event if the client went away before the response was complete
+	streamingListEventClientDisconnected = "client_disconnected"
+)
+
+// IteratingRepository is implemented by repositories which can read the rows of a query one by one, like the
+// repositories of db_repo. The repository of a streaming list handler has to implement it.
+//
+//go:generate mockery -name IteratingRepository
+type IteratingRepository interface {
+	Iterate(ctx context.Context, qb *db_repo.QueryBuilder, model db_repo.ModelBased, callback db_repo.IterateCallback) error
+}
+
+type streamingListHandler struct {
+	transformer ListHandler
+	logger      mon.Logger
+}
+
+// NewStreamingListHandler returns a list handler which writes every result to the client as soon as it was read
+// from the database instead of loading all of them into memory first. The response has the same shape as the one
+// of the DefaultListEnvelope. The List method of the transformer is not used, every row is passed to
+// TransformOutput instead. Custom envelopes are not supported.
+func NewStreamingListHandler(logger mon.Logger, transformer ListHandler) gin.HandlerFunc {
+	lh := streamingListHandler{
+		transformer: transformer,
+		logger:      logger,
+	}
+
+	return apiserver.CreateStreamHandler(lh)
+}
+
+func AddStreamingListHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler ListHandler) {
+	plural := inflection.Plural(basePath)
+	path := fmt.Sprintf("/v%d/%s", version, plural)
+	d.POST(path, NewStreamingListHandler(logger, handler))
+}
+
+func (lh streamingListHandler) GetInput() interface{} {
+	return sql.NewInput()
+}
+
+func (lh streamingListHandler) Handle(ginCtx *gin.Context, ctx context.Context, request *apiserver.Request) error {
+	inp := request.Body.(*sql.Input)
+	limit, err := applyListLimits(lh.transformer, inp)
+
+	if err != nil {
+		resp := apiserver.GetErrorHandler()(http.StatusBadRequest, err)
+		ginCtx.JSON(resp.StatusCode, resp.Body)
+
+		return nil
+	}
+
+	countTotal, err := withTotal(request, true)
+
+	if err != nil {
+		resp := apiserver.GetErrorHandler()(http.StatusBadRequest, err)
+		ginCtx.JSON(resp.StatusCode, resp.Body)
+
+		return nil
+	}
+
+	repo := lh.transformer.GetRepository()
+	iteratingRepo, ok := repo.(IteratingRepository)
+
+	if !ok {
+		return fmt.Errorf("the repository %T can not be used for streaming as it doesn't implement IteratingRepository", repo)
+	}
+
+	lqb := sql.NewOrmQueryBuilder(repo.GetMetadata())
+	qb, err := lqb.Build(inp)
+
+	if err != nil {
+		return err
+	}
+
+	_, apiView := GetApiViews(lh.transformer, request.Header)
+	model := lh.transformer.GetModel()
+	total := 0
+
+	if countTotal {
+		if total, err = repo.Count(ctx, qb, model); err != nil {
+			if clientDisconnected(ctx, err) {
+				return lh.abort(ginCtx, ctx, err, 0)
+			}
+
+			return err
+		}
+	}
+
+	ginCtx.Header(apiserver.ApiViewKey, apiView)
+	ginCtx.Header("Content-Type", apiserver.ContentTypeJson)
+	ginCtx.Status(http.StatusOK)
+
+	writer := ginCtx.Writer
+	count := 0
+
+	if _, err = fmt.Fprint(writer, streamingEnvelopeStart(total, limit)); err != nil {
+		return lh.abort(ginCtx, ctx, err, count)
+	}
+
+	err = iteratingRepo.Iterate(ctx, qb, model, func(model db_repo.ModelBased) error {
+		out, err := lh.transformer.TransformOutput(model, apiView)
+
+		if err != nil {
+			return err
+		}
+
+		encoded, err := json.Marshal(out)
+
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			encoded = append([]byte(","), encoded...)
+		}
+
+		if _, err = writer.Write(encoded); err != nil {
+			return err
+		}
+
+		if count++; count%streamingListFlushInterval == 0 {
+			writer.Flush()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return lh.abort(ginCtx, ctx, err, count)
+	}
+
+	if _, err = writer.Write([]byte("]}")); err != nil {
+		return lh.abort(ginCtx, ctx, err, count)
+	}
+
+	return nil
+}
+
+// abort stops the response after the status was sent already. The envelope isn't closed, so the client gets
+// invalid json instead of an incomplete list looking like a complete one. If the client disconnected, there is
+// nobody left to read the response and only the client_disconnected event is logged instead of an error.
+func (lh streamingListHandler) abort(ginCtx *gin.Context, ctx context.Context, err error, count int) error {
+	logger := lh.logger.WithContext(ctx)
+	ginCtx.Abort()
+
+	if !clientDisconnected(ctx, err) {
+		logger.Error(err, "can not stream the list response")
+
+		return nil
+	}
+
+	logger.WithFields(mon.Fields{
+		mon.FieldEvent: streamingListEventClientDisconnected,
+		"results":      count,
+	}).Infof("the client disconnected after %d results of the list response were written", count)
+
+	return nil
+}
+
+// clientDisconnected reports whether the request context was canceled, which happens as soon as the client closes
+// the connection. The repository stops iterating with the error of the context in this case and closes its rows.
+func clientDisconnected(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.Canceled)
+}
+
+// streamingEnvelopeStart opens the Output envelope up to the results, the limit is only part of it if it was applied.
+func streamingEnvelopeStart(total int, limit *int) string {
+	if limit == nil {
+		return fmt.Sprintf(`{"total":%d,"results":[`, total)
+	}
+
+	return fmt.Sprintf(`{"total":%d,"limit":%d,"results":[`, total, *limit)
+}
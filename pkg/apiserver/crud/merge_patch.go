@@ -0,0 +1,212 @@
+package crud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/db"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+)
+
+// mergePatch keeps the members of a JSON merge patch undecoded, so an explicit null can be told apart from a value
+// and from an absent member.
+type mergePatch map[string]json.RawMessage
+
+type mergePatchHandler struct {
+	transformer UpdateHandler
+	logger      mon.Logger
+}
+
+// NewMergePatchHandler returns a handler updating a model with a JSON merge patch (RFC 7386). The patch is applied to
+// the model as returned by TransformOutput for the input view: a member with a value sets the field, null removes it
+// and absent members are left as they are. The patched document is decoded into the update input, so removed fields
+// get their zero value and fields only contained in the output, like the id, are ignored. Afterwards the input is
+// validated and passed to TransformUpdate like for the update handler.
+func NewMergePatchHandler(logger mon.Logger, transformer UpdateHandler) gin.HandlerFunc {
+	mh := mergePatchHandler{
+		transformer: transformer,
+		logger:      logger,
+	}
+
+	return withMaxBodySize(transformer, apiserver.CreateMultipleBindingsHandler(mh))
+}
+
+// AddMergePatchHandler adds the merge patch handler as PATCH /v1/<basePath>/:id
+func AddMergePatchHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler UpdateHandler) {
+	path, _ := getHandlerPaths(version, basePath)
+
+	d.PATCH(getKeyPath(path, handler), NewMergePatchHandler(logger, handler))
+}
+
+func (mh mergePatchHandler) GetInput() interface{} {
+	return &mergePatch{}
+}
+
+func (mh mergePatchHandler) GetBindings() []binding.Binding {
+	return []binding.Binding{jsonDecodeBinding{}}
+}
+
+func (mh mergePatchHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	inputView, outputView := GetApiViews(mh.transformer, request.Header)
+	patch := *request.Body.(*mergePatch)
+
+	model, resp, err := readModel(ctx, mh.logger, mh.transformer, request, "patch")
+
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
+	input, err := mh.patchInput(model, inputView, patch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateInput(ctx, mh.transformer, input, inputView)
+
+	var inputErr *InputValidationError
+	if errors.As(err, &inputErr) {
+		return newInputValidationErrorResponse(inputErr), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = mh.transformer.TransformUpdate(input, model)
+
+	if modelNotChanged(err) {
+		return apiserver.NewStatusResponse(http.StatusNotModified), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	repo := mh.transformer.GetRepository()
+	err = repo.Update(ctx, model)
+
+	if db.IsDuplicateEntryError(err) {
+		return apiserver.NewStatusResponse(http.StatusConflict), nil
+	}
+
+	if errors.Is(err, &validation.Error{}) {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if getReturnPreference(mh.transformer, request.Header) == ReturnMinimal {
+		return newMinimalResponse(http.StatusNoContent, ""), nil
+	}
+
+	reload, resp, err := readModel(ctx, mh.logger, mh.transformer, request, "reload")
+
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
+	out, err := mh.transformer.TransformOutput(reload, outputView)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiserver.NewJsonResponse(out), nil
+}
+
+// patchInput applies the patch to the current document of the model and decodes the result into a new update input
+func (mh mergePatchHandler) patchInput(model db_repo.ModelBased, inputView string, patch mergePatch) (interface{}, error) {
+	current, err := mh.transformer.TransformOutput(model, inputView)
+
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(current)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not encode the model to apply the merge patch: %w", err)
+	}
+
+	document := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	if err = decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("the model has to be encoded as json object to apply a merge patch: %w", err)
+	}
+
+	if document, err = applyMergePatch(document, patch); err != nil {
+		return nil, err
+	}
+
+	if encoded, err = json.Marshal(document); err != nil {
+		return nil, fmt.Errorf("can not encode the patched model: %w", err)
+	}
+
+	input := mh.transformer.GetUpdateInput()
+
+	if err = json.Unmarshal(encoded, input); err != nil {
+		return nil, fmt.Errorf("can not decode the patched model into the update input: %w", err)
+	}
+
+	return input, nil
+}
+
+// applyMergePatch merges the patch into the target as described by RFC 7386. Nested objects are merged recursively,
+// any other value replaces the member of the target.
+func applyMergePatch(target map[string]interface{}, patch mergePatch) (map[string]interface{}, error) {
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+
+	for key, raw := range patch {
+		raw = bytes.TrimSpace(raw)
+
+		if bytes.Equal(raw, []byte("null")) {
+			delete(target, key)
+			continue
+		}
+
+		if len(raw) > 0 && raw[0] == '{' {
+			nested := mergePatch{}
+
+			if err := json.Unmarshal(raw, &nested); err != nil {
+				return nil, fmt.Errorf("can not decode the merge patch of %s: %w", key, err)
+			}
+
+			current, _ := target[key].(map[string]interface{})
+			merged, err := applyMergePatch(current, nested)
+
+			if err != nil {
+				return nil, err
+			}
+
+			target[key] = merged
+			continue
+		}
+
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("can not decode the merge patch of %s: %w", key, err)
+		}
+
+		target[key] = value
+	}
+
+	return target, nil
+}
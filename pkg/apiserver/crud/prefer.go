@@ -0,0 +1,76 @@
+package crud
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderPrefer            = "Prefer"
+	HeaderPreferenceApplied = "Preference-Applied"
+	ReturnMinimal           = "minimal"
+	ReturnRepresentation    = "representation"
+)
+
+// ReturnPreferenceHandler can be implemented by a create, update or upsert handler to choose what it returns to
+// clients which don't send a Prefer header: ReturnRepresentation, the transformed model, or ReturnMinimal, no body at
+// all. Without it, the handlers return the representation.
+//
+//go:generate mockery -name ReturnPreferenceHandler
+type ReturnPreferenceHandler interface {
+	GetReturnPreference() string
+}
+
+// getReturnPreference returns the preference requested with "Prefer: return=minimal" or "Prefer: return=representation"
+// (RFC 7240) or the default of the handler.
+func getReturnPreference(handler interface{}, header http.Header) string {
+	for _, value := range header.Values(HeaderPrefer) {
+		for _, preference := range strings.Split(value, ",") {
+			preference = strings.TrimSpace(strings.SplitN(preference, ";", 2)[0])
+			parts := strings.SplitN(preference, "=", 2)
+
+			if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), "return") {
+				continue
+			}
+
+			switch strings.Trim(strings.TrimSpace(parts[1]), `"`) {
+			case ReturnMinimal:
+				return ReturnMinimal
+			case ReturnRepresentation:
+				return ReturnRepresentation
+			}
+		}
+	}
+
+	if preferenceHandler, ok := handler.(ReturnPreferenceHandler); ok && preferenceHandler.GetReturnPreference() == ReturnMinimal {
+		return ReturnMinimal
+	}
+
+	return ReturnRepresentation
+}
+
+// newMinimalResponse returns a response without a body, with a Location header if location is not empty.
+func newMinimalResponse(statusCode int, location string) *apiserver.Response {
+	resp := apiserver.NewStatusResponse(statusCode)
+	resp.AddHeader(HeaderPreferenceApplied, fmt.Sprintf("return=%s", ReturnMinimal))
+
+	if location != "" {
+		resp.AddHeader("Location", location)
+	}
+
+	return resp
+}
+
+// getCreatedLocation returns the path of a model created by a POST to the request path. Models with composite keys
+// are read with more than their id, so there is no location for them.
+func getCreatedLocation(handler interface{}, request *apiserver.Request, id *uint) string {
+	columns := getKeyColumns(handler)
+
+	if len(columns) != 1 || columns[0] != "id" || id == nil || request.Url == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%d", strings.TrimSuffix(request.Url.Path, "/"), *id)
+}
@@ -6,8 +6,6 @@ import (
 	db_repo "github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
-	"github.com/pkg/errors"
-	"net/http"
 )
 
 type readHandler struct {
@@ -25,32 +23,26 @@ func NewReadHandler(logger mon.Logger, transformer BaseHandler) gin.HandlerFunc
 }
 
 func (rh readHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
-	id, valid := apiserver.GetUintFromRequest(request, "id")
+	model, resp, err := readModel(ctx, rh.logger, rh.transformer, request, "read")
 
-	if !valid {
-		return nil, errors.New("no valid id provided")
+	if resp != nil || err != nil {
+		return resp, err
 	}
 
-	repo := rh.transformer.GetRepository()
-	model := rh.transformer.GetModel()
-	err := repo.Read(ctx, id, model)
-
-	var notFound db_repo.RecordNotFoundError
-	if errors.As(err, &notFound) {
-		rh.logger.WithContext(ctx).Warnf("failed to read model: %s", err)
-		return apiserver.NewStatusResponse(http.StatusNotFound), nil
-	}
-
-	if err != nil {
-		return nil, err
-	}
+	return transformReadOutput(rh.transformer, model, request)
+}
 
-	apiView := GetApiViewFromHeader(request.Header)
-	out, err := rh.transformer.TransformOutput(model, apiView)
+// transformReadOutput returns the transformed model with its update time as Last-Modified header. Clients sending a
+// matching If-Modified-Since header get 304 without a body instead.
+func transformReadOutput(transformer BaseHandler, model db_repo.ModelBased, request *apiserver.Request) (*apiserver.Response, error) {
+	return withLastModified(model, request, func() (*apiserver.Response, error) {
+		_, apiView := GetApiViews(transformer, request.Header)
+		out, err := transformer.TransformOutput(model, apiView)
 
-	if err != nil {
-		return nil, err
-	}
+		if err != nil {
+			return nil, err
+		}
 
-	return apiserver.NewJsonResponse(out), nil
+		return apiserver.NewJsonResponse(out), nil
+	})
 }
@@ -0,0 +1,74 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+)
+
+type readByKeyHandler struct {
+	transformer BaseHandler
+	logger      mon.Logger
+	column      string
+	param       string
+}
+
+// NewReadByKeyHandler reads a single model by the value of a unique column, e.g. a slug or an email, taken from the
+// path parameter with the same name. It answers with 404 if there is no model with that value and with 409 if there
+// is more than one.
+func NewReadByKeyHandler(logger mon.Logger, transformer BaseHandler, column string) gin.HandlerFunc {
+	rh := readByKeyHandler{
+		transformer: transformer,
+		logger:      logger,
+		column:      column,
+		param:       column,
+	}
+
+	return apiserver.CreateHandler(rh)
+}
+
+func (rh readByKeyHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	value, found := request.Params.Get(rh.param)
+
+	if !found || value == "" {
+		return nil, fmt.Errorf("no valid %s provided", rh.param)
+	}
+
+	repo := rh.transformer.GetRepository()
+	model := rh.transformer.GetModel()
+
+	// two rows are enough to tell a unique match from a duplicate one
+	qb := db_repo.NewQueryBuilder()
+	qb.Where(fmt.Sprintf("%s = ?", rh.column), value)
+	qb.Page(0, 2)
+
+	results := reflect.New(reflect.SliceOf(reflect.TypeOf(model)))
+	err := repo.Query(ctx, qb, results.Interface())
+
+	if db_repo.IsNoQueryResultsError(err) {
+		rh.logger.WithContext(ctx).Warnf("failed to read model by %s: %s", rh.column, err)
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch count := results.Elem().Len(); {
+	case count == 0:
+		rh.logger.WithContext(ctx).Warnf("failed to read model by %s: there is no model with %s %s", rh.column, rh.column, value)
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	case count > 1:
+		err = fmt.Errorf("there is more than one model with %s %s", rh.column, value)
+		return apiserver.GetErrorHandler()(http.StatusConflict, err), nil
+	}
+
+	model = results.Elem().Index(0).Interface().(db_repo.ModelBased)
+
+	return transformReadOutput(rh.transformer, model, request)
+}
@@ -0,0 +1,179 @@
+package crud
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"reflect"
+	"strings"
+)
+
+const (
+	apiViewTag    = "api"
+	apiViewAll    = "*"
+	apiViewHidden = "-"
+)
+
+// TagTransformer can be embedded into a handler to implement TransformOutput with TransformOutputByTags. Define
+// TransformOutput on the handler itself to override it.
+type TagTransformer struct{}
+
+func (TagTransformer) TransformOutput(model db_repo.ModelBased, apiView string) (interface{}, error) {
+	return TransformOutputByTags(model, apiView)
+}
+
+// TransformOutputByTags returns the fields of the model which belong to the api view as a map by their json names.
+// The views of a field are listed in its api tag, e.g. `api:"api,admin"`. A field tagged with `api:"*"` belongs to
+// every view of the model, one without a tag or tagged with `api:"-"` to none. The api tag of an embedded struct
+// applies to all of its fields without an own tag, e.g. to expose the id and timestamps of db_repo.Model. A view not
+// listed by any field of the model returns an empty object instead of all fields.
+func TransformOutputByTags(model interface{}, apiView string) (interface{}, error) {
+	value := reflect.ValueOf(model)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, fmt.Errorf("can not transform a nil model of type %T", model)
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can not transform a model of type %T, it has to be a struct", model)
+	}
+
+	out := make(map[string]interface{})
+
+	if !hasApiView(value.Type(), apiView, nil) {
+		return out, nil
+	}
+
+	transformFieldsByTags(value, apiView, nil, out)
+
+	return out, nil
+}
+
+func transformFieldsByTags(value reflect.Value, apiView string, inherited []string, out map[string]interface{}) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		views := getApiViews(field, inherited)
+
+		if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct {
+			if embedded, ok := getEmbeddedStruct(fieldValue); ok {
+				transformFieldsByTags(embedded, apiView, views, out)
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" || !containsApiView(views, apiView) {
+			continue
+		}
+
+		name, omitEmpty, ok := getJsonName(field)
+
+		if !ok || (omitEmpty && fieldValue.IsZero()) {
+			continue
+		}
+
+		out[name] = fieldValue.Interface()
+	}
+}
+
+// hasApiView returns whether any field of the type belongs to the view, either by name or by a *.
+func hasApiView(typ reflect.Type, apiView string, inherited []string) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		views := getApiViews(field, inherited)
+
+		if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct {
+			if hasApiView(indirectType(field.Type), apiView, views) {
+				return true
+			}
+
+			continue
+		}
+
+		for _, view := range views {
+			if view == apiView {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func getApiViews(field reflect.StructField, inherited []string) []string {
+	tag, ok := field.Tag.Lookup(apiViewTag)
+
+	if !ok {
+		return inherited
+	}
+
+	if tag == apiViewHidden {
+		return nil
+	}
+
+	views := strings.Split(tag, ",")
+
+	for i := range views {
+		views[i] = strings.TrimSpace(views[i])
+	}
+
+	return views
+}
+
+func containsApiView(views []string, apiView string) bool {
+	for _, view := range views {
+		if view == apiView || view == apiViewAll {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getEmbeddedStruct dereferences the value of an embedded struct, it returns false for a nil pointer.
+func getEmbeddedStruct(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	return value, true
+}
+
+func getJsonName(field reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	tag := field.Tag.Get("json")
+
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+
+	if name == "" {
+		name = field.Name
+	}
+
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, true
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
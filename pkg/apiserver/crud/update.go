@@ -5,10 +5,10 @@ import (
 	"errors"
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/db"
-	db_repo "github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/validation"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"net/http"
 )
 
@@ -23,34 +23,36 @@ func NewUpdateHandler(logger mon.Logger, transformer UpdateHandler) gin.HandlerF
 		logger:      logger,
 	}
 
-	return apiserver.CreateJsonHandler(uh)
+	return withMaxBodySize(transformer, apiserver.CreateMultipleBindingsHandler(uh))
 }
 
 func (uh updateHandler) GetInput() interface{} {
 	return uh.transformer.GetUpdateInput()
 }
 
-func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
-	id, valid := apiserver.GetUintFromRequest(request, "id")
-
-	if !valid {
-		return nil, errors.New("no valid id provided")
-	}
+func (uh updateHandler) GetBindings() []binding.Binding {
+	return []binding.Binding{jsonDecodeBinding{}}
+}
 
-	repo := uh.transformer.GetRepository()
-	model := uh.transformer.GetModel()
-	err := repo.Read(ctx, id, model)
+func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	inputView, outputView := GetApiViews(uh.transformer, request.Header)
+	err := validateInput(ctx, uh.transformer, request.Body, inputView)
 
-	var notFound db_repo.RecordNotFoundError
-	if errors.As(err, &notFound) {
-		uh.logger.WithContext(ctx).Warnf("failed to update model: %s", err)
-		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	var inputErr *InputValidationError
+	if errors.As(err, &inputErr) {
+		return newInputValidationErrorResponse(inputErr), nil
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	model, resp, err := readModel(ctx, uh.logger, uh.transformer, request, "update")
+
+	if resp != nil || err != nil {
+		return resp, err
+	}
+
 	err = uh.transformer.TransformUpdate(request.Body, model)
 
 	if modelNotChanged(err) {
@@ -61,6 +63,7 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return nil, err
 	}
 
+	repo := uh.transformer.GetRepository()
 	err = repo.Update(ctx, model)
 
 	if db.IsDuplicateEntryError(err) {
@@ -75,15 +78,17 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return nil, err
 	}
 
-	reload := uh.transformer.GetModel()
-	err = repo.Read(ctx, model.GetId(), reload)
+	if getReturnPreference(uh.transformer, request.Header) == ReturnMinimal {
+		return newMinimalResponse(http.StatusNoContent, ""), nil
+	}
 
-	if err != nil {
-		return nil, err
+	reload, resp, err := readModel(ctx, uh.logger, uh.transformer, request, "reload")
+
+	if resp != nil || err != nil {
+		return resp, err
 	}
 
-	apiView := GetApiViewFromHeader(request.Header)
-	out, err := uh.transformer.TransformOutput(reload, apiView)
+	out, err := uh.transformer.TransformOutput(reload, outputView)
 
 	if err != nil {
 		return nil, err
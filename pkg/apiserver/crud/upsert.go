@@ -0,0 +1,161 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/db"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+)
+
+//go:generate mockery -name BaseUpsertHandler
+type BaseUpsertHandler interface {
+	GetUpsertInput() interface{}
+	// TransformUpsert sets all fields of the model from the input. The model is either the stored one or a new one
+	// with the id of the request.
+	TransformUpsert(input interface{}, model db_repo.ModelBased) (err error)
+}
+
+//go:generate mockery -name UpsertHandler
+type UpsertHandler interface {
+	BaseHandler
+	BaseUpsertHandler
+}
+
+type upsertHandler struct {
+	transformer UpsertHandler
+	logger      mon.Logger
+}
+
+// NewUpsertHandler returns a handler creating the model with the id of the request if it doesn't exist yet and
+// replacing it otherwise. It answers with 201 for a created model and 200 for a replaced one. The model has to
+// implement db_repo.IdSettable to be created with the given id.
+func NewUpsertHandler(logger mon.Logger, transformer UpsertHandler) gin.HandlerFunc {
+	uh := upsertHandler{
+		transformer: transformer,
+		logger:      logger,
+	}
+
+	return withMaxBodySize(transformer, apiserver.CreateMultipleBindingsHandler(uh))
+}
+
+// AddUpsertHandler adds the upsert handler as PUT /v1/<basePath>/:id. Add it instead of the update handler, as both
+// use the same route.
+func AddUpsertHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler UpsertHandler) {
+	_, idPath := getHandlerPaths(version, basePath)
+
+	d.PUT(idPath, NewUpsertHandler(logger, handler))
+}
+
+func (uh upsertHandler) GetInput() interface{} {
+	return uh.transformer.GetUpsertInput()
+}
+
+func (uh upsertHandler) GetBindings() []binding.Binding {
+	return []binding.Binding{jsonDecodeBinding{}}
+}
+
+func (uh upsertHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	id, valid := apiserver.GetUintFromRequest(request, "id")
+
+	if !valid {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, fmt.Errorf("no valid id provided")), nil
+	}
+
+	inputView, outputView := GetApiViews(uh.transformer, request.Header)
+	err := validateInput(ctx, uh.transformer, request.Body, inputView)
+
+	var inputErr *InputValidationError
+	if errors.As(err, &inputErr) {
+		return newInputValidationErrorResponse(inputErr), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	repo := uh.transformer.GetRepository()
+	model := uh.transformer.GetModel()
+	err = repo.Read(ctx, id, model)
+
+	var notFound db_repo.RecordNotFoundError
+	created := errors.As(err, &notFound)
+
+	if err != nil && !created {
+		return nil, err
+	}
+
+	if created {
+		model = uh.transformer.GetModel()
+		settable, ok := model.(db_repo.IdSettable)
+
+		if !ok {
+			return nil, fmt.Errorf("can not create a model of type %T with the given id as it doesn't implement db_repo.IdSettable", model)
+		}
+
+		settable.SetId(id)
+	}
+
+	err = uh.transformer.TransformUpsert(request.Body, model)
+
+	if modelNotChanged(err) {
+		return apiserver.NewStatusResponse(http.StatusNotModified), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		err = repo.Create(ctx, model)
+	} else {
+		err = repo.Update(ctx, model)
+	}
+
+	if db.IsDuplicateEntryError(err) {
+		return apiserver.NewStatusResponse(http.StatusConflict), nil
+	}
+
+	if errors.Is(err, &validation.Error{}) {
+		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if getReturnPreference(uh.transformer, request.Header) == ReturnMinimal {
+		if created {
+			return newMinimalResponse(http.StatusCreated, request.Url.Path), nil
+		}
+
+		return newMinimalResponse(http.StatusNoContent, ""), nil
+	}
+
+	reload := uh.transformer.GetModel()
+	err = repo.Read(ctx, id, reload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := uh.transformer.TransformOutput(reload, outputView)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := apiserver.NewJsonResponse(out)
+
+	if created {
+		resp.StatusCode = http.StatusCreated
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,147 @@
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/gin-gonic/gin/binding"
+	"gopkg.in/go-playground/validator.v8"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// InputValidationError is returned by an InputValidator if the bound input of a create or update call is invalid.
+type InputValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *InputValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i := 0; i < len(e.Errors); i++ {
+		messages[i] = e.Errors[i].Message
+	}
+
+	return fmt.Sprintf("input validation: %s", strings.Join(messages, "; "))
+}
+
+func (e *InputValidationError) Is(err error) bool {
+	_, ok := err.(*InputValidationError)
+
+	return ok
+}
+
+//go:generate mockery -name InputValidator
+type InputValidator interface {
+	Validate(ctx context.Context, input interface{}) error
+}
+
+// ApiViewInputValidator can be implemented by an InputValidator to validate the input against the input view of the
+// request, see ApiViewsHandler. The create and update handlers prefer it over Validate.
+//
+//go:generate mockery -name ApiViewInputValidator
+type ApiViewInputValidator interface {
+	ValidateApiView(ctx context.Context, input interface{}, apiView string) error
+}
+
+type structTagValidator struct{}
+
+// NewStructTagValidator validates the input with the binding tags of the input struct, the same way gin does it while binding.
+func NewStructTagValidator() InputValidator {
+	return structTagValidator{}
+}
+
+func (v structTagValidator) Validate(_ context.Context, input interface{}) error {
+	err := binding.Validator.ValidateStruct(input)
+
+	if err == nil {
+		return nil
+	}
+
+	validationErrors, ok := err.(validator.ValidationErrors)
+
+	if !ok {
+		return err
+	}
+
+	result := &InputValidationError{
+		Errors: make([]FieldError, 0, len(validationErrors)),
+	}
+
+	for _, fieldErr := range validationErrors {
+		result.Errors = append(result.Errors, FieldError{
+			Field:   fieldErr.Name,
+			Rule:    fieldErr.Tag,
+			Message: fmt.Sprintf("field %s failed on the '%s' rule", fieldErr.Name, fieldErr.Tag),
+		})
+	}
+
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Field < result.Errors[j].Field
+	})
+
+	return result
+}
+
+// InputValidatorHandler can be implemented by a create, update, merge patch or upsert handler to validate its input
+// with its own InputValidator. Without it, the input is validated with the struct tag validator.
+//
+//go:generate mockery -name InputValidatorHandler
+type InputValidatorHandler interface {
+	GetInputValidator() InputValidator
+}
+
+var structTagInputValidator = NewStructTagValidator()
+
+func getInputValidator(handler interface{}) InputValidator {
+	if validatorHandler, ok := handler.(InputValidatorHandler); ok {
+		return validatorHandler.GetInputValidator()
+	}
+
+	return structTagInputValidator
+}
+
+func validateInput(ctx context.Context, handler interface{}, input interface{}, apiView string) error {
+	inputValidator := getInputValidator(handler)
+
+	if validator, ok := inputValidator.(ApiViewInputValidator); ok {
+		return validator.ValidateApiView(ctx, input, apiView)
+	}
+
+	return inputValidator.Validate(ctx, input)
+}
+
+func newInputValidationErrorResponse(err *InputValidationError) *apiserver.Response {
+	resp := apiserver.NewJsonResponse(err)
+	resp.StatusCode = http.StatusUnprocessableEntity
+
+	return resp
+}
+
+// jsonDecodeBinding only decodes the request body, the validation is done by the InputValidator afterwards
+type jsonDecodeBinding struct{}
+
+func (jsonDecodeBinding) Name() string {
+	return "json"
+}
+
+func (jsonDecodeBinding) Bind(req *http.Request, obj interface{}) error {
+	if req == nil || req.Body == nil {
+		return fmt.Errorf("invalid request")
+	}
+
+	decoder := json.NewDecoder(req.Body)
+
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+
+	return decoder.Decode(obj)
+}
@@ -91,6 +91,10 @@ func (d *Definitions) PUT(relativePath string, handlers ...gin.HandlerFunc) {
 	d.Handle("PUT", relativePath, handlers...)
 }
 
+func (d *Definitions) PATCH(relativePath string, handlers ...gin.HandlerFunc) {
+	d.Handle("PATCH", relativePath, handlers...)
+}
+
 func buildRouter(definitions *Definitions, router gin.IRouter) {
 	grp := router
 
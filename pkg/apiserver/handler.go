@@ -151,7 +151,7 @@ func handleWithInput(handler HandlerWithInput, binding binding.Binding, errHandl
 		err := binding.Bind(ginCtx.Request, input)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+			handleError(ginCtx, errHandler, getBindErrorStatus(err), gin.Error{
 				Err:  err,
 				Type: gin.ErrorTypeBind,
 			})
@@ -174,7 +174,7 @@ func handleWithMultiPartFormInput(handler HandlerWithInput, errHandler ErrorHand
 		err := binding.FormMultipart.Bind(ginCtx.Request, input)
 
 		if err != nil && !errors.Is(err, http.ErrMissingFile) {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+			handleError(ginCtx, errHandler, getBindErrorStatus(err), gin.Error{
 				Err:  err,
 				Type: gin.ErrorTypeBind,
 			})
@@ -191,7 +191,7 @@ func handleWithStream(handler HandlerWithStream, binding binding.Binding, errHan
 		err := binding.Bind(ginCtx.Request, input)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+			handleError(ginCtx, errHandler, getBindErrorStatus(err), gin.Error{
 				Err:  err,
 				Type: gin.ErrorTypeBind,
 			})
@@ -228,7 +228,7 @@ func handleWithMultipleBindings(handler HandlerWithMultipleBindings, errHandler
 			err := bindings[i].Bind(ginCtx.Request, input)
 
 			if err != nil {
-				handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+				handleError(ginCtx, errHandler, getBindErrorStatus(err), gin.Error{
 					Err:  err,
 					Type: gin.ErrorTypeBind,
 				})
@@ -246,7 +246,7 @@ func handleRaw(handler HandlerWithoutInput, errHandler ErrorHandler) gin.Handler
 		body, err := ioutil.ReadAll(ginCtx.Request.Body)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+			handleError(ginCtx, errHandler, getBindErrorStatus(err), gin.Error{
 				Err:  err,
 				Type: gin.ErrorTypeBind,
 			})
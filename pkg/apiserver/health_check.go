@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/kernel"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 type ApiHealthCheckSettings struct {
 	Port int    `cfg:"port" default:"8090"`
 	Path string `cfg:"path" default:"/health"`
+	// Timeout of every registered check which doesn't define its own one
+	Timeout time.Duration `cfg:"timeout" default:"5s"`
 }
 
 type ApiHealthCheck struct {
@@ -31,17 +35,15 @@ func NewApiHealthCheck() kernel.ModuleFactory {
 		gin.SetMode(gin.ReleaseMode)
 		router := gin.New()
 
-		healthCheck := NewApiHealthCheckWithInterfaces(logger, router, settings)
+		healthCheck := NewApiHealthCheckWithInterfaces(logger, router, healthcheck.DefaultRegistry(), settings)
 
 		return healthCheck, nil
 	}
 }
 
-func NewApiHealthCheckWithInterfaces(logger mon.Logger, router *gin.Engine, settings *ApiHealthCheckSettings) *ApiHealthCheck {
+func NewApiHealthCheckWithInterfaces(logger mon.Logger, router *gin.Engine, registry *healthcheck.Registry, settings *ApiHealthCheckSettings) *ApiHealthCheck {
 	router.Use(LoggingMiddleware(logger))
-	router.GET(settings.Path, func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{})
-	})
+	router.GET(settings.Path, HealthCheckHandler(registry, settings.Timeout))
 
 	addr := fmt.Sprintf(":%d", settings.Port)
 
@@ -56,6 +58,25 @@ func NewApiHealthCheckWithInterfaces(logger mon.Logger, router *gin.Engine, sett
 	}
 }
 
+// HealthCheckHandler runs all checks of the registry and answers with a 200 if all of them passed and with a 503
+// listing the failed ones otherwise. The body contains the result of every check.
+func HealthCheckHandler(registry *healthcheck.Registry, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := registry.Check(c.Request.Context(), timeout)
+
+		if result.Healthy {
+			c.JSON(http.StatusOK, result)
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"healthy":  false,
+			"failures": result.Failures(),
+			"checks":   result.Checks,
+		})
+	}
+}
+
 func (a *ApiHealthCheck) Run(ctx context.Context) error {
 	go a.waitForStop(ctx)
 	err := a.server.ListenAndServe()
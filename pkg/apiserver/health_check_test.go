@@ -1,12 +1,17 @@
 package apiserver_test
 
 import (
+	"context"
+	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestNewApiHealthCheck(t *testing.T) {
@@ -14,10 +19,30 @@ func TestNewApiHealthCheck(t *testing.T) {
 	ginEngine := gin.New()
 	logger := mocks.NewLoggerMockedAll()
 
-	apiserver.NewApiHealthCheckWithInterfaces(logger, ginEngine, &apiserver.ApiHealthCheckSettings{
+	apiserver.NewApiHealthCheckWithInterfaces(logger, ginEngine, healthcheck.NewRegistry(), &apiserver.ApiHealthCheckSettings{
 		Path: "/health",
 	})
 
 	httpRecorder := httptest.NewRecorder()
 	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health", http.StatusOK)
 }
+
+func TestNewApiHealthCheck_Failure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	logger := mocks.NewLoggerMockedAll()
+
+	registry := healthcheck.NewRegistry()
+	registry.Register("db", healthcheck.HealthcheckerFunc(func(ctx context.Context) error {
+		return fmt.Errorf("connection refused")
+	}))
+
+	apiserver.NewApiHealthCheckWithInterfaces(logger, ginEngine, registry, &apiserver.ApiHealthCheckSettings{
+		Path:    "/health",
+		Timeout: time.Second,
+	})
+
+	httpRecorder := httptest.NewRecorder()
+	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health", http.StatusServiceUnavailable)
+	assert.JSONEq(t, `{"healthy":false,"failures":["db"],"checks":{"db":{"healthy":false,"error":"connection refused"}}}`, httpRecorder.Body.String())
+}
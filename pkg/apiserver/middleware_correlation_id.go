@@ -0,0 +1,59 @@
+package apiserver
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/uuid"
+	"github.com/gin-gonic/gin"
+)
+
+const HeaderCorrelationId = "X-Correlation-ID"
+
+type correlationIdKey struct{}
+
+// CorrelationIdMiddleware generates a uuid v4 as correlation id for every request without an X-Correlation-ID header.
+func CorrelationIdMiddleware() gin.HandlerFunc {
+	return CorrelationIdMiddlewareWithInterfaces(uuid.New())
+}
+
+// CorrelationIdMiddlewareWithInterfaces stores the correlation id of the request in its context and echoes it in the
+// response header. An id sent by the client is kept, only requests without one get a generated id. Add
+// ContextCorrelationIdFieldsResolver to the logger to log it with every context aware log entry.
+func CorrelationIdMiddlewareWithInterfaces(uuidSource uuid.Uuid) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		correlationId := ginCtx.GetHeader(HeaderCorrelationId)
+
+		if correlationId == "" {
+			correlationId = uuidSource.NewV4()
+		}
+
+		ctx := ContextWithCorrelationId(ginCtx.Request.Context(), correlationId)
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+		ginCtx.Header(HeaderCorrelationId, correlationId)
+
+		ginCtx.Next()
+	}
+}
+
+func ContextWithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, correlationId)
+}
+
+// CorrelationIdFromContext returns the correlation id stored with ContextWithCorrelationId.
+func CorrelationIdFromContext(ctx context.Context) (string, bool) {
+	correlationId, ok := ctx.Value(correlationIdKey{}).(string)
+
+	return correlationId, ok
+}
+
+// ContextCorrelationIdFieldsResolver logs the correlation id of the context as correlation_id.
+func ContextCorrelationIdFieldsResolver(ctx context.Context) map[string]interface{} {
+	correlationId, ok := CorrelationIdFromContext(ctx)
+
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		"correlation_id": correlationId,
+	}
+}
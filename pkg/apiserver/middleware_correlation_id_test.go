@@ -0,0 +1,53 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	uuidMocks "github.com/applike/gosoline/pkg/uuid/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveWithCorrelationId(req *http.Request) (*httptest.ResponseRecorder, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+
+	uuidSource := new(uuidMocks.Uuid)
+	uuidSource.On("NewV4").Return("ba7c7e85-27c1-4b6a-a1a1-7f2a1b6a9a0e")
+
+	var fields map[string]interface{}
+
+	r := gin.New()
+	r.Use(apiserver.CorrelationIdMiddlewareWithInterfaces(uuidSource))
+	r.GET("/some/route", func(ginCtx *gin.Context) {
+		fields = apiserver.ContextCorrelationIdFieldsResolver(ginCtx.Request.Context())
+		ginCtx.Status(http.StatusOK)
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	return res, fields
+}
+
+func TestCorrelationIdMiddleware_Generated(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/some/route", nil)
+	res, fields := serveWithCorrelationId(req)
+
+	assert.Equal(t, "ba7c7e85-27c1-4b6a-a1a1-7f2a1b6a9a0e", res.Header().Get(apiserver.HeaderCorrelationId))
+	assert.Equal(t, map[string]interface{}{
+		"correlation_id": "ba7c7e85-27c1-4b6a-a1a1-7f2a1b6a9a0e",
+	}, fields)
+}
+
+func TestCorrelationIdMiddleware_Incoming(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/some/route", nil)
+	req.Header.Set(apiserver.HeaderCorrelationId, "incoming")
+	res, fields := serveWithCorrelationId(req)
+
+	assert.Equal(t, "incoming", res.Header().Get(apiserver.HeaderCorrelationId))
+	assert.Equal(t, map[string]interface{}{
+		"correlation_id": "incoming",
+	}, fields, "the incoming id should not be replaced")
+}
@@ -2,13 +2,45 @@ package apiserver
 
 import (
 	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/ipread"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
+	"net/http"
 	"strings"
 	"time"
 )
 
+const HeaderRequestId = "X-Request-Id"
+
+type LoggingSettings struct {
+	// FieldNames renames fields of the access log, e.g. status: http_status
+	FieldNames map[string]string `cfg:"field_names"`
+}
+
+// NewLoggingMiddleware reads the field names from api_logging and resolves the client ip with the trusted proxies
+// configured in ipread.api.
+func NewLoggingMiddleware(config cfg.Config, logger mon.Logger) (gin.HandlerFunc, error) {
+	settings := &LoggingSettings{}
+	config.UnmarshalKey("api_logging", settings)
+
+	clientIpResolver, err := ipread.NewClientIpResolver(config, "api")
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create client ip resolver: %w", err)
+	}
+
+	return LoggingMiddlewareWithInterfaces(logger, clientIpResolver, settings), nil
+}
+
 func LoggingMiddleware(logger mon.Logger) gin.HandlerFunc {
+	return LoggingMiddlewareWithInterfaces(logger, nil, &LoggingSettings{})
+}
+
+// LoggingMiddlewareWithInterfaces logs one line per request on the http channel. Requests answered with a 5xx status
+// are logged as error, with a 4xx status as warning and all others as info. Without a client ip resolver the client
+// ip is taken from gin.
+func LoggingMiddlewareWithInterfaces(logger mon.Logger, clientIpResolver ipread.ClientIpResolver, settings *LoggingSettings) gin.HandlerFunc {
 	chLogger := logger.WithChannel("http")
 
 	return func(ginCtx *gin.Context) {
@@ -36,10 +68,11 @@ func LoggingMiddleware(logger mon.Logger) gin.HandlerFunc {
 		method := ginCtx.Request.Method
 		requestTimeNano := time.Since(start)
 		requestTimeSecond := float64(requestTimeNano) / float64(time.Second)
+		status := ginCtx.Writer.Status()
 
-		log = log.WithFields(mon.Fields{
+		fields := mon.Fields{
 			"bytes":                    ginCtx.Writer.Size(),
-			"client_ip":                ginCtx.ClientIP(),
+			"client_ip":                getClientIp(ginCtx, clientIpResolver),
 			"host":                     req.Host,
 			"protocol":                 req.Proto,
 			"request_method":           method,
@@ -50,26 +83,69 @@ func LoggingMiddleware(logger mon.Logger) gin.HandlerFunc {
 			"request_referer":          referer,
 			"request_time":             requestTimeSecond,
 			"scheme":                   req.URL.Scheme,
-			"status":                   ginCtx.Writer.Status(),
-		})
+			"status":                   status,
+		}
 
-		if len(ginCtx.Errors) == 0 {
-			log.Infof("%s %s %s", method, path, req.Proto)
-			return
+		if requestId := req.Header.Get(HeaderRequestId); requestId != "" {
+			fields["request_id"] = requestId
 		}
 
-		for _, e := range ginCtx.Errors {
-			switch e.Type {
-			case gin.ErrorTypeBind:
-				log.Warnf("%s %s %s - bind error - %v", method, path, req.Proto, e.Err)
-			case gin.ErrorTypeRender:
-				log.Warnf("%s %s %s - render error - %v", method, path, req.Proto, e.Err)
-			default:
-				log.Errorf(e.Err, "%s %s %s", method, path, req.Proto)
-			}
+		log = log.WithFields(settings.renameFields(fields))
+		msg := fmt.Sprintf("%s %s %s", method, path, req.Proto)
+
+		if len(ginCtx.Errors) > 0 && status < http.StatusInternalServerError {
+			msg = fmt.Sprintf("%s - %s", msg, strings.Join(ginCtx.Errors.Errors(), "; "))
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Error(getRequestError(ginCtx, status), msg)
+		case status >= http.StatusBadRequest:
+			log.Warn(msg)
+		default:
+			log.Info(msg)
 		}
 	}
 }
+
+func (s *LoggingSettings) renameFields(fields mon.Fields) mon.Fields {
+	if len(s.FieldNames) == 0 {
+		return fields
+	}
+
+	renamed := make(mon.Fields, len(fields))
+
+	for name, value := range fields {
+		if newName, ok := s.FieldNames[name]; ok {
+			name = newName
+		}
+
+		renamed[name] = value
+	}
+
+	return renamed
+}
+
+func getClientIp(ginCtx *gin.Context, clientIpResolver ipread.ClientIpResolver) string {
+	if clientIpResolver == nil {
+		return ginCtx.ClientIP()
+	}
+
+	if ip := clientIpResolver.ClientIp(ginCtx.Request); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+func getRequestError(ginCtx *gin.Context, status int) error {
+	if last := ginCtx.Errors.Last(); last != nil {
+		return last.Err
+	}
+
+	return fmt.Errorf("request failed with status %d", status)
+}
+
 func getPathRaw(ginCtx *gin.Context) string {
 	path := ginCtx.Request.URL.Path
 
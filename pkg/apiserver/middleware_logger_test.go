@@ -0,0 +1,89 @@
+package apiserver_test
+
+import (
+	"errors"
+	"github.com/applike/gosoline/pkg/apiserver"
+	ipreadMocks "github.com/applike/gosoline/pkg/ipread/mocks"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveLogged(logger mon.Logger, settings *apiserver.LoggingSettings, handler gin.HandlerFunc, req *http.Request) {
+	gin.SetMode(gin.TestMode)
+
+	clientIpResolver := new(ipreadMocks.ClientIpResolver)
+	clientIpResolver.On("ClientIp", mock.Anything).Return(net.ParseIP("203.0.113.1"))
+
+	r := gin.New()
+	r.Use(apiserver.LoggingMiddlewareWithInterfaces(logger, clientIpResolver, settings))
+	r.GET("/some/route", handler)
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func newLoggingMiddlewareLogger() *monMocks.Logger {
+	logger := new(monMocks.Logger)
+	logger.On("WithChannel", "http").Return(logger).Once()
+	logger.On("WithContext", mock.Anything).Return(logger).Once()
+
+	return logger
+}
+
+func TestLoggingMiddleware_Info(t *testing.T) {
+	logger := newLoggingMiddlewareLogger()
+	logger.On("WithFields", mock.MatchedBy(func(fields mon.Fields) bool {
+		return fields["status"] == http.StatusOK && fields["client_ip"] == "203.0.113.1" && fields["request_id"] == "request"
+	})).Return(logger).Once()
+	logger.On("Info", "GET /some/route HTTP/1.1").Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/some/route", nil)
+	req.Header.Set(apiserver.HeaderRequestId, "request")
+
+	serveLogged(logger, &apiserver.LoggingSettings{}, func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusOK)
+	}, req)
+
+	logger.AssertExpectations(t)
+}
+
+func TestLoggingMiddleware_WarnWithFieldNames(t *testing.T) {
+	settings := &apiserver.LoggingSettings{
+		FieldNames: map[string]string{
+			"status": "http_status",
+		},
+	}
+
+	logger := newLoggingMiddlewareLogger()
+	logger.On("WithFields", mock.MatchedBy(func(fields mon.Fields) bool {
+		_, hasStatus := fields["status"]
+
+		return fields["http_status"] == http.StatusNotFound && !hasStatus
+	})).Return(logger).Once()
+	logger.On("Warn", "GET /some/route HTTP/1.1").Once()
+
+	serveLogged(logger, settings, func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusNotFound)
+	}, httptest.NewRequest(http.MethodGet, "/some/route", nil))
+
+	logger.AssertExpectations(t)
+}
+
+func TestLoggingMiddleware_Error(t *testing.T) {
+	err := errors.New("failure")
+
+	logger := newLoggingMiddlewareLogger()
+	logger.On("WithFields", mock.Anything).Return(logger).Once()
+	logger.On("Error", err, "GET /some/route HTTP/1.1").Once()
+
+	serveLogged(logger, &apiserver.LoggingSettings{}, func(ginCtx *gin.Context) {
+		_ = ginCtx.AbortWithError(http.StatusInternalServerError, err)
+	}, httptest.NewRequest(http.MethodGet, "/some/route", nil))
+
+	logger.AssertExpectations(t)
+}
@@ -0,0 +1,169 @@
+package apiserver
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/ipread"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	RateLimitKeyByIp     = "ip"
+	RateLimitKeyByHeader = "header"
+)
+
+type RateLimitSettings struct {
+	RequestsPerSecond float64 `cfg:"requests_per_second" default:"10"`
+	Burst             int     `cfg:"burst" default:"20"`
+	KeyBy             string  `cfg:"key_by" default:"ip"`
+	Header            string  `cfg:"header" default:"X-API-KEY"`
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type RateLimiter struct {
+	logger           mon.Logger
+	clock            clock.Clock
+	clientIpResolver ipread.ClientIpResolver
+	lck              sync.Mutex
+	settings         *RateLimitSettings
+	buckets          map[string]*tokenBucket
+	prunedAt         time.Time
+}
+
+// NewRateLimitMiddleware creates a token bucket rate limiter configured by the settings at api.rate_limit.<name>.
+// Attach it to a route or a group to limit requests per client ip or per value of a request header. The client ip is
+// resolved with the trusted proxies configured in ipread.api, so clients can't evade the limit by sending a
+// forwarding header of their own.
+func NewRateLimitMiddleware(config cfg.Config, logger mon.Logger, name string) (gin.HandlerFunc, error) {
+	key := fmt.Sprintf("api.rate_limit.%s", name)
+	settings := &RateLimitSettings{}
+	config.UnmarshalKey(key, settings)
+
+	clientIpResolver, err := ipread.NewClientIpResolver(config, "api")
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create client ip resolver: %w", err)
+	}
+
+	limiter := NewRateLimiterWithInterfaces(logger.WithChannel("rate_limit"), clock.NewRealClock(), clientIpResolver, settings)
+
+	return limiter.Middleware(), nil
+}
+
+func NewRateLimiterWithInterfaces(logger mon.Logger, clock clock.Clock, clientIpResolver ipread.ClientIpResolver, settings *RateLimitSettings) *RateLimiter {
+	return &RateLimiter{
+		logger:           logger,
+		clock:            clock,
+		clientIpResolver: clientIpResolver,
+		settings:         settings,
+		buckets:          make(map[string]*tokenBucket),
+		prunedAt:         clock.Now(),
+	}
+}
+
+// UpdateSettings replaces the limits of the rate limiter. Existing buckets keep their tokens.
+func (r *RateLimiter) UpdateSettings(settings *RateLimitSettings) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	r.settings = settings
+}
+
+func (r *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		key := r.getKey(ginCtx)
+		allowed, retryAfter := r.Allow(key)
+
+		if allowed {
+			return
+		}
+
+		r.logger.WithContext(ginCtx.Request.Context()).Warnf("rate limit exceeded for %s %s", ginCtx.Request.Method, ginCtx.Request.URL.Path)
+
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		ginCtx.Header("Retry-After", strconv.Itoa(seconds))
+		ginCtx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"err": "rate limit exceeded"})
+	}
+}
+
+// Allow takes a token from the bucket of the given key. If there is none left, it returns
+// how long the client has to wait until the next token is available.
+func (r *RateLimiter) Allow(key string) (bool, time.Duration) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	now := r.clock.Now()
+	r.prune(now)
+
+	bucket, ok := r.buckets[key]
+
+	if !ok {
+		bucket = &tokenBucket{
+			tokens:    float64(r.settings.Burst),
+			updatedAt: now,
+		}
+		r.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updatedAt).Seconds()
+	bucket.tokens = math.Min(float64(r.settings.Burst), bucket.tokens+elapsed*r.settings.RequestsPerSecond)
+	bucket.updatedAt = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+
+		return true, 0
+	}
+
+	if r.settings.RequestsPerSecond <= 0 {
+		return false, time.Second
+	}
+
+	wait := (1 - bucket.tokens) / r.settings.RequestsPerSecond
+
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// prune removes the buckets which would be full by now, a new bucket for the same key would behave the same way
+func (r *RateLimiter) prune(now time.Time) {
+	if now.Sub(r.prunedAt) < time.Minute {
+		return
+	}
+
+	r.prunedAt = now
+
+	for key, bucket := range r.buckets {
+		tokens := bucket.tokens + now.Sub(bucket.updatedAt).Seconds()*r.settings.RequestsPerSecond
+
+		if tokens >= float64(r.settings.Burst) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// getKey returns the bucket of the request. Requests without the header share the buckets of the client ips instead of
+// a single one. The keys are prefixed, so a header value can't take the bucket of an ip.
+func (r *RateLimiter) getKey(ginCtx *gin.Context) string {
+	r.lck.Lock()
+	settings := r.settings
+	r.lck.Unlock()
+
+	if settings.KeyBy == RateLimitKeyByHeader {
+		if value := ginCtx.GetHeader(settings.Header); value != "" {
+			return "header:" + value
+		}
+	}
+
+	return "ip:" + getClientIp(ginCtx, r.clientIpResolver)
+}
@@ -0,0 +1,100 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/ipread"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := monMocks.NewLoggerMockedAll()
+	fakeClock := clock.NewFakeClock()
+
+	limiter := apiserver.NewRateLimiterWithInterfaces(logger, fakeClock, nil, &apiserver.RateLimitSettings{
+		RequestsPerSecond: 0.5,
+		Burst:             2,
+		KeyBy:             apiserver.RateLimitKeyByHeader,
+		Header:            "X-API-KEY",
+	})
+
+	r := gin.New()
+	r.Use(limiter.Middleware())
+	r.GET("/", func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusOK)
+	})
+
+	call := func(apiKey string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-KEY", apiKey)
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, call("a").Code)
+	assert.Equal(t, http.StatusOK, call("a").Code)
+
+	resp := call("a")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, call("b").Code, "other keys should have their own bucket")
+
+	fakeClock.Advance(2 * time.Second)
+	assert.Equal(t, http.StatusOK, call("a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("a").Code)
+}
+
+func TestRateLimiter_Middleware_ClientIp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := monMocks.NewLoggerMockedAll()
+	fakeClock := clock.NewFakeClock()
+
+	clientIpResolver, err := ipread.NewClientIpResolverWithSettings(&ipread.ClientIpSettings{
+		TrustedProxies: []string{"10.0.0.0/8"},
+	})
+	assert.NoError(t, err)
+
+	limiter := apiserver.NewRateLimiterWithInterfaces(logger, fakeClock, clientIpResolver, &apiserver.RateLimitSettings{
+		RequestsPerSecond: 0.5,
+		Burst:             1,
+		KeyBy:             apiserver.RateLimitKeyByHeader,
+		Header:            "X-API-KEY",
+	})
+
+	r := gin.New()
+	r.Use(limiter.Middleware())
+	r.GET("/", func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusOK)
+	})
+
+	call := func(remoteAddr string, forwardedFor string) int {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(ipread.HeaderForwardedFor, forwardedFor)
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call("203.0.113.1:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, call("203.0.113.1:1234", "198.51.100.2"), "a forwarding header from an untrusted client should not change the bucket")
+	assert.Equal(t, http.StatusOK, call("203.0.113.2:1234", ""), "requests without api key should not share a single bucket")
+
+	assert.Equal(t, http.StatusOK, call("10.0.0.1:1234", "198.51.100.3"))
+	assert.Equal(t, http.StatusTooManyRequests, call("10.0.0.2:1234", "198.51.100.3"), "the client ip should be taken from a trusted proxy")
+}
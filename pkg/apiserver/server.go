@@ -21,6 +21,7 @@ type Settings struct {
 	TimeoutRead  time.Duration
 	TimeoutWrite time.Duration
 	TimeoutIdle  time.Duration
+	MaxBodySize  int64
 }
 
 type ApiServer struct {
@@ -40,6 +41,7 @@ func New(definer Definer) kernel.ModuleFactory {
 			TimeoutRead:  config.GetDuration("api_timeout_read"),
 			TimeoutWrite: config.GetDuration("api_timeout_write"),
 			TimeoutIdle:  config.GetDuration("api_timeout_idle"),
+			MaxBodySize:  int64(config.GetInt("api_max_body_size", 0)),
 		}
 
 		gin.SetMode(settings.Mode)
@@ -61,8 +63,14 @@ func New(definer Definer) kernel.ModuleFactory {
 			return nil, fmt.Errorf("could not define routes: %w", err)
 		}
 
+		loggingMiddleware, err := NewLoggingMiddleware(config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not create logging middleware: %w", err)
+		}
+
 		router.Use(RecoveryWithSentry(logger))
-		router.Use(LoggingMiddleware(logger))
+		router.Use(loggingMiddleware)
+		router.Use(MaxBodySizeMiddleware(settings.MaxBodySize))
 
 		buildRouter(definitions, router)
 
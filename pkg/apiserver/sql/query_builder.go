@@ -8,11 +8,13 @@ import (
 )
 
 const (
-	OpEq    = "="
-	OpNeq   = "!="
-	OpLike  = "~"
-	OpIs    = "is"
-	OpIsNot = "is not"
+	OpEq      = "="
+	OpNeq     = "!="
+	OpLike    = "~"
+	OpIs      = "is"
+	OpIsNot   = "is not"
+	OpIsNull  = "isnull"
+	OpNotNull = "notnull"
 )
 
 type Order struct {
@@ -268,13 +270,18 @@ func (qb baseQueryBuilder) buildFilterValues(match FilterMatch) (string, []inter
 		return "", []interface{}{}, fmt.Errorf("no list mapping found for dimension %s", match.Dimension)
 	}
 
+	mapping := qb.mapping[match.Dimension]
+
+	if match.Operator == OpIsNull || match.Operator == OpNotNull {
+		return qb.buildNullFilter(match, mapping)
+	}
+
 	if len(match.Values) == 0 {
 		return "(1 = 2)", []interface{}{}, nil
 	}
 
 	stmts := make([]string, 0)
 	args := make([]interface{}, 0)
-	mapping := qb.mapping[match.Dimension]
 
 	for _, column := range mapping.Columns() {
 		w, a := qb.buildFilterColumn(match, column)
@@ -289,6 +296,31 @@ func (qb baseQueryBuilder) buildFilterValues(match FilterMatch) (string, []inter
 	return where, args, nil
 }
 
+// buildNullFilter checks the columns of the mapping for NULL with the operators isnull and notnull, which don't take
+// any values.
+func (qb baseQueryBuilder) buildNullFilter(match FilterMatch, mapping db_repo.FieldMapping) (string, []interface{}, error) {
+	if len(match.Values) > 0 {
+		return "", []interface{}{}, fmt.Errorf("the operator %s of dimension %s does not take any values", match.Operator, match.Dimension)
+	}
+
+	predicate := "IS NULL"
+
+	if match.Operator == OpNotNull {
+		predicate = "IS NOT NULL"
+	}
+
+	stmts := make([]string, 0, len(mapping.Columns()))
+
+	for _, column := range mapping.Columns() {
+		stmts = append(stmts, fmt.Sprintf("(%s %s)", column.Name(), predicate))
+	}
+
+	b := fmt.Sprintf(" %s ", mapping.Bool())
+	where := fmt.Sprintf("(%s)", strings.Join(stmts, b))
+
+	return where, []interface{}{}, nil
+}
+
 func (qb baseQueryBuilder) buildFilterColumn(match FilterMatch, column db_repo.FieldMappingColumn) (string, []interface{}) {
 	if (match.Operator == OpEq || match.Operator == OpNeq) && len(match.Values) > 1 {
 		return qb.buildSetFilterColumn(match, column)
@@ -340,3 +340,51 @@ func TestListQueryBuilder_Build_NullFilter(t *testing.T) {
 
 	assert.Equal(t, expected, qb)
 }
+
+func TestListQueryBuilder_Build_IsNullFilter(t *testing.T) {
+	metadata := db_repo.Metadata{
+		PrimaryKey: "id",
+		TableName:  "tablename",
+		Mappings: db_repo.FieldMappings{
+			"id":       db_repo.NewFieldMapping("id"),
+			"assignee": db_repo.NewFieldMapping("assignee_id"),
+			"reviewer": db_repo.NewFieldMapping("reviewer_id"),
+		},
+	}
+
+	inp := &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "assignee",
+					Operator:  sql.OpIsNull,
+				},
+				{
+					Dimension: "reviewer",
+					Operator:  sql.OpNotNull,
+				},
+			},
+			Bool: "and",
+		},
+	}
+
+	lqb := sql.NewOrmQueryBuilder(metadata)
+	qb, err := lqb.Build(inp)
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("tablename")
+	expected.Where("(((assignee_id IS NULL)) and ((reviewer_id IS NOT NULL)))", []interface{}{}...)
+	expected.GroupBy("id")
+
+	assert.Equal(t, expected, qb)
+
+	inp.Filter.Matches[0].Values = []interface{}{true}
+	_, err = lqb.Build(inp)
+	assert.EqualError(t, err, "the operator isnull of dimension assignee does not take any values")
+
+	inp.Filter.Matches[0].Dimension = "unknown"
+	_, err = lqb.Build(inp)
+	assert.EqualError(t, err, "no list mapping found for dimension unknown")
+}
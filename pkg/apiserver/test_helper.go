@@ -8,6 +8,10 @@ import (
 )
 
 func HttpTest(method string, path string, requestPath string, body string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	return HttpTestWithHeader(method, path, requestPath, body, nil, handler)
+}
+
+func HttpTestWithHeader(method string, path string, requestPath string, body string, header http.Header, handler gin.HandlerFunc) *httptest.ResponseRecorder {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
@@ -17,6 +21,10 @@ func HttpTest(method string, path string, requestPath string, body string, handl
 	request, _ := http.NewRequest(method, requestPath, bodyReader)
 	response := httptest.NewRecorder()
 
+	for key, values := range header {
+		request.Header[key] = values
+	}
+
 	r.ServeHTTP(response, request)
 
 	return response
@@ -8,6 +8,7 @@ import (
 )
 
 type App struct {
+	configFileDisabled   bool
 	configOptions        []ConfigOption
 	configPostProcessors []cfg.PostProcessor
 	kernelOptions        []KernelOption
@@ -68,3 +68,55 @@ func runTestApp(t *testing.T, f func()) {
 
 	f()
 }
+
+type envTestModule struct {
+	kernel.EssentialModule
+	t *testing.T
+}
+
+func (m envTestModule) Boot(config cfg.Config, _ mon.Logger) error {
+	settings := &testSettings{}
+	config.UnmarshalKey("test.settings-struct", settings)
+
+	assert.Equal(m.t, "value", settings.Field)
+	assert.Equal(m.t, "test_app", config.GetString("app_name"))
+
+	return nil
+}
+
+func (m envTestModule) Run(_ context.Context) error {
+	return nil
+}
+
+func TestDefaultWithoutConfigFile(t *testing.T) {
+	env := map[string]string{
+		"ENV":                        "test",
+		"APP_NAME":                   "test_app",
+		"APP_PROJECT":                "test_project",
+		"APP_FAMILY":                 "test_family",
+		"TEST_SETTINGS_STRUCT_FIELD": "value",
+	}
+
+	for key, value := range env {
+		assert.NoError(t, os.Setenv(key, value))
+	}
+
+	defer func() {
+		for key := range env {
+			assert.NoError(t, os.Unsetenv(key))
+		}
+	}()
+
+	runTestApp(t, func() {
+		// there is no config.dist.yml in the parent directory of testdata
+		assert.NoError(t, os.Chdir(".."))
+
+		app := application.Default(application.WithoutConfigFile)
+		app.Add("test", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+			return envTestModule{
+				t: t,
+			}, nil
+		})
+		app.Run()
+	})
+}
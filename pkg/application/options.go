@@ -33,6 +33,8 @@ type loggerSettings struct {
 	Format          string                 `cfg:"format" default:"console" validate:"required"`
 	TimestampFormat string                 `cfg:"timestamp_format" default:"15:04:05.000" validate:"required"`
 	Tags            map[string]interface{} `cfg:"tags"`
+	Fields          map[string]interface{} `cfg:"fields"`
+	ChannelLevels   map[string]string      `cfg:"channel_levels"`
 }
 
 func WithApiHealthCheck(app *App) {
@@ -64,6 +66,15 @@ func WithConfigEnvKeyReplacer(replacer *strings.Replacer) Option {
 	}
 }
 
+// WithConfigEnvFile reads environment variables for the config from a .env file like ./.env, see cfg.WithEnvFile.
+func WithConfigEnvFile(filePath string) Option {
+	return func(app *App) {
+		app.addConfigOption(func(config cfg.GosoConf) error {
+			return config.Option(cfg.WithEnvFile(filePath))
+		})
+	}
+}
+
 func WithConfigErrorHandlers(handlers ...cfg.ErrorHandler) Option {
 	return func(app *App) {
 		app.addConfigOption(func(config cfg.GosoConf) error {
@@ -75,6 +86,10 @@ func WithConfigErrorHandlers(handlers ...cfg.ErrorHandler) Option {
 func WithConfigFile(filePath string, fileType string) Option {
 	return func(app *App) {
 		app.addConfigOption(func(config cfg.GosoConf) error {
+			if app.configFileDisabled {
+				return nil
+			}
+
 			return config.Option(cfg.WithConfigFile(filePath, fileType))
 		})
 	}
@@ -132,6 +147,13 @@ func WithConfigSetting(key string, settings interface{}) Option {
 	}
 }
 
+// WithoutConfigFile skips the files added with WithConfigFile, like the config.dist.yml of Default, for setups
+// without any config file. The config is then built from the defaults of the settings structs, the environment
+// and the settings added with WithConfigMap or WithConfigSetting. A file passed with the -config flag is still read.
+func WithoutConfigFile(app *App) {
+	app.configFileDisabled = true
+}
+
 func WithConsumerMessagesPerRunnerMetrics(app *App) {
 	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
 		kernel.AddFactory(stream.MessagesPerRunnerMetricWriterFactory)
@@ -255,6 +277,11 @@ func WithLoggerSettingsFromConfig(app *App) {
 			mon.WithLevel(settings.Level),
 			mon.WithFormat(settings.Format),
 			mon.WithTimestampFormat(settings.TimestampFormat),
+			mon.WithDefaultFields(settings.Fields),
+		}
+
+		for channel, level := range settings.ChannelLevels {
+			loggerOptions = append(loggerOptions, mon.WithMinLevelForChannel(channel, level))
 		}
 
 		return logger.Option(loggerOptions...)
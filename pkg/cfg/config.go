@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cast"
 	"github.com/thoas/go-funk"
 	"gopkg.in/go-playground/validator.v9"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -51,6 +53,9 @@ type config struct {
 	settings       *mapx.MapX
 	envKeyPrefix   string
 	envKeyReplacer *strings.Replacer
+	envKeyBindings map[string]string
+	casters        []mapx.MapStructCaster
+	parsers        map[reflect.Type]mapx.MapStructParser
 }
 
 var DefaultEnvKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
@@ -62,10 +67,15 @@ func New() GosoConf {
 
 func NewWithInterfaces(lookupEnv LookupEnv) GosoConf {
 	cfg := &config{
-		lookupEnv:     lookupEnv,
-		errorHandlers: []ErrorHandler{defaultErrorHandler},
-		sanitizers:    make([]Sanitizer, 0),
-		settings:      mapx.NewMapX(),
+		lookupEnv:      lookupEnv,
+		errorHandlers:  []ErrorHandler{defaultErrorHandler},
+		sanitizers:     make([]Sanitizer, 0),
+		settings:       mapx.NewMapX(),
+		envKeyBindings: make(map[string]string),
+		parsers: map[reflect.Type]mapx.MapStructParser{
+			reflect.TypeOf(net.IP{}):   mapx.MapStructIpParser,
+			reflect.TypeOf(&url.URL{}): mapx.MapStructUrlParser,
+		},
 	}
 
 	return cfg
@@ -220,9 +230,7 @@ func (c *config) GetStringMap(key string, optionalDefault ...map[string]interfac
 	}
 
 	for k, v := range strMap {
-		if str, ok := v.(string); ok {
-			strMap[k] = c.augmentString(str)
-		}
+		strMap[k] = c.augmentLeaves(v)
 	}
 
 	return strMap
@@ -363,6 +371,32 @@ func (c *config) augmentString(str string) string {
 	return str
 }
 
+// augmentLeaves augments all strings contained in the value, including the ones of nested maps and slices
+func (c *config) augmentLeaves(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return c.augmentString(v)
+	case map[string]interface{}:
+		augmented := make(map[string]interface{}, len(v))
+
+		for key, elem := range v {
+			augmented[key] = c.augmentLeaves(elem)
+		}
+
+		return augmented
+	case []interface{}:
+		augmented := make([]interface{}, len(v))
+
+		for i, elem := range v {
+			augmented[i] = c.augmentLeaves(elem)
+		}
+
+		return augmented
+	default:
+		return value
+	}
+}
+
 func (c *config) err(err error, msg string, args ...interface{}) {
 	for i := 0; i < len(c.errorHandlers); i++ {
 		c.errorHandlers[i](err, msg, args...)
@@ -373,13 +407,14 @@ func (c *config) buildMapStruct(target interface{}) *mapx.MapXStruct {
 	ms, err := mapx.NewMapStruct(target, &mapx.MapXStructSettings{
 		FieldTag:   "cfg",
 		DefaultTag: "default",
-		Casters: []mapx.MapStructCaster{
+		Casters: append([]mapx.MapStructCaster{
 			mapx.MapStructDurationCaster,
 			mapx.MapStructTimeCaster,
-		},
+		}, c.casters...),
 		Decoders: []mapx.MapStructDecoder{
 			c.decodeAugmentHook(),
 		},
+		Parsers: c.parsers,
 	})
 
 	if err != nil {
@@ -406,7 +441,7 @@ func (c *config) get(key string) interface{} {
 	dataMap := mapx.NewMapX()
 	dataMap.Set(key, data)
 
-	environment := c.readEnvironment(c.envKeyPrefix, dataMap)
+	environment := c.readEnvironment(c.envKeyPrefix, "", dataMap)
 	dataMap.Merge(".", environment)
 
 	c.settings.Merge(".", dataMap)
@@ -433,7 +468,7 @@ func (c *config) getString(key string, optionalDefault ...string) string {
 
 func (c *config) isSet(key string) bool {
 	envKey := c.resolveEnvKey(c.envKeyPrefix, key)
-	if _, ok := c.lookupEnv(envKey); ok {
+	if _, ok := c.lookupEnvForKey(key, envKey); ok {
 		return true
 	}
 
@@ -506,20 +541,25 @@ func (c *config) mergeStruct(prefix string, settings interface{}, options ...Mer
 	return c.mergeMsi(prefix, msi, options...)
 }
 
-func (c *config) readEnvironment(prefix string, input *mapx.MapX) *mapx.MapX {
+func (c *config) readEnvironment(prefix string, configPrefix string, input *mapx.MapX) *mapx.MapX {
 	environment := mapx.NewMapX()
 
 	for _, k := range input.Keys() {
 		key := c.resolveEnvKey(prefix, k)
+		configKey := k
 		val := input.Get(k)
 
+		if len(configPrefix) > 0 {
+			configKey = strings.Join([]string{configPrefix, k}, ".")
+		}
+
 		if nestedMap, err := val.Map(); err == nil {
-			nestedValues := c.readEnvironment(key, nestedMap)
+			nestedValues := c.readEnvironment(key, configKey, nestedMap)
 			environment.Set(k, nestedValues)
 			continue
 		}
 
-		if envValue, ok := c.lookupEnv(key); ok {
+		if envValue, ok := c.lookupEnvForKey(configKey, key); ok {
 			augmentedString := c.augmentString(envValue)
 			environment.Set(k, augmentedString)
 		}
@@ -528,6 +568,18 @@ func (c *config) readEnvironment(prefix string, input *mapx.MapX) *mapx.MapX {
 	return environment
 }
 
+// lookupEnvForKey reads the environment variable bound to the config key with WithEnvKeyBinding first and
+// falls back to the automatically resolved environment key if there is no binding or the variable isn't set.
+func (c *config) lookupEnvForKey(configKey string, envKey string) (string, bool) {
+	if boundKey, ok := c.envKeyBindings[configKey]; ok {
+		if value, ok := c.lookupEnv(boundKey); ok {
+			return value, true
+		}
+	}
+
+	return c.lookupEnv(envKey)
+}
+
 func (c *config) resolveEnvKey(prefix string, key string) string {
 	if len(prefix) > 0 {
 		key = strings.Join([]string{prefix, key}, ".")
@@ -628,7 +680,7 @@ func (c *config) unmarshalStruct(key string, output interface{}, additionalDefau
 	}
 
 	environmentKey := c.resolveEnvKey(c.envKeyPrefix, key)
-	environmentSettings := c.readEnvironment(environmentKey, finalSettings)
+	environmentSettings := c.readEnvironment(environmentKey, key, finalSettings)
 
 	finalSettings.Merge(".", environmentSettings)
 	c.settings.Set(key, finalSettings)
@@ -1,11 +1,14 @@
 package cfg_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/suite"
 	"math"
+	"net"
+	"net/url"
 	"testing"
 	"time"
 )
@@ -202,6 +205,32 @@ func (s *ConfigTestSuite) TestConfig_GetStringMapString() {
 	s.Equal(missingMap, s.config.GetStringMapString("missing", missingMap))
 }
 
+func (s *ConfigTestSuite) TestConfig_GetStringMap() {
+	s.setupConfigValues(map[string]interface{}{
+		"map": map[string]interface{}{
+			"a":   "{bar}",
+			"int": 1,
+			"nested": map[string]interface{}{
+				"b":     "{bar}-nested",
+				"slice": []interface{}{"{bar}", 2},
+			},
+		},
+		"bar": "baz",
+	})
+
+	strMap := s.config.GetStringMap("map")
+
+	s.Equal(map[string]interface{}{
+		"a":   "baz",
+		"int": 1,
+		"nested": map[string]interface{}{
+			"b":     "baz-nested",
+			"slice": []interface{}{"baz", 2},
+		},
+	}, strMap)
+	s.Equal(map[string]interface{}{}, s.config.GetStringMap("missing", map[string]interface{}{}))
+}
+
 func (s *ConfigTestSuite) TestConfig_GetStringSlice() {
 	s.setupConfigValues(map[string]interface{}{
 		"slice":  []string{"string", "a{b}"},
@@ -279,6 +308,39 @@ func (s *ConfigTestSuite) TestConfig_EnvironmentPrefixed() {
 	s.Equal("string", s.config.GetString("s"))
 }
 
+func (s *ConfigTestSuite) TestConfig_EnvironmentBinding() {
+	type dbSettings struct {
+		User     string `cfg:"user"`
+		Password string `cfg:"password"`
+	}
+
+	s.applyOptions(cfg.WithEnvKeyBinding("db.password", "DATABASE_PASSWORD"))
+	s.setupConfigValues(map[string]interface{}{
+		"db": map[string]interface{}{
+			"user":     "gosoline",
+			"password": "default",
+		},
+	})
+	s.setupEnvironment(map[string]string{
+		"DB_USER":           "env",
+		"DB_PASSWORD":       "automatic",
+		"DATABASE_PASSWORD": "bound",
+	})
+
+	s.True(s.config.IsSet("db.password"))
+	s.Equal("bound", s.config.GetString("db.password"))
+	s.Equal("env", s.config.GetString("db.user"))
+
+	settings := dbSettings{}
+	s.config.UnmarshalKey("db", &settings)
+	s.Equal(dbSettings{User: "env", Password: "bound"}, settings)
+
+	s.setupEnvironment(map[string]string{
+		"DB_PASSWORD": "automatic",
+	})
+	s.Equal("automatic", s.config.GetString("db.password"))
+}
+
 func (s *ConfigTestSuite) TestConfig_UnmarshalKey_Struct() {
 	type configMap struct {
 		Foo          string   `cfg:"foo"`
@@ -538,6 +600,188 @@ func (s *ConfigTestSuite) TestConfig_UnmarshalKeyWithDefaultsFromKey() {
 	s.Equal(expected, cm)
 }
 
+func (s *ConfigTestSuite) TestConfig_UnmarshalKeyPreciseNumbers() {
+	type port uint16
+
+	type configMap struct {
+		I64   int64       `cfg:"i64"`
+		Env   int64       `cfg:"env"`
+		F64   float64     `cfg:"f64"`
+		Num   json.Number `cfg:"num"`
+		Port  port        `cfg:"port"`
+		Slice []int64     `cfg:"slice"`
+	}
+
+	s.applyOptions(cfg.WithPreciseNumbers())
+	s.setupEnvironment(map[string]string{
+		"KEY_ENV": "9223372036854775807",
+	})
+	s.setupConfigValues(map[string]interface{}{
+		"key": map[string]interface{}{
+			"i64":   json.Number("9007199254740993"),
+			"env":   1,
+			"f64":   json.Number("0.1"),
+			"num":   json.Number("123456789012345678901234567890"),
+			"port":  8080,
+			"slice": []interface{}{json.Number("9007199254740995"), "1"},
+		},
+	})
+
+	expected := configMap{
+		I64:   9007199254740993,
+		Env:   math.MaxInt64,
+		F64:   0.1,
+		Num:   json.Number("123456789012345678901234567890"),
+		Port:  8080,
+		Slice: []int64{9007199254740995, 1},
+	}
+
+	cm := configMap{}
+	s.config.UnmarshalKey("key", &cm)
+
+	s.Equal(expected, cm)
+}
+
+func (s *ConfigTestSuite) TestConfig_UnmarshalKeyPreciseNumbersOverflow() {
+	type configMap struct {
+		I8 int8 `cfg:"i8"`
+	}
+
+	var errs []error
+
+	s.applyOptions(
+		cfg.WithPreciseNumbers(),
+		cfg.WithErrorHandlers(func(err error, msg string, args ...interface{}) {
+			errs = append(errs, err)
+		}),
+	)
+	s.setupConfigValues(map[string]interface{}{
+		"key": map[string]interface{}{
+			"i8": 128,
+		},
+	})
+
+	cm := configMap{}
+	s.config.UnmarshalKey("key", &cm)
+
+	s.Len(errs, 1)
+}
+
+type testLevel string
+
+func parseTestLevel(raw string) (interface{}, error) {
+	switch raw {
+	case "debug", "info":
+		return testLevel(raw), nil
+	}
+
+	return nil, fmt.Errorf("unknown level %s", raw)
+}
+
+func (s *ConfigTestSuite) TestConfig_UnmarshalKeyStringParsers() {
+	type configMap struct {
+		Ip        net.IP    `cfg:"ip"`
+		DefaultIp net.IP    `cfg:"default_ip" default:"127.0.0.1"`
+		Ips       []net.IP  `cfg:"ips"`
+		Url       *url.URL  `cfg:"url"`
+		Time      time.Time `cfg:"time"`
+		Level     testLevel `cfg:"level"`
+	}
+
+	s.applyOptions(cfg.WithStringParser(testLevel(""), parseTestLevel))
+	s.setupConfigValues(map[string]interface{}{
+		"host": "example.com",
+		"key": map[string]interface{}{
+			"ip":    "10.0.0.1",
+			"ips":   []interface{}{"10.0.0.2", "::1"},
+			"url":   "https://{host}/path?q=1",
+			"time":  "2020-04-21T12:00:00Z",
+			"level": "debug",
+		},
+	})
+
+	expected := configMap{
+		Ip:        net.ParseIP("10.0.0.1"),
+		DefaultIp: net.ParseIP("127.0.0.1"),
+		Ips:       []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("::1")},
+		Url: &url.URL{
+			Scheme:   "https",
+			Host:     "example.com",
+			Path:     "/path",
+			RawQuery: "q=1",
+		},
+		Time:  time.Date(2020, time.April, 21, 12, 0, 0, 0, time.UTC),
+		Level: "debug",
+	}
+
+	cm := configMap{}
+	s.config.UnmarshalKey("key", &cm)
+
+	s.Equal(expected, cm)
+}
+
+func (s *ConfigTestSuite) TestConfig_UnmarshalKeyStringParsersInvalid() {
+	tests := map[string]struct {
+		value  string
+		target interface{}
+		err    string
+	}{
+		"ip": {
+			value: "10.0.0.256",
+			target: &struct {
+				Value net.IP `cfg:"value"`
+			}{},
+			err: `can not decode and cast value for key value: can not parse "10.0.0.256" as net.IP: "10.0.0.256" is not a valid ip address`,
+		},
+		"url": {
+			value: "/relative",
+			target: &struct {
+				Value *url.URL `cfg:"value"`
+			}{},
+			err: `can not decode and cast value for key value: can not parse "/relative" as *url.URL: "/relative" is not an absolute url`,
+		},
+		"time": {
+			value: "yesterday",
+			target: &struct {
+				Value time.Time `cfg:"value"`
+			}{},
+			err: "can not decode and cast value for key value: provided value yesterday doesn't match target type time.Time: caster mapx.MapStructCaster failed: unable to parse date: yesterday",
+		},
+		"custom": {
+			value: "trace",
+			target: &struct {
+				Value testLevel `cfg:"value"`
+			}{},
+			err: `can not decode and cast value for key value: can not parse "trace" as cfg_test.testLevel: unknown level trace`,
+		},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			var errs []error
+
+			s.SetupTest()
+			s.applyOptions(
+				cfg.WithStringParser(testLevel(""), parseTestLevel),
+				cfg.WithErrorHandlers(func(err error, msg string, args ...interface{}) {
+					errs = append(errs, err)
+				}),
+			)
+			s.setupConfigValues(map[string]interface{}{
+				"key": map[string]interface{}{
+					"value": test.value,
+				},
+			})
+
+			s.config.UnmarshalKey("key", test.target)
+
+			if s.Len(errs, 1) {
+				s.EqualError(errs[0], test.err)
+			}
+		})
+	}
+}
+
 func (s *ConfigTestSuite) TestConfig_FromYml() {
 	type configMap struct {
 		D   time.Duration          `cfg:"d"`
@@ -567,6 +811,43 @@ func (s *ConfigTestSuite) TestConfig_FromYml() {
 	s.Equal(expected, cm)
 }
 
+func (s *ConfigTestSuite) TestConfig_WithEnvFile() {
+	s.setupConfigValues(map[string]interface{}{
+		"db": map[string]interface{}{
+			"host":     "localhost",
+			"password": "default",
+			"port":     3307,
+			"user":     "default",
+		},
+	})
+	s.setupEnvironment(map[string]string{
+		"DB_PORT": "3308",
+	})
+	s.applyOptions(cfg.WithEnvFile("testdata/test.env"))
+
+	s.Equal("se\"cret\n", s.config.GetString("db.password"))
+	s.Equal("gosoline", s.config.GetString("db.user"))
+	s.Equal("local #host", s.config.GetString("db.host"))
+	s.Equal(3308, s.config.GetInt("db.port"), "the environment of the process should take precedence")
+	s.Equal("", s.config.GetString("empty"))
+
+	s.applyOptions(cfg.WithEnvFile("testdata/missing.env"))
+}
+
+func (s *ConfigTestSuite) TestConfig_WithEnvFile_Disabled() {
+	s.setupConfigValues(map[string]interface{}{
+		"db": map[string]interface{}{
+			"user": "default",
+		},
+		"env_file": map[string]interface{}{
+			"enabled": false,
+		},
+	})
+	s.applyOptions(cfg.WithEnvFile("testdata/test.env"))
+
+	s.Equal("default", s.config.GetString("db.user"))
+}
+
 func TestConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(ConfigTestSuite))
 }
@@ -0,0 +1,119 @@
+package cfg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// WithEnvFile reads variables from a .env file, which are used like environment variables of the process, e.g.
+// DB_PASSWORD=secret for db.password. Variables set in the environment of the process take precedence, the
+// environment of the process isn't changed. A missing file is skipped. Setting env_file.enabled to false, e.g. in the
+// config of the production environment, skips reading the file.
+//
+// Every line holds a KEY=value pair, optionally prefixed by export. Values may be quoted: double quoted ones support
+// escapes like \n, single quoted ones are taken as they are. Lines starting with # and the rest of a line after a #
+// following an unquoted value are comments.
+func WithEnvFile(filePath string) Option {
+	return func(cfg *config) error {
+		if !cfg.GetBool("env_file.enabled", true) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(filePath)
+
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("can not read env file %s: %w", filePath, err)
+		}
+
+		values, err := parseEnvFile(content)
+
+		if err != nil {
+			return fmt.Errorf("can not parse env file %s: %w", filePath, err)
+		}
+
+		lookupEnv := cfg.lookupEnv
+		cfg.lookupEnv = func(key string) (string, bool) {
+			if value, ok := lookupEnv(key); ok {
+				return value, true
+			}
+
+			value, ok := values[key]
+
+			return value, ok
+		}
+
+		return nil
+	}
+}
+
+func parseEnvFile(content []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		idx := strings.Index(line, "=")
+
+		if idx < 1 {
+			return nil, fmt.Errorf("line %d is not of the form KEY=value", lineNo)
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		value, err := parseEnvValue(strings.TrimSpace(line[idx+1:]))
+
+		if err != nil {
+			return nil, fmt.Errorf("can not parse the value of %s in line %d: %w", key, lineNo, err)
+		}
+
+		values[key] = value
+	}
+
+	return values, scanner.Err()
+}
+
+func parseEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	quote := raw[0]
+
+	if quote != '"' && quote != '\'' {
+		if idx := strings.Index(raw, " #"); idx >= 0 {
+			raw = raw[:idx]
+		}
+
+		return strings.TrimSpace(raw), nil
+	}
+
+	end := strings.LastIndexByte(raw, quote)
+
+	if end == 0 {
+		return "", fmt.Errorf("missing closing quote")
+	}
+
+	if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected characters after the closing quote")
+	}
+
+	if quote == '\'' {
+		return raw[1:end], nil
+	}
+
+	return strconv.Unquote(raw[:end+1])
+}
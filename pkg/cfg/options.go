@@ -2,7 +2,9 @@ package cfg
 
 import (
 	"flag"
+	"github.com/applike/gosoline/pkg/mapx"
 	"os"
+	"reflect"
 	"strings"
 )
 
@@ -49,6 +51,17 @@ func WithEnvKeyPrefix(prefix string) Option {
 	}
 }
 
+// WithEnvKeyBinding reads the config key from the given environment variable, e.g. db.password from
+// DATABASE_PASSWORD. A bound variable takes precedence over the variable derived from the config key by the
+// prefix and the replacer, which is still used if the bound variable isn't set.
+func WithEnvKeyBinding(configKey string, envKey string) Option {
+	return func(cfg *config) error {
+		cfg.envKeyBindings[configKey] = envKey
+
+		return nil
+	}
+}
+
 func WithEnvKeyReplacer(replacer *strings.Replacer) Option {
 	return func(cfg *config) error {
 		cfg.envKeyReplacer = replacer
@@ -65,6 +78,28 @@ func WithErrorHandlers(handlers ...ErrorHandler) Option {
 	}
 }
 
+// WithPreciseNumbers decodes numbers into integer, float and json.Number fields without a detour over float64. Big
+// integers keep their exact value and values not fitting into a field, like a fraction for an integer, result in an
+// error instead of being truncated. Named numeric types like type Port uint16 are supported as well.
+func WithPreciseNumbers() Option {
+	return func(cfg *config) error {
+		cfg.casters = append(cfg.casters, mapx.MapStructNumberCaster)
+
+		return nil
+	}
+}
+
+// WithStringParser registers a parser for fields of the type of the given value, e.g. WithStringParser(Level(""),
+// parseLevel) for a type Level string. The parser gets the raw string of the setting and an error it returns is
+// reported together with the key of the field. Parsers for net.IP and *url.URL are registered by default.
+func WithStringParser(value interface{}, parser mapx.MapStructParser) Option {
+	return func(cfg *config) error {
+		cfg.parsers[reflect.TypeOf(value)] = parser
+
+		return nil
+	}
+}
+
 func WithSanitizers(sanitizer ...Sanitizer) Option {
 	return func(cfg *config) error {
 		cfg.sanitizers = append(cfg.sanitizers, sanitizer...)
@@ -95,3 +95,17 @@ func CheckErrorThrottle(_ interface{}, err error) exec.ErrorType {
 
 	return exec.ErrorTypeUnknown
 }
+
+// CheckErrorCodes returns a checker marking aws errors with one of the codes as retryable, e.g. the
+// ProvisionedThroughputExceededException of a service which isn't covered by CheckErrorThrottle.
+func CheckErrorCodes(codes ...string) exec.ErrorChecker {
+	return func(_ interface{}, err error) exec.ErrorType {
+		for _, code := range codes {
+			if IsAwsError(err, code) {
+				return exec.ErrorTypeRetryable
+			}
+		}
+
+		return exec.ErrorTypeUnknown
+	}
+}
@@ -96,6 +96,37 @@ func TestIsRequestCanceled(t *testing.T) {
 	}
 }
 
+func TestCheckErrorCodes(t *testing.T) {
+	check := cloudAws.CheckErrorCodes(kinesis.ErrCodeProvisionedThroughputExceededException, kinesis.ErrCodeLimitExceededException)
+
+	for name, test := range map[string]struct {
+		err       error
+		errorType exec.ErrorType
+	}{
+		"matching code": {
+			err:       awsErr{code: kinesis.ErrCodeLimitExceededException},
+			errorType: exec.ErrorTypeRetryable,
+		},
+		"matching code wrapped": {
+			err:       fmt.Errorf("error %w", awsErr{code: kinesis.ErrCodeProvisionedThroughputExceededException}),
+			errorType: exec.ErrorTypeRetryable,
+		},
+		"other code": {
+			err:       awsErr{code: kinesis.ErrCodeResourceNotFoundException},
+			errorType: exec.ErrorTypeUnknown,
+		},
+		"no aws error": {
+			err:       fmt.Errorf("error"),
+			errorType: exec.ErrorTypeUnknown,
+		},
+	} {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.errorType, check(nil, test.err))
+		})
+	}
+}
+
 func TestIsUsedClosedConnectionError(t *testing.T) {
 	ln, err := net.Listen("tcp4", "127.0.0.1:0")
 	if err != nil {
@@ -0,0 +1,385 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/cloud"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"time"
+)
+
+const (
+	CheckpointTrimHorizon = kinesis.ShardIteratorTypeTrimHorizon
+	CheckpointLatest      = kinesis.ShardIteratorTypeLatest
+	CheckpointAtTimestamp = kinesis.ShardIteratorTypeAtTimestamp
+
+	// kinsumer reads a shard from the start if there is no sequence number and from the tip for this one
+	kinsumerSequenceNumberLatest = "LATEST"
+	// the records before a timestamp are searched in a window which starts this long before it and is doubled until
+	// a record is found, up to the maximum retention of a stream
+	checkpointResetLookback    = time.Minute
+	checkpointResetMaxLookback = 365 * 24 * time.Hour
+	// how many GetRecords calls a single search may take
+	checkpointResetMaxReads = 100
+)
+
+// CheckpointPosition is the position in a shard a consumer continues reading at after its checkpoints were reset.
+type CheckpointPosition struct {
+	Type      string
+	Timestamp time.Time
+}
+
+func CheckpointAtTrimHorizon() CheckpointPosition {
+	return CheckpointPosition{Type: CheckpointTrimHorizon}
+}
+
+func CheckpointAtLatest() CheckpointPosition {
+	return CheckpointPosition{Type: CheckpointLatest}
+}
+
+func CheckpointAt(timestamp time.Time) CheckpointPosition {
+	return CheckpointPosition{Type: CheckpointAtTimestamp, Timestamp: timestamp}
+}
+
+//go:generate mockery -name CheckpointResetter
+type CheckpointResetter interface {
+	// ResetCheckpoints moves the checkpoints of the given shards, or of all shards of the stream if there are none,
+	// to the position. It returns the number of checkpoints which were changed.
+	ResetCheckpoints(ctx context.Context, position CheckpointPosition, shardIds ...string) (int, error)
+}
+
+type checkpointResetter struct {
+	logger        mon.Logger
+	clock         clock.Clock
+	kinesisClient kinesisiface.KinesisAPI
+	dynamoClient  dynamodbiface.DynamoDBAPI
+	settings      KinsumerSettings
+	maxClientAge  time.Duration
+}
+
+// NewCheckpointResetter resets the checkpoints kinsumer stores for the application in the table
+// <applicationName>_checkpoints, e.g. to replay a stream after an incident. The consumer has to be stopped before:
+// the reset fails if a client of the application sent a heartbeat within the last five shard checks, the same time
+// after which kinsumer considers a client dead.
+func NewCheckpointResetter(config cfg.Config, logger mon.Logger, settings KinsumerSettings) CheckpointResetter {
+	kinesisClient := cloud.GetKinesisClient(config, logger)
+	dynamoClient := cloud.GetDynamoDbClient(config, logger)
+	maxClientAge := config.GetDuration("aws_kinesis_shard_check_freq") * time.Second * 5
+
+	return NewCheckpointResetterWithInterfaces(logger, clock.NewRealClock(), kinesisClient, dynamoClient, settings, maxClientAge)
+}
+
+func NewCheckpointResetterWithInterfaces(logger mon.Logger, clock clock.Clock, kinesisClient kinesisiface.KinesisAPI, dynamoClient dynamodbiface.DynamoDBAPI, settings KinsumerSettings, maxClientAge time.Duration) CheckpointResetter {
+	return &checkpointResetter{
+		logger:        logger.WithChannel("kinsumer"),
+		clock:         clock,
+		kinesisClient: kinesisClient,
+		dynamoClient:  dynamoClient,
+		settings:      settings,
+		maxClientAge:  maxClientAge,
+	}
+}
+
+// ResetCheckpoints sets the checkpoints to the position. kinsumer can only continue after a sequence number, so
+// CheckpointAtTimestamp sets the checkpoint to the last record before the timestamp, or to the start of the shard if
+// there is none. This way the first record at the timestamp and all records written until the consumer is started
+// again are read.
+func (r *checkpointResetter) ResetCheckpoints(ctx context.Context, position CheckpointPosition, shardIds ...string) (int, error) {
+	switch position.Type {
+	case CheckpointTrimHorizon, CheckpointLatest, CheckpointAtTimestamp:
+	default:
+		return 0, fmt.Errorf("unknown checkpoint position %s", position.Type)
+	}
+
+	if err := r.checkStopped(ctx); err != nil {
+		return 0, err
+	}
+
+	var err error
+
+	if len(shardIds) == 0 {
+		if shardIds, err = r.listShards(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	changed := 0
+
+	for _, shardId := range shardIds {
+		sequenceNumber, err := r.getSequenceNumber(ctx, shardId, position)
+
+		if err != nil {
+			return changed, fmt.Errorf("can not get the sequence number of shard %s at %s: %w", shardId, position.Type, err)
+		}
+
+		ok, err := r.resetCheckpoint(ctx, shardId, sequenceNumber)
+
+		if err != nil {
+			return changed, fmt.Errorf("can not reset the checkpoint of shard %s: %w", shardId, err)
+		}
+
+		if ok {
+			changed++
+		}
+	}
+
+	r.logger.Infof("reset %d of %d checkpoints of application %s on stream %s to %s", changed, len(shardIds), r.settings.ApplicationName, r.settings.StreamName, position.Type)
+
+	return changed, nil
+}
+
+func (r *checkpointResetter) checkStopped(ctx context.Context) error {
+	cutoff := r.clock.Now().Add(-r.maxClientAge).UnixNano()
+
+	out, err := r.dynamoClient.ScanWithContext(ctx, &dynamodb.ScanInput{
+		TableName:        aws.String(r.settings.ApplicationName + "_clients"),
+		ConsistentRead:   aws.Bool(true),
+		FilterExpression: aws.String("LastUpdate > :cutoff"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":cutoff": {N: aws.String(fmt.Sprint(cutoff))},
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not check for running clients of application %s: %w", r.settings.ApplicationName, err)
+	}
+
+	if len(out.Items) > 0 {
+		return fmt.Errorf("the application %s still has %d running clients on stream %s: stop them before resetting the checkpoints", r.settings.ApplicationName, len(out.Items), r.settings.StreamName)
+	}
+
+	return nil
+}
+
+func (r *checkpointResetter) listShards(ctx context.Context) ([]string, error) {
+	shardIds := make([]string, 0)
+	input := &kinesis.ListShardsInput{
+		StreamName: aws.String(r.settings.StreamName),
+	}
+
+	for {
+		out, err := r.kinesisClient.ListShardsWithContext(ctx, input)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not list the shards of stream %s: %w", r.settings.StreamName, err)
+		}
+
+		for _, shard := range out.Shards {
+			shardIds = append(shardIds, aws.StringValue(shard.ShardId))
+		}
+
+		if out.NextToken == nil {
+			return shardIds, nil
+		}
+
+		input = &kinesis.ListShardsInput{
+			NextToken: out.NextToken,
+		}
+	}
+}
+
+// getSequenceNumber returns the sequence number kinsumer continues after, nil to read the shard from the start.
+func (r *checkpointResetter) getSequenceNumber(ctx context.Context, shardId string, position CheckpointPosition) (*string, error) {
+	switch position.Type {
+	case CheckpointTrimHorizon:
+		return nil, nil
+	case CheckpointLatest:
+		return aws.String(kinsumerSequenceNumberLatest), nil
+	}
+
+	for lookback := checkpointResetLookback; lookback <= checkpointResetMaxLookback; lookback *= 2 {
+		sequenceNumber, found, err := r.findLastRecordBefore(ctx, shardId, position.Timestamp, lookback)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			return sequenceNumber, nil
+		}
+	}
+
+	return nil, fmt.Errorf("found no record within %s before %s", checkpointResetMaxLookback, position.Timestamp.Format(time.RFC3339))
+}
+
+// findLastRecordBefore searches the last record before the timestamp, starting to read the shard at the lookback
+// before it. If there is no record in between, it only reports a result if the shard has no records before the
+// window either: nil to read the shard from the start.
+func (r *checkpointResetter) findLastRecordBefore(ctx context.Context, shardId string, timestamp time.Time, lookback time.Duration) (*string, bool, error) {
+	iterator, err := r.getShardIterator(ctx, shardId, kinesis.ShardIteratorTypeAtTimestamp, timestamp.Add(-lookback))
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var first, last *string
+
+	err = r.readShard(ctx, iterator, func(record *kinesis.Record) bool {
+		if first == nil {
+			first = record.SequenceNumber
+		}
+
+		if !aws.TimeValue(record.ApproximateArrivalTimestamp).Before(timestamp) {
+			return false
+		}
+
+		last = record.SequenceNumber
+
+		return true
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if last != nil {
+		return last, true, nil
+	}
+
+	if iterator, err = r.getShardIterator(ctx, shardId, kinesis.ShardIteratorTypeTrimHorizon, time.Time{}); err != nil {
+		return nil, false, err
+	}
+
+	var oldest *string
+
+	err = r.readShard(ctx, iterator, func(record *kinesis.Record) bool {
+		oldest = record.SequenceNumber
+
+		return false
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if oldest == nil || aws.StringValue(oldest) == aws.StringValue(first) {
+		return nil, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (r *checkpointResetter) getShardIterator(ctx context.Context, shardId string, iteratorType string, timestamp time.Time) (*string, error) {
+	input := &kinesis.GetShardIteratorInput{
+		StreamName:        aws.String(r.settings.StreamName),
+		ShardId:           aws.String(shardId),
+		ShardIteratorType: aws.String(iteratorType),
+	}
+
+	if iteratorType == kinesis.ShardIteratorTypeAtTimestamp {
+		input.Timestamp = aws.Time(timestamp)
+	}
+
+	out, err := r.kinesisClient.GetShardIteratorWithContext(ctx, input)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return out.ShardIterator, nil
+}
+
+// readShard passes the records to visit until it returns false or the tip or the end of the shard is reached.
+func (r *checkpointResetter) readShard(ctx context.Context, iterator *string, visit func(record *kinesis.Record) bool) error {
+	for i := 0; iterator != nil; i++ {
+		if i == checkpointResetMaxReads {
+			return fmt.Errorf("stopped reading the shard after %d reads", checkpointResetMaxReads)
+		}
+
+		out, err := r.kinesisClient.GetRecordsWithContext(ctx, &kinesis.GetRecordsInput{
+			ShardIterator: iterator,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, record := range out.Records {
+			if !visit(record) {
+				return nil
+			}
+		}
+
+		// GetRecords may return no records while there are more records further in the shard
+		if aws.Int64Value(out.MillisBehindLatest) == 0 {
+			return nil
+		}
+
+		iterator = out.NextShardIterator
+	}
+
+	return nil
+}
+
+// resetCheckpoint writes the sequence number to the checkpoint of the shard unless it is owned by a live client. It
+// returns false if the checkpoint already had the sequence number.
+func (r *checkpointResetter) resetCheckpoint(ctx context.Context, shardId string, sequenceNumber *string) (bool, error) {
+	tableName := aws.String(r.settings.ApplicationName + "_checkpoints")
+	key := map[string]*dynamodb.AttributeValue{
+		"Shard": {S: aws.String(shardId)},
+	}
+
+	current, err := r.dynamoClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      tableName,
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	if currentSequenceNumber(current.Item) == aws.StringValue(sequenceNumber) && (current.Item != nil || sequenceNumber == nil) {
+		return false, nil
+	}
+
+	now := r.clock.Now()
+	cutoff := now.Add(-r.maxClientAge).UnixNano()
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:           tableName,
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_not_exists(OwnerID) OR attribute_type(OwnerID, :nullType) OR LastUpdate <= :cutoff"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":nullType":      {S: aws.String("NULL")},
+			":cutoff":        {N: aws.String(fmt.Sprint(cutoff))},
+			":lastUpdate":    {N: aws.String(fmt.Sprint(now.UnixNano()))},
+			":lastUpdateRfc": {S: aws.String(now.UTC().Format(time.RFC1123Z))},
+		},
+	}
+
+	if sequenceNumber == nil {
+		input.UpdateExpression = aws.String("SET LastUpdate = :lastUpdate, LastUpdateRFC = :lastUpdateRfc REMOVE SequenceNumber")
+	} else {
+		input.UpdateExpression = aws.String("SET LastUpdate = :lastUpdate, LastUpdateRFC = :lastUpdateRfc, SequenceNumber = :sequenceNumber")
+		input.ExpressionAttributeValues[":sequenceNumber"] = &dynamodb.AttributeValue{S: sequenceNumber}
+	}
+
+	_, err = r.dynamoClient.UpdateItemWithContext(ctx, input)
+
+	if gosoAws.IsAwsError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+		return false, fmt.Errorf("the shard is owned by a running client")
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func currentSequenceNumber(item map[string]*dynamodb.AttributeValue) string {
+	if value, ok := item["SequenceNumber"]; ok && value.S != nil {
+		return *value.S
+	}
+
+	return ""
+}
@@ -0,0 +1,197 @@
+package kinesis_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	gosoKinesis "github.com/applike/gosoline/pkg/cloud/aws/kinesis"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func getCheckpointResetter() (gosoKinesis.CheckpointResetter, *cloudMocks.KinesisAPI, *cloudMocks.DynamoDBAPI) {
+	logger := monMocks.NewLoggerMockedAll()
+	kinesisClient := new(cloudMocks.KinesisAPI)
+	dynamoClient := new(cloudMocks.DynamoDBAPI)
+	settings := gosoKinesis.KinsumerSettings{
+		StreamName:      "stream",
+		ApplicationName: "app",
+	}
+
+	resetter := gosoKinesis.NewCheckpointResetterWithInterfaces(logger, clock.NewFakeClock(), kinesisClient, dynamoClient, settings, time.Minute)
+
+	return resetter, kinesisClient, dynamoClient
+}
+
+func matchTable(name string) interface{} {
+	return mock.MatchedBy(func(input interface{}) bool {
+		switch input := input.(type) {
+		case *dynamodb.ScanInput:
+			return aws.StringValue(input.TableName) == name
+		case *dynamodb.GetItemInput:
+			return aws.StringValue(input.TableName) == name
+		}
+
+		return false
+	})
+}
+
+func TestCheckpointResetter_ResetCheckpoints_Running(t *testing.T) {
+	resetter, _, dynamoClient := getCheckpointResetter()
+
+	dynamoClient.On("ScanWithContext", mock.Anything, matchTable("app_clients")).Return(&dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"ID": {S: aws.String("client")}},
+		},
+	}, nil).Once()
+
+	changed, err := resetter.ResetCheckpoints(context.Background(), gosoKinesis.CheckpointAtTrimHorizon())
+
+	assert.EqualError(t, err, "the application app still has 1 running clients on stream stream: stop them before resetting the checkpoints")
+	assert.Equal(t, 0, changed)
+
+	dynamoClient.AssertExpectations(t)
+}
+
+func TestCheckpointResetter_ResetCheckpoints(t *testing.T) {
+	resetter, kinesisClient, dynamoClient := getCheckpointResetter()
+
+	dynamoClient.On("ScanWithContext", mock.Anything, matchTable("app_clients")).Return(&dynamodb.ScanOutput{}, nil)
+
+	kinesisClient.On("ListShardsWithContext", mock.Anything, &kinesis.ListShardsInput{
+		StreamName: aws.String("stream"),
+	}).Return(&kinesis.ListShardsOutput{
+		Shards: []*kinesis.Shard{
+			{ShardId: aws.String("shard-1")},
+			{ShardId: aws.String("shard-2")},
+		},
+	}, nil).Once()
+
+	dynamoClient.On("GetItemWithContext", mock.Anything, &dynamodb.GetItemInput{
+		TableName:      aws.String("app_checkpoints"),
+		Key:            map[string]*dynamodb.AttributeValue{"Shard": {S: aws.String("shard-1")}},
+		ConsistentRead: aws.Bool(true),
+	}).Return(&dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"Shard":          {S: aws.String("shard-1")},
+			"SequenceNumber": {S: aws.String("123")},
+		},
+	}, nil)
+	dynamoClient.On("GetItemWithContext", mock.Anything, &dynamodb.GetItemInput{
+		TableName:      aws.String("app_checkpoints"),
+		Key:            map[string]*dynamodb.AttributeValue{"Shard": {S: aws.String("shard-2")}},
+		ConsistentRead: aws.Bool(true),
+	}).Return(&dynamodb.GetItemOutput{}, nil)
+
+	dynamoClient.On("UpdateItemWithContext", mock.Anything, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.StringValue(input.Key["Shard"].S) == "shard-1" &&
+			aws.StringValue(input.UpdateExpression) == "SET LastUpdate = :lastUpdate, LastUpdateRFC = :lastUpdateRfc REMOVE SequenceNumber"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	changed, err := resetter.ResetCheckpoints(context.Background(), gosoKinesis.CheckpointAtTrimHorizon())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed, "the checkpoint of shard-2 already starts at the trim horizon")
+
+	timestamp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	kinesisClient.On("GetShardIteratorWithContext", mock.Anything, &kinesis.GetShardIteratorInput{
+		StreamName:        aws.String("stream"),
+		ShardId:           aws.String("shard-2"),
+		ShardIteratorType: aws.String(kinesis.ShardIteratorTypeAtTimestamp),
+		Timestamp:         aws.Time(timestamp.Add(-time.Minute)),
+	}).Return(&kinesis.GetShardIteratorOutput{ShardIterator: aws.String("iterator")}, nil).Once()
+	kinesisClient.On("GetRecordsWithContext", mock.Anything, &kinesis.GetRecordsInput{
+		ShardIterator: aws.String("iterator"),
+	}).Return(&kinesis.GetRecordsOutput{
+		Records: []*kinesis.Record{
+			{SequenceNumber: aws.String("455"), ApproximateArrivalTimestamp: aws.Time(timestamp.Add(-time.Second))},
+			{SequenceNumber: aws.String("456"), ApproximateArrivalTimestamp: aws.Time(timestamp)},
+		},
+	}, nil).Once()
+
+	dynamoClient.On("UpdateItemWithContext", mock.Anything, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.StringValue(input.Key["Shard"].S) == "shard-2" &&
+			aws.StringValue(input.ExpressionAttributeValues[":sequenceNumber"].S) == "455"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	changed, err = resetter.ResetCheckpoints(context.Background(), gosoKinesis.CheckpointAt(timestamp), "shard-2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	kinesisClient.AssertExpectations(t)
+	dynamoClient.AssertExpectations(t)
+}
+
+func TestCheckpointResetter_ResetCheckpoints_AtTimestampWithoutPriorRecord(t *testing.T) {
+	resetter, kinesisClient, dynamoClient := getCheckpointResetter()
+	timestamp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dynamoClient.On("ScanWithContext", mock.Anything, matchTable("app_clients")).Return(&dynamodb.ScanOutput{}, nil)
+
+	iterator := func(iteratorType string, timestamp *time.Time, iterator string) {
+		kinesisClient.On("GetShardIteratorWithContext", mock.Anything, &kinesis.GetShardIteratorInput{
+			StreamName:        aws.String("stream"),
+			ShardId:           aws.String("shard-1"),
+			ShardIteratorType: aws.String(iteratorType),
+			Timestamp:         timestamp,
+		}).Return(&kinesis.GetShardIteratorOutput{ShardIterator: aws.String(iterator)}, nil).Once()
+	}
+	records := func(iterator string, records ...*kinesis.Record) {
+		kinesisClient.On("GetRecordsWithContext", mock.Anything, &kinesis.GetRecordsInput{
+			ShardIterator: aws.String(iterator),
+		}).Return(&kinesis.GetRecordsOutput{Records: records}, nil).Once()
+	}
+
+	atTimestamp := &kinesis.Record{SequenceNumber: aws.String("456"), ApproximateArrivalTimestamp: aws.Time(timestamp)}
+	older := &kinesis.Record{SequenceNumber: aws.String("123"), ApproximateArrivalTimestamp: aws.Time(timestamp.Add(-90 * time.Second))}
+
+	// there is an older record outside of the first window, so the window is widened
+	iterator(kinesis.ShardIteratorTypeAtTimestamp, aws.Time(timestamp.Add(-time.Minute)), "one-minute")
+	records("one-minute", atTimestamp)
+	iterator(kinesis.ShardIteratorTypeTrimHorizon, nil, "trim-horizon")
+	records("trim-horizon", older)
+	iterator(kinesis.ShardIteratorTypeAtTimestamp, aws.Time(timestamp.Add(-2*time.Minute)), "two-minutes")
+	records("two-minutes", older, atTimestamp)
+
+	dynamoClient.On("GetItemWithContext", mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	dynamoClient.On("UpdateItemWithContext", mock.Anything, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		sequenceNumber, ok := input.ExpressionAttributeValues[":sequenceNumber"]
+
+		return ok && aws.StringValue(sequenceNumber.S) == "123"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	changed, err := resetter.ResetCheckpoints(context.Background(), gosoKinesis.CheckpointAt(timestamp), "shard-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	// the first record of the shard is at the timestamp, so the shard is read from the start
+	iterator(kinesis.ShardIteratorTypeAtTimestamp, aws.Time(timestamp.Add(-time.Minute)), "one-minute")
+	records("one-minute", atTimestamp)
+	iterator(kinesis.ShardIteratorTypeTrimHorizon, nil, "trim-horizon")
+	records("trim-horizon", atTimestamp)
+
+	dynamoClient.On("GetItemWithContext", mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"Shard":          {S: aws.String("shard-1")},
+			"SequenceNumber": {S: aws.String("123")},
+		},
+	}, nil).Once()
+	dynamoClient.On("UpdateItemWithContext", mock.Anything, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.StringValue(input.UpdateExpression) == "SET LastUpdate = :lastUpdate, LastUpdateRFC = :lastUpdateRfc REMOVE SequenceNumber"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	changed, err = resetter.ResetCheckpoints(context.Background(), gosoKinesis.CheckpointAt(timestamp), "shard-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	kinesisClient.AssertExpectations(t)
+	dynamoClient.AssertExpectations(t)
+}
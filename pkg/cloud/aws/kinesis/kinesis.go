@@ -1,8 +1,10 @@
 package kinesis
 
 import (
+	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kinesis"
@@ -53,3 +55,24 @@ func CreateKinesisStream(config cfg.Config, logger mon.Logger, client kinesisifa
 
 	return nil
 }
+
+// NewStreamHealthcheck reports the stream as healthy if it can be described and is not being deleted.
+func NewStreamHealthcheck(client kinesisiface.KinesisAPI, streamName string) healthcheck.Healthchecker {
+	return healthcheck.HealthcheckerFunc(func(ctx context.Context) error {
+		out, err := client.DescribeStreamSummaryWithContext(ctx, &kinesis.DescribeStreamSummaryInput{
+			StreamName: aws.String(streamName),
+		})
+
+		if err != nil {
+			return fmt.Errorf("can not describe kinesis stream %s: %w", streamName, err)
+		}
+
+		status := aws.StringValue(out.StreamDescriptionSummary.StreamStatus)
+
+		if status == kinesis.StreamStatusDeleting {
+			return fmt.Errorf("kinesis stream %s has the status %s", streamName, status)
+		}
+
+		return nil
+	})
+}
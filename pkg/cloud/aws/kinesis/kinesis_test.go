@@ -0,0 +1,40 @@
+package kinesis_test
+
+import (
+	"context"
+	"fmt"
+	gosoKinesis "github.com/applike/gosoline/pkg/cloud/aws/kinesis"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStreamHealthcheck(t *testing.T) {
+	ctx := context.Background()
+	client := new(cloudMocks.KinesisAPI)
+	input := &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String("stream"),
+	}
+
+	client.On("DescribeStreamSummaryWithContext", ctx, input).Return(&kinesis.DescribeStreamSummaryOutput{
+		StreamDescriptionSummary: &kinesis.StreamDescriptionSummary{
+			StreamStatus: aws.String(kinesis.StreamStatusActive),
+		},
+	}, nil).Once()
+	client.On("DescribeStreamSummaryWithContext", ctx, input).Return(&kinesis.DescribeStreamSummaryOutput{
+		StreamDescriptionSummary: &kinesis.StreamDescriptionSummary{
+			StreamStatus: aws.String(kinesis.StreamStatusDeleting),
+		},
+	}, nil).Once()
+	client.On("DescribeStreamSummaryWithContext", ctx, input).Return(nil, fmt.Errorf("unreachable")).Once()
+
+	check := gosoKinesis.NewStreamHealthcheck(client, "stream")
+
+	assert.NoError(t, check.Check(ctx))
+	assert.EqualError(t, check.Check(ctx), "kinesis stream stream has the status DELETING")
+	assert.EqualError(t, check.Check(ctx), "can not describe kinesis stream stream: unreachable")
+
+	client.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/cloud"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/twinj/uuid"
 	"github.com/twitchscience/kinsumer"
@@ -34,6 +35,11 @@ func (k *KinsumerSettings) GetResourceName() string {
 	return k.StreamName
 }
 
+// NewKinsumer creates a kinsumer client which distributes the shards of the stream across all running instances of the
+// application using dynamodb. Every instance sends a heartbeat to the clients table each aws_kinesis_shard_check_freq
+// seconds and the owner of a shard is recorded in the checkpoints table, so a shard is only read by a single instance
+// at a time. Instances without a heartbeat for five shard checks are considered dead and the leader, which acts every
+// aws_kinesis_leader_action_freq seconds, rebalances their shards to the remaining instances.
 func NewKinsumer(config cfg.Config, logger mon.Logger, settings KinsumerSettings) (Kinsumer, error) {
 	kinesisClient := cloud.GetKinesisClient(config, logger)
 	dynamoDbClient := cloud.GetDynamoDbClient(config, logger)
@@ -57,9 +63,9 @@ func NewKinsumer(config cfg.Config, logger mon.Logger, settings KinsumerSettings
 	leaderActionFreq := config.GetDuration("aws_kinesis_leader_action_freq") * time.Second
 
 	kinsumerConfig := kinsumer.NewConfig()
-	kinsumerConfig.WithShardCheckFrequency(shardCheckFreq)
-	kinsumerConfig.WithLeaderActionFrequency(leaderActionFreq)
-	kinsumerConfig.WithLogger(kinsumerLogger{
+	kinsumerConfig = kinsumerConfig.WithShardCheckFrequency(shardCheckFreq)
+	kinsumerConfig = kinsumerConfig.WithLeaderActionFrequency(leaderActionFreq)
+	kinsumerConfig = kinsumerConfig.WithLogger(kinsumerLogger{
 		logger: logger,
 	})
 
@@ -75,5 +81,7 @@ func NewKinsumer(config cfg.Config, logger mon.Logger, settings KinsumerSettings
 		return nil, fmt.Errorf("error creating kinsumer dynamo db tables: %w", err)
 	}
 
+	healthcheck.Register(fmt.Sprintf("kinesis.%s", settings.StreamName), NewStreamHealthcheck(kinesisClient, settings.StreamName))
+
 	return client, nil
 }
@@ -25,6 +25,20 @@ func (_m *Reader) Run(ctx context.Context) error {
 	return r0
 }
 
+// Shutdown provides a mock function with given fields: ctx
+func (_m *Reader) Shutdown(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Stop provides a mock function with given fields:
 func (_m *Reader) Stop() {
 	_m.Called()
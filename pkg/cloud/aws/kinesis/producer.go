@@ -0,0 +1,298 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/cloud"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/uuid"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"sync"
+	"time"
+)
+
+const (
+	// limits of a single PutRecords request, see https://docs.aws.amazon.com/kinesis/latest/APIReference/API_PutRecords.html
+	producerBatchRecordsMax = 500
+	producerBatchBytesMax   = 5 * 1024 * 1024
+	producerRecordBytesMax  = 1024 * 1024
+
+	producerDefaultMaxRetries = 3
+	producerDefaultRetryDelay = 100 * time.Millisecond
+)
+
+// PartitionKeyFunc decides to which shard a record is written.
+type PartitionKeyFunc func(data []byte) string
+
+// NewRandomPartitionKey spreads the records evenly over all shards.
+func NewRandomPartitionKey() PartitionKeyFunc {
+	uuidGen := uuid.New()
+
+	return func(_ []byte) string {
+		return uuidGen.NewV4()
+	}
+}
+
+type ProducerSettings struct {
+	StreamName string
+	// FlushInterval defaults to aws_kinesis_producer_flush_freq seconds (or 1 second if not configured)
+	FlushInterval time.Duration
+	// MaxRetries defines how often failed or throttled records are written again
+	MaxRetries int
+	RetryDelay time.Duration
+	// PartitionKey defaults to a random partition key per record
+	PartitionKey PartitionKeyFunc
+}
+
+func (s *ProducerSettings) GetResourceName() string {
+	return s.StreamName
+}
+
+// PutResult is delivered to the caller of Put once the record was written or finally failed.
+type PutResult struct {
+	ShardId        string
+	SequenceNumber string
+	Err            error
+}
+
+//go:generate mockery -name Producer
+type Producer interface {
+	// Put buffers a record until the next flush. The returned channel receives exactly one result.
+	Put(ctx context.Context, data []byte) (<-chan PutResult, error)
+	// Flush writes all buffered records and waits until they are written.
+	Flush(ctx context.Context) error
+	// Run flushes the buffer every flush interval until the context is canceled and flushes a last time on shutdown.
+	Run(ctx context.Context) error
+}
+
+type producerRecord struct {
+	entry  *kinesis.PutRecordsRequestEntry
+	result chan PutResult
+	size   int
+	err    error
+}
+
+type producer struct {
+	logger   mon.Logger
+	clock    clock.Clock
+	client   kinesisiface.KinesisAPI
+	settings ProducerSettings
+
+	lck         sync.Mutex
+	flushLck    sync.Mutex
+	buffer      []*producerRecord
+	bufferBytes int
+}
+
+func NewProducer(config cfg.Config, logger mon.Logger, settings ProducerSettings) (Producer, error) {
+	client := cloud.GetKinesisClient(config, logger)
+
+	if err := CreateKinesisStream(config, logger, client, &settings); err != nil {
+		return nil, fmt.Errorf("failed to create kinesis stream: %w", err)
+	}
+
+	if settings.FlushInterval == 0 {
+		settings.FlushInterval = config.GetDuration("aws_kinesis_producer_flush_freq", 1) * time.Second
+	}
+
+	logger = logger.WithChannel("kinesis_producer").WithFields(mon.Fields{
+		"outputStream": settings.StreamName,
+	})
+
+	return NewProducerWithInterfaces(logger, clock.NewRealClock(), client, settings), nil
+}
+
+func NewProducerWithInterfaces(logger mon.Logger, clock clock.Clock, client kinesisiface.KinesisAPI, settings ProducerSettings) Producer {
+	if settings.FlushInterval == 0 {
+		settings.FlushInterval = time.Second
+	}
+
+	if settings.MaxRetries == 0 {
+		settings.MaxRetries = producerDefaultMaxRetries
+	}
+
+	if settings.RetryDelay == 0 {
+		settings.RetryDelay = producerDefaultRetryDelay
+	}
+
+	if settings.PartitionKey == nil {
+		settings.PartitionKey = NewRandomPartitionKey()
+	}
+
+	return &producer{
+		logger:   logger,
+		clock:    clock,
+		client:   client,
+		settings: settings,
+		buffer:   make([]*producerRecord, 0, producerBatchRecordsMax),
+	}
+}
+
+func (p *producer) Put(ctx context.Context, data []byte) (<-chan PutResult, error) {
+	partitionKey := p.settings.PartitionKey(data)
+	size := len(data) + len(partitionKey)
+
+	if size > producerRecordBytesMax {
+		return nil, fmt.Errorf("record of %d bytes exceeds the limit of %d bytes", size, producerRecordBytesMax)
+	}
+
+	record := &producerRecord{
+		entry: &kinesis.PutRecordsRequestEntry{
+			Data:         data,
+			PartitionKey: aws.String(partitionKey),
+		},
+		result: make(chan PutResult, 1),
+		size:   size,
+	}
+
+	p.lck.Lock()
+	full := len(p.buffer)+1 > producerBatchRecordsMax || p.bufferBytes+size > producerBatchBytesMax
+	p.lck.Unlock()
+
+	if full {
+		if err := p.Flush(ctx); err != nil {
+			p.logger.WithContext(ctx).Warnf("not all records could be written while flushing a full buffer: %s", err.Error())
+		}
+	}
+
+	p.lck.Lock()
+	p.buffer = append(p.buffer, record)
+	p.bufferBytes += size
+	p.lck.Unlock()
+
+	return record.result, nil
+}
+
+func (p *producer) Flush(ctx context.Context) error {
+	p.flushLck.Lock()
+	defer p.flushLck.Unlock()
+
+	p.lck.Lock()
+	records := p.buffer
+	p.buffer = make([]*producerRecord, 0, producerBatchRecordsMax)
+	p.bufferBytes = 0
+	p.lck.Unlock()
+
+	failed := 0
+
+	for _, batch := range p.buildBatches(records) {
+		failed += p.writeBatch(ctx, batch)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d out of %d records could not be written to stream %s", failed, len(records), p.settings.StreamName)
+	}
+
+	return nil
+}
+
+func (p *producer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			// the context is already canceled, but we still want to get rid of the remaining records
+			if err := p.Flush(context.Background()); err != nil {
+				return fmt.Errorf("could not flush the kinesis producer on shutdown: %w", err)
+			}
+
+			return nil
+
+		case <-p.clock.After(p.settings.FlushInterval):
+			if err := p.Flush(ctx); err != nil {
+				p.logger.WithContext(ctx).Error(err, "could not flush the kinesis producer")
+			}
+		}
+	}
+}
+
+func (p *producer) buildBatches(records []*producerRecord) [][]*producerRecord {
+	batches := make([][]*producerRecord, 0)
+	batch := make([]*producerRecord, 0, producerBatchRecordsMax)
+	batchBytes := 0
+
+	for _, record := range records {
+		if len(batch) == producerBatchRecordsMax || batchBytes+record.size > producerBatchBytesMax {
+			batches = append(batches, batch)
+			batch = make([]*producerRecord, 0, producerBatchRecordsMax)
+			batchBytes = 0
+		}
+
+		batch = append(batch, record)
+		batchBytes += record.size
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// writeBatch writes the batch and retries all records with an error code. Every record receives its result and the
+// number of finally failed records is returned.
+func (p *producer) writeBatch(ctx context.Context, batch []*producerRecord) int {
+	pending := p.putRecords(ctx, batch)
+
+	for attempt := 1; len(pending) > 0 && attempt <= p.settings.MaxRetries && ctx.Err() == nil; attempt++ {
+		p.logger.WithContext(ctx).Warnf("retrying %d out of %d records after attempt %d: %s", len(pending), len(batch), attempt, pending[0].err.Error())
+
+		select {
+		case <-ctx.Done():
+			continue
+		case <-p.clock.After(p.settings.RetryDelay):
+		}
+
+		pending = p.putRecords(ctx, pending)
+	}
+
+	for _, record := range pending {
+		record.result <- PutResult{Err: record.err}
+	}
+
+	return len(pending)
+}
+
+// putRecords sends the records and delivers the result to every successful record. The records which have to be
+// written again are returned with the reason of their failure.
+func (p *producer) putRecords(ctx context.Context, records []*producerRecord) []*producerRecord {
+	entries := make([]*kinesis.PutRecordsRequestEntry, len(records))
+
+	for i, record := range records {
+		entries[i] = record.entry
+	}
+
+	output, err := p.client.PutRecordsWithContext(ctx, &kinesis.PutRecordsInput{
+		Records:    entries,
+		StreamName: aws.String(p.settings.StreamName),
+	})
+
+	if err != nil {
+		for _, record := range records {
+			record.err = fmt.Errorf("could not put records to stream %s: %w", p.settings.StreamName, err)
+		}
+
+		return records
+	}
+
+	failed := make([]*producerRecord, 0)
+
+	for i, outputRecord := range output.Records {
+		if outputRecord.ErrorCode != nil {
+			records[i].err = fmt.Errorf("record failed with %s: %s", aws.StringValue(outputRecord.ErrorCode), aws.StringValue(outputRecord.ErrorMessage))
+			failed = append(failed, records[i])
+
+			continue
+		}
+
+		records[i].result <- PutResult{
+			ShardId:        aws.StringValue(outputRecord.ShardId),
+			SequenceNumber: aws.StringValue(outputRecord.SequenceNumber),
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,123 @@
+package kinesis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	gosoKinesis "github.com/applike/gosoline/pkg/cloud/aws/kinesis"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func getProducer(client *cloudMocks.KinesisAPI) gosoKinesis.Producer {
+	logger := monMocks.NewLoggerMockedAll()
+
+	return gosoKinesis.NewProducerWithInterfaces(logger, clock.NewRealClock(), client, gosoKinesis.ProducerSettings{
+		StreamName: "stream",
+		MaxRetries: 2,
+		RetryDelay: time.Millisecond,
+		PartitionKey: func(data []byte) string {
+			return string(data)
+		},
+	})
+}
+
+func matchRecords(keys ...string) interface{} {
+	return mock.MatchedBy(func(input *kinesis.PutRecordsInput) bool {
+		if aws.StringValue(input.StreamName) != "stream" || len(input.Records) != len(keys) {
+			return false
+		}
+
+		for i, record := range input.Records {
+			if aws.StringValue(record.PartitionKey) != keys[i] {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func TestProducer_Flush_RetriesFailedRecords(t *testing.T) {
+	client := new(cloudMocks.KinesisAPI)
+	client.On("PutRecordsWithContext", mock.Anything, matchRecords("a", "b")).Return(&kinesis.PutRecordsOutput{
+		Records: []*kinesis.PutRecordsResultEntry{
+			{ShardId: aws.String("shard-1"), SequenceNumber: aws.String("1")},
+			{ErrorCode: aws.String("ProvisionedThroughputExceededException"), ErrorMessage: aws.String("slow down")},
+		},
+	}, nil).Once()
+	client.On("PutRecordsWithContext", mock.Anything, matchRecords("b")).Return(&kinesis.PutRecordsOutput{
+		Records: []*kinesis.PutRecordsResultEntry{
+			{ShardId: aws.String("shard-2"), SequenceNumber: aws.String("2")},
+		},
+	}, nil).Once()
+
+	producer := getProducer(client)
+
+	resultA, err := producer.Put(context.Background(), []byte("a"))
+	assert.NoError(t, err)
+	resultB, err := producer.Put(context.Background(), []byte("b"))
+	assert.NoError(t, err)
+
+	err = producer.Flush(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, gosoKinesis.PutResult{ShardId: "shard-1", SequenceNumber: "1"}, <-resultA)
+	assert.Equal(t, gosoKinesis.PutResult{ShardId: "shard-2", SequenceNumber: "2"}, <-resultB)
+	client.AssertExpectations(t)
+}
+
+func TestProducer_Flush_GivesUpAfterMaxRetries(t *testing.T) {
+	client := new(cloudMocks.KinesisAPI)
+	client.On("PutRecordsWithContext", mock.Anything, matchRecords("a")).Return(nil, errors.New("connection reset")).Times(3)
+
+	producer := getProducer(client)
+
+	result, err := producer.Put(context.Background(), []byte("a"))
+	assert.NoError(t, err)
+
+	err = producer.Flush(context.Background())
+	assert.EqualError(t, err, "1 out of 1 records could not be written to stream stream")
+
+	res := <-result
+	assert.EqualError(t, res.Err, "could not put records to stream stream: connection reset")
+	client.AssertExpectations(t)
+}
+
+func TestProducer_Put_FlushesFullBatches(t *testing.T) {
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%03d", i)
+	}
+
+	output := &kinesis.PutRecordsOutput{
+		Records: make([]*kinesis.PutRecordsResultEntry, 500),
+	}
+	for i := range output.Records {
+		output.Records[i] = &kinesis.PutRecordsResultEntry{}
+	}
+
+	client := new(cloudMocks.KinesisAPI)
+	client.On("PutRecordsWithContext", mock.Anything, matchRecords(keys...)).Return(output, nil).Once()
+
+	producer := getProducer(client)
+
+	for _, key := range keys {
+		_, err := producer.Put(context.Background(), []byte(key))
+		assert.NoError(t, err)
+	}
+
+	client.AssertNotCalled(t, "PutRecordsWithContext", mock.Anything, mock.Anything)
+
+	_, err := producer.Put(context.Background(), []byte("500"))
+	assert.NoError(t, err)
+
+	client.AssertExpectations(t)
+}
@@ -12,6 +12,7 @@ import (
 //go:generate mockery -name Reader
 type Reader interface {
 	Run(ctx context.Context) error
+	Shutdown(ctx context.Context) error
 	Stop()
 }
 
@@ -25,6 +26,7 @@ type reader struct {
 	client   Kinsumer
 	handler  MessageHandler
 	doStop   sync.Once
+	lck      sync.Mutex
 	wg       sync.WaitGroup
 }
 
@@ -48,7 +50,10 @@ func NewReader(config cfg.Config, logger mon.Logger, factory KinsumerFactory, ha
 func (r *reader) Run(ctx context.Context) error {
 	defer r.handler.Done()
 
+	// guarded, as Stop can be called while Run is starting
+	r.lck.Lock()
 	r.wg.Add(1)
+	r.lck.Unlock()
 	defer r.wg.Done()
 
 	logger := r.logger.WithContext(ctx)
@@ -100,8 +105,32 @@ func (r *reader) Run(ctx context.Context) error {
 	}
 }
 
+// Shutdown stops reading new records from the stream and waits until the record currently being handled is done and
+// kinsumer wrote its checkpoints and released the shards it owned. If the context is done before, the reader keeps
+// shutting down in the background and the error of the context is returned. Calling it more than once is safe, every
+// call stops the client only once and waits for the same shutdown.
+func (r *reader) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		r.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("kinesis reader did not shut down in time: %w", ctx.Err())
+	}
+}
+
 func (r *reader) Stop() {
 	r.stopClient()
+
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
 	r.wg.Wait()
 }
 
@@ -0,0 +1,262 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/mon"
+	"sync"
+)
+
+const (
+	metricNameMultiReaderRecordCount = "KinesisRecordCount"
+	metricNameMultiReaderErrorCount  = "KinesisRecordErrorCount"
+)
+
+type MultiReaderSettings struct {
+	// Streams lists the streams to read. Kinsumer keys the checkpoints and the shard owners in the tables of the
+	// application by shard id only, so every stream needs its own ApplicationName to keep its checkpoints apart.
+	Streams []KinsumerSettings
+	// Workers is the number of go routines handling the records of all streams, 1 by default. With more than one
+	// worker, the records of a shard can be handled out of order.
+	Workers int
+}
+
+type multiReaderRecord struct {
+	stream string
+	data   []byte
+}
+
+// multiReaderHandler hands the records of a single stream to the workers of the multi reader
+type multiReaderHandler struct {
+	stream  string
+	records chan multiReaderRecord
+}
+
+func (h multiReaderHandler) Handle(rawMessage []byte) error {
+	h.records <- multiReaderRecord{
+		stream: h.stream,
+		data:   rawMessage,
+	}
+
+	return nil
+}
+
+func (h multiReaderHandler) Done() {
+}
+
+type multiReader struct {
+	logger       mon.Logger
+	metricWriter mon.MetricWriter
+	readers      map[string]Reader
+	handlers     map[string]MessageHandler
+	records      chan multiReaderRecord
+	workers      int
+	doStop       sync.Once
+	lck          sync.Mutex
+	wg           sync.WaitGroup
+}
+
+// NewMultiReader reads several streams with one kinsumer client per stream and hands their records to a shared pool
+// of workers, which pass every record to the handler of its stream. The handlers are keyed by stream name. If a
+// stream fails, the others are stopped as well. The records handled and failed are counted per stream.
+func NewMultiReader(config cfg.Config, logger mon.Logger, factory KinsumerFactory, handlers map[string]MessageHandler, settings MultiReaderSettings) (Reader, error) {
+	defaults := make([]*mon.MetricDatum, 0, len(settings.Streams)*2)
+
+	for _, stream := range settings.Streams {
+		defaults = append(defaults, multiReaderMetrics(stream.StreamName, 0, 0)...)
+	}
+
+	metricWriter := mon.NewMetricDaemonWriter(defaults...)
+
+	return NewMultiReaderWithInterfaces(config, logger, metricWriter, factory, handlers, settings)
+}
+
+func NewMultiReaderWithInterfaces(config cfg.Config, logger mon.Logger, metricWriter mon.MetricWriter, factory KinsumerFactory, handlers map[string]MessageHandler, settings MultiReaderSettings) (Reader, error) {
+	if len(settings.Streams) == 0 {
+		return nil, fmt.Errorf("there are no streams to read")
+	}
+
+	if settings.Workers < 1 {
+		settings.Workers = 1
+	}
+
+	records := make(chan multiReaderRecord)
+	readers := make(map[string]Reader, len(settings.Streams))
+	applications := make(map[string]string, len(settings.Streams))
+
+	for _, stream := range settings.Streams {
+		if _, ok := handlers[stream.StreamName]; !ok {
+			return nil, fmt.Errorf("there is no handler for stream %s", stream.StreamName)
+		}
+
+		if _, ok := readers[stream.StreamName]; ok {
+			return nil, fmt.Errorf("the stream %s is configured more than once", stream.StreamName)
+		}
+
+		if other, ok := applications[stream.ApplicationName]; ok {
+			return nil, fmt.Errorf("the streams %s and %s use the same application name %s, which would mix up their checkpoints", other, stream.StreamName, stream.ApplicationName)
+		}
+
+		applications[stream.ApplicationName] = stream.StreamName
+
+		handler := multiReaderHandler{
+			stream:  stream.StreamName,
+			records: records,
+		}
+
+		reader, err := NewReader(config, logger.WithFields(mon.Fields{"stream": stream.StreamName}), factory, handler, stream)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create reader for stream %s: %w", stream.StreamName, err)
+		}
+
+		readers[stream.StreamName] = reader
+	}
+
+	return &multiReader{
+		logger:       logger,
+		metricWriter: metricWriter,
+		readers:      readers,
+		handlers:     handlers,
+		records:      records,
+		workers:      settings.Workers,
+	}, nil
+}
+
+// Run reads all streams until they are stopped or one of them fails. The Done method of every handler is called
+// once all records were handled.
+func (r *multiReader) Run(ctx context.Context) error {
+	r.lck.Lock()
+	r.wg.Add(1)
+	r.lck.Unlock()
+	defer r.wg.Done()
+
+	workers := coffin.New()
+
+	for i := 0; i < r.workers; i++ {
+		workers.Gof(func() error {
+			r.work(ctx)
+
+			return nil
+		}, "panic while handling kinesis records")
+	}
+
+	readers := coffin.New()
+	stopping := sync.WaitGroup{}
+
+	for stream, reader := range r.readers {
+		stream, reader := stream, reader
+
+		readers.Go(func() error {
+			if err := reader.Run(ctx); err != nil {
+				// stopping the readers waits for all of them to return, including this one, so it has to run apart
+				stopping.Add(1)
+				go func() {
+					defer stopping.Done()
+					r.stopReaders()
+				}()
+
+				return fmt.Errorf("can not read stream %s: %w", stream, err)
+			}
+
+			return nil
+		})
+	}
+
+	err := readers.Wait()
+	stopping.Wait()
+	close(r.records)
+
+	if workerErr := workers.Wait(); workerErr != nil && err == nil {
+		err = workerErr
+	}
+
+	for _, handler := range r.handlers {
+		handler.Done()
+	}
+
+	return err
+}
+
+func (r *multiReader) work(ctx context.Context) {
+	for record := range r.records {
+		err := r.handlers[record.stream].Handle(record.data)
+
+		if err != nil {
+			r.logger.WithContext(ctx).WithFields(mon.Fields{
+				"stream": record.stream,
+			}).Error(err, "could not handle message")
+
+			r.metricWriter.Write(multiReaderMetrics(record.stream, 1, 1))
+
+			continue
+		}
+
+		r.metricWriter.Write(multiReaderMetrics(record.stream, 1, 0))
+	}
+}
+
+// Shutdown stops all streams and waits like the Shutdown of a single reader does.
+func (r *multiReader) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		r.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("kinesis multi reader did not shut down in time: %w", ctx.Err())
+	}
+}
+
+func (r *multiReader) Stop() {
+	r.stopReaders()
+
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	r.wg.Wait()
+}
+
+func (r *multiReader) stopReaders() {
+	r.doStop.Do(func() {
+		wg := sync.WaitGroup{}
+		wg.Add(len(r.readers))
+
+		for _, reader := range r.readers {
+			go func(reader Reader) {
+				defer wg.Done()
+				reader.Stop()
+			}(reader)
+		}
+
+		wg.Wait()
+	})
+}
+
+func multiReaderMetrics(stream string, records float64, errors float64) mon.MetricData {
+	return mon.MetricData{
+		&mon.MetricDatum{
+			MetricName: metricNameMultiReaderRecordCount,
+			Dimensions: map[string]string{
+				"StreamName": stream,
+			},
+			Unit:  mon.UnitCount,
+			Value: records,
+		},
+		&mon.MetricDatum{
+			MetricName: metricNameMultiReaderErrorCount,
+			Dimensions: map[string]string{
+				"StreamName": stream,
+			},
+			Unit:  mon.UnitCount,
+			Value: errors,
+		},
+	}
+}
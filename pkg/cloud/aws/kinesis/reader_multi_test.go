@@ -0,0 +1,160 @@
+package kinesis_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	configMocks "github.com/applike/gosoline/pkg/cfg/mocks"
+	"github.com/applike/gosoline/pkg/cloud/aws/kinesis"
+	kinesisMocks "github.com/applike/gosoline/pkg/cloud/aws/kinesis/mocks"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func mockMultiFactory(kinsumers map[string]kinesis.Kinsumer) kinesis.KinsumerFactory {
+	return func(config cfg.Config, logger mon.Logger, settings kinesis.KinsumerSettings) (kinesis.Kinsumer, error) {
+		return kinsumers[settings.StreamName], nil
+	}
+}
+
+func multiReaderMetrics(stream string, records float64, errors float64) mon.MetricData {
+	return mon.MetricData{
+		{MetricName: "KinesisRecordCount", Dimensions: map[string]string{"StreamName": stream}, Unit: mon.UnitCount, Value: records},
+		{MetricName: "KinesisRecordErrorCount", Dimensions: map[string]string{"StreamName": stream}, Unit: mon.UnitCount, Value: errors},
+	}
+}
+
+// stoppableKinsumer returns no records until it is stopped
+type stoppableKinsumer struct {
+	stop chan struct{}
+}
+
+func (k *stoppableKinsumer) Run() error {
+	return nil
+}
+
+func (k *stoppableKinsumer) Next() ([]byte, error) {
+	<-k.stop
+
+	return nil, nil
+}
+
+func (k *stoppableKinsumer) Stop() {
+	close(k.stop)
+}
+
+func TestMultiReader_Run(t *testing.T) {
+	orders := new(kinesisMocks.Kinsumer)
+	orders.On("Run").Return(nil).Once()
+	orders.On("Next").Return([]byte("order"), nil).Once()
+	orders.On("Next").Return(nil, nil).Once()
+	orders.On("Stop").Once()
+
+	payments := new(kinesisMocks.Kinsumer)
+	payments.On("Run").Return(nil).Once()
+	payments.On("Next").Return([]byte("payment"), nil).Once()
+	payments.On("Next").Return(nil, nil).Once()
+	payments.On("Stop").Once()
+
+	orderHandler := new(kinesisMocks.MessageHandler)
+	orderHandler.On("Handle", []byte("order")).Return(nil).Once()
+	orderHandler.On("Done").Once()
+
+	paymentHandler := new(kinesisMocks.MessageHandler)
+	paymentHandler.On("Handle", []byte("payment")).Return(fmt.Errorf("invalid payment")).Once()
+	paymentHandler.On("Done").Once()
+
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("Write", multiReaderMetrics("orders", 1, 0)).Once()
+	metricWriter.On("Write", multiReaderMetrics("payments", 1, 1)).Once()
+
+	factory := mockMultiFactory(map[string]kinesis.Kinsumer{
+		"orders":   orders,
+		"payments": payments,
+	})
+	handlers := map[string]kinesis.MessageHandler{
+		"orders":   orderHandler,
+		"payments": paymentHandler,
+	}
+
+	reader, err := kinesis.NewMultiReaderWithInterfaces(new(configMocks.Config), monMocks.NewLoggerMockedAll(), metricWriter, factory, handlers, kinesis.MultiReaderSettings{
+		Streams: []kinesis.KinsumerSettings{
+			{StreamName: "orders", ApplicationName: "app-orders"},
+			{StreamName: "payments", ApplicationName: "app-payments"},
+		},
+		Workers: 2,
+	})
+	assert.NoError(t, err)
+
+	err = reader.Run(context.Background())
+	assert.NoError(t, err)
+
+	reader.Stop()
+
+	orders.AssertExpectations(t)
+	payments.AssertExpectations(t)
+	orderHandler.AssertExpectations(t)
+	paymentHandler.AssertExpectations(t)
+	metricWriter.AssertExpectations(t)
+}
+
+func TestMultiReader_Run_StreamFailed(t *testing.T) {
+	orders := new(kinesisMocks.Kinsumer)
+	orders.On("Run").Return(fmt.Errorf("stream not found")).Once()
+	orders.On("Stop").Once()
+
+	payments := &stoppableKinsumer{
+		stop: make(chan struct{}),
+	}
+
+	orderHandler := new(kinesisMocks.MessageHandler)
+	orderHandler.On("Done").Once()
+
+	paymentHandler := new(kinesisMocks.MessageHandler)
+	paymentHandler.On("Done").Once()
+
+	factory := mockMultiFactory(map[string]kinesis.Kinsumer{
+		"orders":   orders,
+		"payments": payments,
+	})
+	handlers := map[string]kinesis.MessageHandler{
+		"orders":   orderHandler,
+		"payments": paymentHandler,
+	}
+
+	reader, err := kinesis.NewMultiReaderWithInterfaces(new(configMocks.Config), monMocks.NewLoggerMockedAll(), new(monMocks.MetricWriter), factory, handlers, kinesis.MultiReaderSettings{
+		Streams: []kinesis.KinsumerSettings{
+			{StreamName: "orders", ApplicationName: "app-orders"},
+			{StreamName: "payments", ApplicationName: "app-payments"},
+		},
+	})
+	assert.NoError(t, err)
+
+	err = reader.Run(context.Background())
+	assert.EqualError(t, err, "can not read stream orders: kinsumer.Kinsumer.Run() returned error stream not found")
+
+	orders.AssertExpectations(t)
+	orderHandler.AssertExpectations(t)
+	paymentHandler.AssertExpectations(t)
+}
+
+func TestNewMultiReader_SharedApplicationName(t *testing.T) {
+	factory := mockMultiFactory(map[string]kinesis.Kinsumer{})
+	handlers := map[string]kinesis.MessageHandler{
+		"orders":   new(kinesisMocks.MessageHandler),
+		"payments": new(kinesisMocks.MessageHandler),
+	}
+
+	_, err := kinesis.NewMultiReaderWithInterfaces(new(configMocks.Config), monMocks.NewLoggerMockedAll(), new(monMocks.MetricWriter), factory, handlers, kinesis.MultiReaderSettings{
+		Streams: []kinesis.KinsumerSettings{
+			{StreamName: "orders", ApplicationName: "app"},
+			{StreamName: "payments", ApplicationName: "app"},
+		},
+	})
+
+	assert.EqualError(t, err, "the streams orders and payments use the same application name app, which would mix up their checkpoints")
+	mock.AssertExpectationsForObjects(t)
+}
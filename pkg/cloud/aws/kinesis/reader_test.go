@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func mockFactory(kinsumerMock kinesis.Kinsumer) kinesis.KinsumerFactory {
@@ -119,3 +120,100 @@ func TestReaderRestartTrigger(t *testing.T) {
 
 	kinsumerMock.AssertExpectations(t)
 }
+
+func TestReaderShutdown(t *testing.T) {
+	loggerMock := new(monMocks.Logger)
+	loggerMock.On("WithContext", mock.Anything).Return(loggerMock)
+
+	stopped := make(chan struct{})
+	kinsumerMock := new(kinesisMocks.Kinsumer)
+	kinsumerMock.On("Run").Return(nil).Once()
+	kinsumerMock.On("Next").Return([]byte("record"), nil).Once()
+	kinsumerMock.On("Next").Return(nil, nil).Once()
+	kinsumerMock.On("Stop").Run(func(args mock.Arguments) {
+		close(stopped)
+	}).Once()
+
+	handling := make(chan struct{})
+	release := make(chan struct{})
+	handlerMock := new(kinesisMocks.MessageHandler)
+	handlerMock.On("Handle", []byte("record")).Run(func(args mock.Arguments) {
+		close(handling)
+		<-release
+	}).Return(nil).Once()
+	handlerMock.On("Done").Once()
+
+	reader, err := kinesis.NewReader(nil, loggerMock, mockFactory(kinsumerMock), handlerMock, kinesis.KinsumerSettings{})
+	assert.NoError(t, err)
+
+	runDone := make(chan error)
+	go func() {
+		runDone <- reader.Run(context.Background())
+	}()
+
+	<-handling
+
+	shutdownDone := make(chan error)
+	go func() {
+		shutdownDone <- reader.Shutdown(context.Background())
+	}()
+
+	<-stopped
+
+	select {
+	case <-shutdownDone:
+		assert.Fail(t, "shutdown should wait for the handler in flight")
+	default:
+	}
+
+	close(release)
+
+	assert.NoError(t, <-shutdownDone)
+	assert.NoError(t, <-runDone)
+	assert.NoError(t, reader.Shutdown(context.Background()), "repeated calls should succeed")
+
+	kinsumerMock.AssertExpectations(t)
+	handlerMock.AssertExpectations(t)
+}
+
+func TestReaderShutdownTimeout(t *testing.T) {
+	loggerMock := new(monMocks.Logger)
+	loggerMock.On("WithContext", mock.Anything).Return(loggerMock)
+
+	kinsumerMock := new(kinesisMocks.Kinsumer)
+	kinsumerMock.On("Run").Return(nil).Once()
+	kinsumerMock.On("Next").Return([]byte("record"), nil).Once()
+	kinsumerMock.On("Next").Return(nil, nil).Once()
+	kinsumerMock.On("Stop").Once()
+
+	handling := make(chan struct{})
+	release := make(chan struct{})
+	handlerMock := new(kinesisMocks.MessageHandler)
+	handlerMock.On("Handle", []byte("record")).Run(func(args mock.Arguments) {
+		close(handling)
+		<-release
+	}).Return(nil).Once()
+	handlerMock.On("Done").Once()
+
+	reader, err := kinesis.NewReader(nil, loggerMock, mockFactory(kinsumerMock), handlerMock, kinesis.KinsumerSettings{})
+	assert.NoError(t, err)
+
+	runDone := make(chan error)
+	go func() {
+		runDone <- reader.Run(context.Background())
+	}()
+
+	<-handling
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = reader.Shutdown(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "the error of the context should be returned")
+
+	close(release)
+	assert.NoError(t, <-runDone)
+
+	kinsumerMock.AssertExpectations(t)
+	handlerMock.AssertExpectations(t)
+}
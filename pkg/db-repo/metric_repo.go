@@ -69,6 +69,14 @@ func (r metricRepository) Query(ctx context.Context, qb *QueryBuilder, result in
 	return err
 }
 
+func (r metricRepository) Iterate(ctx context.Context, qb *QueryBuilder, model ModelBased, callback IterateCallback) error {
+	start := time.Now()
+	err := r.Repository.Iterate(ctx, qb, model, callback)
+	r.writeMetric(Query, err, start)
+
+	return err
+}
+
 func (r metricRepository) writeMetric(op string, err error, start time.Time) {
 	latencyNano := time.Since(start)
 	metricName := MetricNameDbAccessSuccess
@@ -134,3 +142,7 @@ func getDefaultRepositoryMetrics(modelId mdl.ModelId) []*mon.MetricDatum {
 
 	return defaults
 }
+
+func (r metricRepository) Transaction(ctx context.Context, fn TransactionFunc) error {
+	return RunInTransaction(ctx, r.Repository, fn)
+}
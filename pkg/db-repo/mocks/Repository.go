@@ -102,6 +102,20 @@ func (_m *Repository) GetModelName() string {
 	return r0
 }
 
+// Iterate provides a mock function with given fields: ctx, qb, model, callback
+func (_m *Repository) Iterate(ctx context.Context, qb *db_repo.QueryBuilder, model db_repo.ModelBased, callback db_repo.IterateCallback) error {
+	ret := _m.Called(ctx, qb, model, callback)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *db_repo.QueryBuilder, db_repo.ModelBased, db_repo.IterateCallback) error); ok {
+		r0 = rf(ctx, qb, model, callback)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Query provides a mock function with given fields: ctx, qb, result
 func (_m *Repository) Query(ctx context.Context, qb *db_repo.QueryBuilder, result interface{}) error {
 	ret := _m.Called(ctx, qb, result)
@@ -21,6 +21,16 @@ func (m *Model) GetId() *uint {
 	return m.Id
 }
 
+func (m *Model) SetId(id *uint) {
+	m.Id = id
+}
+
+// IdSettable is implemented by models which can be created with an id given by the client instead of an
+// auto incremented one.
+type IdSettable interface {
+	SetId(id *uint)
+}
+
 type TimeStampable interface {
 	SetUpdatedAt(updatedAt *time.Time)
 	SetCreatedAt(createdAt *time.Time)
@@ -85,3 +85,7 @@ func (r *notifyingRepository) doCallback(ctx context.Context, callbackType strin
 
 	return nil
 }
+
+func (r *notifyingRepository) Transaction(ctx context.Context, fn TransactionFunc) error {
+	return RunInTransaction(ctx, r.Repository, fn)
+}
@@ -39,12 +39,16 @@ type Repository interface {
 	Delete(ctx context.Context, value ModelBased) error
 	Query(ctx context.Context, qb *QueryBuilder, result interface{}) error
 	Count(ctx context.Context, qb *QueryBuilder, model ModelBased) (int, error)
+	Iterate(ctx context.Context, qb *QueryBuilder, model ModelBased, callback IterateCallback) error
 
 	GetModelId() string
 	GetModelName() string
 	GetMetadata() Metadata
 }
 
+// IterateCallback is called for every row read by Iterate. Returning an error stops the iteration.
+type IterateCallback func(model ModelBased) error
+
 type repository struct {
 	logger   mon.Logger
 	tracer   tracing.Tracer
@@ -96,7 +100,7 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 	value.SetUpdatedAt(&now)
 	value.SetCreatedAt(&now)
 
-	err := r.orm.Create(value).Error
+	err := r.db(ctx).Create(value).Error
 
 	if db.IsDuplicateEntryError(err) {
 		logger.Warnf("could not create model of type %s due to duplicate entry error: %s", modelId, err.Error())
@@ -110,7 +114,7 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 		return err
 	}
 
-	err = r.refreshAssociations(value, Create)
+	err = r.refreshAssociations(ctx, value, Create)
 
 	if err != nil {
 		logger.Errorf(err, "could not update associations of model type %v", modelId)
@@ -127,7 +131,7 @@ func (r *repository) Read(ctx context.Context, id *uint, out ModelBased) error {
 	_, span := r.startSubSpan(ctx, "Get")
 	defer span.Finish()
 
-	err := r.orm.First(out, *id).Error
+	err := r.db(ctx).First(out, *id).Error
 
 	if gorm.IsRecordNotFoundError(err) {
 		return NewRecordNotFoundError(*id, modelId, err)
@@ -146,7 +150,7 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 	now := r.clock.Now()
 	value.SetUpdatedAt(&now)
 
-	err := r.orm.Save(value).Error
+	err := r.db(ctx).Save(value).Error
 
 	if db.IsDuplicateEntryError(err) {
 		logger.Warnf("could not update model of type %s with id %d due to duplicate entry error: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
@@ -160,7 +164,7 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 		return err
 	}
 
-	err = r.refreshAssociations(value, Update)
+	err = r.refreshAssociations(ctx, value, Update)
 
 	if err != nil {
 		logger.Errorf(err, "could not update associations of model type %s with id %d", modelId, *value.GetId())
@@ -179,14 +183,14 @@ func (r *repository) Delete(ctx context.Context, value ModelBased) error {
 	_, span := r.startSubSpan(ctx, "Delete")
 	defer span.Finish()
 
-	err := r.refreshAssociations(value, Delete)
+	err := r.refreshAssociations(ctx, value, Delete)
 
 	if err != nil {
 		logger.Errorf(err, "could not delete associations of model type %s with id %d", modelId, *value.GetId())
 		return err
 	}
 
-	err = r.orm.Delete(value).Error
+	err = r.db(ctx).Delete(value).Error
 
 	if err != nil {
 		logger.Errorf(err, "could not delete model of type %s with id %d", modelId, *value.GetId())
@@ -201,7 +205,65 @@ func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interfa
 	_, span := r.startSubSpan(ctx, "Query")
 	defer span.Finish()
 
-	db := r.orm.New()
+	db := r.buildQuery(ctx, qb, result)
+	err := db.Find(result).Error
+
+	if gorm.IsRecordNotFoundError(err) {
+		return NewNoQueryResultsError(r.GetModelId(), err)
+	}
+
+	return err
+}
+
+// Iterate works like Query, but instead of loading all rows into a slice it reads them one after another and
+// hands a new instance of the type of the given model for every row to the callback. It stops with the error of the
+// context as soon as the context is canceled, closing the rows to release the connection.
+func (r *repository) Iterate(ctx context.Context, qb *QueryBuilder, model ModelBased, callback IterateCallback) error {
+	_, span := r.startSubSpan(ctx, "Iterate")
+	defer span.Finish()
+
+	db := r.buildQuery(ctx, qb, model).Model(model)
+	rows, err := db.Rows()
+
+	if err != nil {
+		return fmt.Errorf("can not query rows of %s: %w", r.GetModelId(), err)
+	}
+
+	defer rows.Close()
+
+	modelType := reflect.TypeOf(model).Elem()
+
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row := reflect.New(modelType).Interface().(ModelBased)
+
+		if err := db.ScanRows(rows, row); err != nil {
+			return fmt.Errorf("can not scan row of %s: %w", r.GetModelId(), err)
+		}
+
+		if err := callback(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *repository) buildQuery(ctx context.Context, qb *QueryBuilder, model interface{}) *gorm.DB {
+	db := r.db(ctx).New()
+
+	if hint := r.maxExecutionTimeHint(ctx); hint != "" {
+		sel := "*"
+
+		if len(qb.joins) > 0 {
+			sel = fmt.Sprintf("%s.*", r.orm.NewScope(model).QuotedTableName())
+		}
+
+		db = db.Select(hint + sel)
+	}
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -224,13 +286,7 @@ func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interfa
 		db = db.Limit(qb.page.limit)
 	}
 
-	err := db.Find(result).Error
-
-	if gorm.IsRecordNotFoundError(err) {
-		return NewNoQueryResultsError(r.GetModelId(), err)
-	}
-
-	return err
+	return db
 }
 
 func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBased) (int, error) {
@@ -241,7 +297,7 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 		Count int
 	}{}
 
-	db := r.orm.New()
+	db := r.db(ctx).New()
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -254,14 +310,32 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 	scope := r.orm.NewScope(model)
 	tableName := scope.TableName()
 	key := scope.PrimaryKey()
-	sel := fmt.Sprintf("COUNT(DISTINCT %s.%s) AS count", tableName, key)
+	sel := fmt.Sprintf("%sCOUNT(DISTINCT %s.%s) AS count", r.maxExecutionTimeHint(ctx), tableName, key)
 
 	err := db.Table(tableName).Select(sel).Scan(&result).Error
 
 	return result.Count, err
 }
 
-func (r *repository) refreshAssociations(model interface{}, op string) error {
+// maxExecutionTimeHint returns an optimizer hint which makes mysql abort a select as soon as the deadline of the
+// context is exceeded. The orm doesn't pass the context to the driver, so the query would keep running otherwise.
+func (r *repository) maxExecutionTimeHint(ctx context.Context) string {
+	deadline, ok := ctx.Deadline()
+
+	if !ok || r.orm.Dialect().GetName() != "mysql" {
+		return ""
+	}
+
+	remaining := time.Until(deadline).Milliseconds()
+
+	if remaining < 1 {
+		remaining = 1
+	}
+
+	return fmt.Sprintf("/*+ MAX_EXECUTION_TIME(%d) */ ", remaining)
+}
+
+func (r *repository) refreshAssociations(ctx context.Context, model interface{}, op string) error {
 	typeReflection := reflect.TypeOf(model).Elem()
 	valueReflection := reflect.ValueOf(model).Elem()
 
@@ -302,7 +376,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 		case Update:
 			switch scopeField.Relationship.Kind {
 			case "many_to_many":
-				err = r.orm.Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
+				err = r.db(ctx).Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
 
 			default:
 				assocIds := readIdsFromReflectValue(values)
@@ -319,7 +393,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 					qry = qry + fmt.Sprintf(" AND %s NOT IN (%s)", "id", strings.Join(assocIds, ","))
 				}
 
-				err = r.orm.Exec(qry).Error
+				err = r.db(ctx).Exec(qry).Error
 			}
 
 		case Delete:
@@ -333,10 +407,10 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 				}
 
 				qry := fmt.Sprintf("DELETE FROM %s WHERE %s = %d", tableName, scopeField.Relationship.ForeignDBNames[0], id)
-				err = r.orm.Exec(qry).Error
+				err = r.db(ctx).Exec(qry).Error
 
 			default:
-				err = r.orm.Model(model).Association(field.Name).Clear().Error
+				err = r.db(ctx).Model(model).Association(field.Name).Clear().Error
 			}
 
 		default:
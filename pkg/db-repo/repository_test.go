@@ -3,6 +3,7 @@ package db_repo_test
 import (
 	"context"
 	"database/sql/driver"
+	"fmt"
 	goSqlMock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mdl"
@@ -694,6 +695,63 @@ func getMocks(t *testing.T) (goSqlMock.Sqlmock, db_repo.Repository) {
 	return clientMock, repo
 }
 
+func TestRepository_Iterate(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).
+		AddRow(id1, &now, &now).
+		AddRow(id42, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` WHERE \\(id > \\?\\) ORDER BY id ASC").WithArgs(0).WillReturnRows(rows)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where("id > ?", 0)
+	qb.OrderBy("id", "ASC")
+
+	ids := make([]uint, 0)
+	err := repo.Iterate(context.Background(), qb, &MyTestModel{}, func(model db_repo.ModelBased) error {
+		ids = append(ids, *model.GetId())
+
+		return nil
+	})
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	assert.NoError(t, err, "there should not be an error")
+	assert.Equal(t, []uint{*id1, *id42}, ids)
+}
+
+func TestRepository_Query_Deadline(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT /\\*\\+ MAX_EXECUTION_TIME\\(\\d+\\) \\*/ \\* FROM `my_test_models` WHERE \\(id > \\?\\)").WithArgs(0).WillReturnRows(rows)
+
+	countRows := goSqlMock.NewRows([]string{"count"}).AddRow(1)
+	dbc.ExpectQuery("SELECT /\\*\\+ MAX_EXECUTION_TIME\\(\\d+\\) \\*/ COUNT\\(DISTINCT my_test_models.id\\) AS count FROM `my_test_models` WHERE \\(id > \\?\\)").WithArgs(0).WillReturnRows(countRows)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where("id > ?", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	result := make([]MyTestModel, 0)
+	err := repo.Query(ctx, qb, &result)
+	assert.NoError(t, err, "there should not be an error")
+
+	count, err := repo.Count(ctx, qb, &MyTestModel{})
+	assert.NoError(t, err, "there should not be an error")
+	assert.Equal(t, 1, count)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func getTimedMocks(t *testing.T, time time.Time) (goSqlMock.Sqlmock, db_repo.Repository) {
 	logger := monMocks.NewLoggerMockedAll()
 	tracer := tracing.NewNoopTracer()
@@ -713,3 +771,46 @@ func getTimedMocks(t *testing.T, time time.Time) (goSqlMock.Sqlmock, db_repo.Rep
 
 	return clientMock, repo
 }
+
+func TestRepository_Transaction(t *testing.T) {
+	dbc, repo := getMocks(t)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("DELETE FROM `my_test_models`  WHERE `my_test_models`\\.`id` = \\?").WithArgs(id1).WillReturnResult(result)
+	dbc.ExpectExec("DELETE FROM `my_test_models`  WHERE `my_test_models`\\.`id` = \\?").WithArgs(id42).WillReturnError(fmt.Errorf("lock wait timeout"))
+	dbc.ExpectRollback()
+
+	err := repo.(db_repo.TransactionalRepository).Transaction(context.Background(), func(ctx context.Context) error {
+		if err := repo.Delete(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}}); err != nil {
+			return err
+		}
+
+		return repo.Delete(ctx, &MyTestModel{Model: db_repo.Model{Id: id42}})
+	})
+
+	assert.EqualError(t, err, "lock wait timeout")
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_Transaction_Commit(t *testing.T) {
+	dbc, repo := getMocks(t)
+
+	result := goSqlMock.NewResult(0, 1)
+	dbc.ExpectBegin()
+	dbc.ExpectExec("DELETE FROM `my_test_models`  WHERE `my_test_models`\\.`id` = \\?").WithArgs(id1).WillReturnResult(result)
+	dbc.ExpectCommit()
+
+	err := db_repo.RunInTransaction(context.Background(), repo, func(ctx context.Context) error {
+		return repo.Delete(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}})
+	})
+
+	assert.NoError(t, err)
+
+	if err := dbc.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
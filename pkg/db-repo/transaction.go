@@ -0,0 +1,85 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/jinzhu/gorm"
+)
+
+type contextTransactionKey struct{}
+
+// TransactionFunc is run by Transaction. Operations of the repositories of this package using the given context are
+// part of the transaction.
+type TransactionFunc func(ctx context.Context) error
+
+// TransactionalRepository is implemented by the repositories of this package.
+//
+//go:generate mockery -name TransactionalRepository
+type TransactionalRepository interface {
+	Transaction(ctx context.Context, fn TransactionFunc) error
+}
+
+// Transaction begins a transaction and runs fn with a context carrying it. As gorm doesn't support contexts, the
+// repositories look the transaction up in the context of every operation, so operations of other repositories using
+// the same database are part of it as well. The transaction is committed if fn succeeds and rolled back otherwise.
+// If the context already carries a transaction, fn joins it.
+func (r *repository) Transaction(ctx context.Context, fn TransactionFunc) (err error) {
+	if _, ok := transactionFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx := r.orm.Begin()
+
+	if tx.Error != nil {
+		return fmt.Errorf("can not begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			tx.Rollback()
+			panic(recovered)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, contextTransactionKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback().Error; rollbackErr != nil {
+			return multierror.Append(err, fmt.Errorf("can not roll back transaction: %w", rollbackErr))
+		}
+
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// db returns the transaction of the context or the orm if there is none
+func (r *repository) db(ctx context.Context) *gorm.DB {
+	if tx, ok := transactionFromContext(ctx); ok {
+		return tx
+	}
+
+	return r.orm
+}
+
+func transactionFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(contextTransactionKey{}).(*gorm.DB)
+
+	return tx, ok
+}
+
+// RunInTransaction runs fn in a transaction of the repository if it implements TransactionalRepository. Other
+// repositories, e.g. mocks, just run fn.
+func RunInTransaction(ctx context.Context, repo interface{}, fn TransactionFunc) error {
+	transactional, ok := repo.(TransactionalRepository)
+
+	if !ok {
+		return fn(ctx)
+	}
+
+	return transactional.Transaction(ctx, fn)
+}
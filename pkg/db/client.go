@@ -10,6 +10,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 const (
@@ -39,8 +40,9 @@ type Client interface {
 }
 
 type ClientSqlx struct {
-	logger mon.Logger
-	db     *sqlx.DB
+	logger   mon.Logger
+	db       *sqlx.DB
+	queryLog *queryLogger
 }
 
 func NewClient(config cfg.Config, logger mon.Logger, name string) (Client, error) {
@@ -50,13 +52,18 @@ func NewClient(config cfg.Config, logger mon.Logger, name string) (Client, error
 		return nil, fmt.Errorf("can not connect to sql database: %w", err)
 	}
 
-	return NewClientWithInterfaces(logger, db), nil
+	settings := createSettings(config, name)
+
+	return NewClientWithInterfaces(logger, db, settings.QueryLog), nil
 }
 
-func NewClientWithInterfaces(logger mon.Logger, db *sqlx.DB) Client {
+func NewClientWithInterfaces(logger mon.Logger, db *sqlx.DB, queryLogSettings QueryLogSettings) Client {
+	logger = logger.WithContext(context.Background()) // TODO: this is not nice, but we don't (yet) have a context when logging in this module
+
 	return &ClientSqlx{
-		logger: logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
-		db:     db,
+		logger:   logger,
+		db:       db,
+		queryLog: newQueryLogger(logger, queryLogSettings),
 	}
 }
 
@@ -130,7 +137,7 @@ func (c *ClientSqlx) GetResult(query string, args ...interface{}) (*Result, erro
 }
 
 func (c *ClientSqlx) Exec(query string, args ...interface{}) (sql.Result, error) {
-	c.logger.Debugf("> %s %q", query, args)
+	defer c.queryLog.log(query, args, time.Now())
 
 	return c.db.Exec(query, args...)
 }
@@ -140,29 +147,31 @@ func (c *ClientSqlx) Prepare(query string) (*sql.Stmt, error) {
 }
 
 func (c *ClientSqlx) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	c.logger.Debugf("> %s %q", query, args)
+	defer c.queryLog.log(query, args, time.Now())
 
 	return c.db.Query(query, args...)
 }
 
 func (c *ClientSqlx) QueryRow(query string, args ...interface{}) *sql.Row {
+	defer c.queryLog.log(query, args, time.Now())
+
 	return c.db.QueryRow(query, args...)
 }
 
 func (c *ClientSqlx) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
-	c.logger.Debugf("> %s %q", query, args)
+	defer c.queryLog.log(query, args, time.Now())
 
 	return c.db.Queryx(query, args...)
 }
 
 func (c *ClientSqlx) Select(dest interface{}, query string, args ...interface{}) error {
-	c.logger.Debugf("> %s %q", query, args)
+	defer c.queryLog.log(query, args, time.Now())
 
 	return c.db.Select(dest, query, args...)
 }
 
 func (c *ClientSqlx) Get(dest interface{}, query string, args ...interface{}) error {
-	c.logger.Debugf("> %s %q", query, args)
+	defer c.queryLog.log(query, args, time.Now())
 
 	return c.db.Get(dest, query, args...)
 }
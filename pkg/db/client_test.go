@@ -1,12 +1,18 @@
 package db_test
 
 import (
+	"bytes"
+	"encoding/json"
 	goSqlMock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/mon"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/jmoiron/sqlx"
+	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetResult(t *testing.T) {
@@ -125,7 +131,72 @@ func getMocks() (db.Client, goSqlMock.Sqlmock) {
 	loggerMock := monMocks.NewLoggerMockedAll()
 	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
 
-	client := db.NewClientWithInterfaces(loggerMock, sqlxDB)
+	client := db.NewClientWithInterfaces(loggerMock, sqlxDB, db.QueryLogSettings{})
 
 	return client, sqlMock
 }
+
+func TestClient_QueryLog(t *testing.T) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
+
+	out := bytes.NewBuffer([]byte{})
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), out)
+	err := logger.Option(mon.WithFormat(mon.FormatJson), mon.WithLevel(mon.Debug))
+	assert.NoError(t, err)
+
+	client := db.NewClientWithInterfaces(logger, sqlxDB, db.QueryLogSettings{
+		Enabled: true,
+	})
+
+	sqlMock.ExpectExec("UPDATE users").WithArgs("secret", 2).WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	_, err = client.Exec("UPDATE users SET password = ? WHERE id = ?", db.Redact("secret"), 2)
+	assert.NoError(t, err)
+
+	entry := make(map[string]interface{})
+	err = json.Unmarshal(out.Bytes(), &entry)
+	assert.NoError(t, err)
+
+	fields := entry["fields"].(map[string]interface{})
+
+	assert.Equal(t, "sql", entry["channel"])
+	assert.Equal(t, "debug", entry["level_name"])
+	assert.Equal(t, "executed sql statement", entry["message"])
+	assert.Equal(t, "UPDATE users SET password = ? WHERE id = ?", fields["sql_statement"])
+	assert.Equal(t, []interface{}{"[redacted]", float64(2)}, fields["sql_args"])
+	assert.Contains(t, fields, "sql_duration")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestClient_QueryLog_Slow(t *testing.T) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
+
+	out := bytes.NewBuffer([]byte{})
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), out)
+	err := logger.Option(mon.WithFormat(mon.FormatJson))
+	assert.NoError(t, err)
+
+	client := db.NewClientWithInterfaces(logger, sqlxDB, db.QueryLogSettings{
+		Enabled:       true,
+		SlowThreshold: time.Nanosecond,
+		RedactArgs:    true,
+	})
+
+	sqlMock.ExpectQuery("SELECT").WithArgs(3).WillReturnRows(goSqlMock.NewRows([]string{"id"}).AddRow(3)).WillDelayFor(time.Millisecond)
+
+	rows, err := client.Query("SELECT id FROM users WHERE id = ?", 3)
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+
+	entry := make(map[string]interface{})
+	err = json.Unmarshal(out.Bytes(), &entry)
+	assert.NoError(t, err)
+
+	fields := entry["fields"].(map[string]interface{})
+
+	assert.Equal(t, "warn", entry["level_name"])
+	assert.True(t, strings.HasPrefix(entry["message"].(string), "slow sql statement took "))
+	assert.Equal(t, []interface{}{"[redacted]"}, fields["sql_args"])
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jmoiron/sqlx"
 	"sync"
@@ -28,6 +29,7 @@ type Settings struct {
 
 	Uri        Uri               `cfg:"uri"`
 	Migrations MigrationSettings `cfg:"migrations"`
+	QueryLog   QueryLogSettings  `cfg:"query_log"`
 }
 
 var defaultConnections = struct {
@@ -58,6 +60,10 @@ func ProvideConnection(config cfg.Config, logger mon.Logger, configKey string) (
 	defaultConnections.instances[key] = instance
 	defaultConnections.errors[key] = err
 
+	if err == nil {
+		healthcheck.Register(fmt.Sprintf("db.%s", configKey), healthcheck.HealthcheckerFunc(instance.PingContext))
+	}
+
 	return defaultConnections.instances[key], defaultConnections.errors[key]
 }
 
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"database/sql/driver"
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+const (
+	ChannelSql = "sql"
+
+	redactedArg = "[redacted]"
+)
+
+type QueryLogSettings struct {
+	// Enabled logs every statement with its args and duration on the sql channel at debug level
+	Enabled bool `cfg:"enabled" default:"false"`
+	// SlowThreshold logs statements taking longer at warn level instead, 0 disables the escalation
+	SlowThreshold time.Duration `cfg:"slow_threshold" default:"1s"`
+	// RedactArgs hides the values of all args, single args can be hidden with Redact
+	RedactArgs bool `cfg:"redact_args" default:"false"`
+}
+
+type redacted struct {
+	value interface{}
+}
+
+// Redact marks an arg of a statement as sensitive, like a password. The value is passed to the database as it is,
+// but the query log writes it as [redacted].
+func Redact(value interface{}) driver.Valuer {
+	return redacted{
+		value: value,
+	}
+}
+
+func (r redacted) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(r.value)
+}
+
+type queryLogger struct {
+	logger   mon.Logger
+	settings QueryLogSettings
+}
+
+func newQueryLogger(logger mon.Logger, settings QueryLogSettings) *queryLogger {
+	return &queryLogger{
+		logger:   logger.WithChannel(ChannelSql),
+		settings: settings,
+	}
+}
+
+func (l *queryLogger) log(query string, args []interface{}, start time.Time) {
+	if !l.settings.Enabled {
+		return
+	}
+
+	duration := time.Since(start)
+	logger := l.logger.WithFields(mon.Fields{
+		"sql_statement": query,
+		"sql_args":      l.redactArgs(args),
+		"sql_duration":  float64(duration) / float64(time.Second),
+	})
+
+	if l.settings.SlowThreshold > 0 && duration > l.settings.SlowThreshold {
+		logger.Warnf("slow sql statement took %s", duration)
+		return
+	}
+
+	logger.Debug("executed sql statement")
+}
+
+func (l *queryLogger) redactArgs(args []interface{}) []interface{} {
+	logged := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		if _, ok := arg.(redacted); ok || l.settings.RedactArgs {
+			arg = redactedArg
+		}
+
+		logged[i] = arg
+	}
+
+	return logged
+}
@@ -67,6 +67,7 @@ func (b *batchGetItemsBuilder) WithHashKeys(hashKeys interface{}) BatchGetItemsB
 	return b
 }
 
+// DisableTtlFilter returns expired items as long as DynamoDB didn't delete them yet.
 func (b *batchGetItemsBuilder) DisableTtlFilter() BatchGetItemsBuilder {
 	b.disableTtlFilter = true
 
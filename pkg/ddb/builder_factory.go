@@ -46,5 +46,5 @@ func (f *builderFactory) PutItemBuilder() PutItemBuilder {
 }
 
 func (f *builderFactory) UpdateItemBuilder() UpdateItemBuilder {
-	return NewUpdateItemBuilder(f.metadata)
+	return NewUpdateItemBuilderWithInterfaces(f.metadata, f.clock)
 }
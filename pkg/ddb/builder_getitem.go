@@ -68,6 +68,7 @@ func (b *getItemBuilder) WithKeys(keys ...interface{}) GetItemBuilder {
 	return b
 }
 
+// DisableTtlFilter returns the item even if it already expired but was not deleted by DynamoDB yet.
 func (b *getItemBuilder) DisableTtlFilter() GetItemBuilder {
 	b.disableTtlFilter = true
 
@@ -80,6 +81,8 @@ func (b *getItemBuilder) WithProjection(projection interface{}) GetItemBuilder {
 	return b
 }
 
+// WithConsistentRead returns the latest written version of the item. A consistent read consumes twice the read
+// capacity of an eventually consistent one.
 func (b *getItemBuilder) WithConsistentRead(consistentRead bool) GetItemBuilder {
 	b.consistentRead = &consistentRead
 
@@ -40,6 +40,7 @@ type QueryBuilder interface {
 	WithRangeGte(value interface{}) QueryBuilder
 	WithRangeLt(value interface{}) QueryBuilder
 	WithRangeLte(value interface{}) QueryBuilder
+	WithKeyCondition(condition KeyCondition) QueryBuilder
 	WithFilter(filter expression.ConditionBuilder) QueryBuilder
 	DisableTtlFilter() QueryBuilder
 	WithProjection(projection interface{}) QueryBuilder
@@ -47,6 +48,7 @@ type QueryBuilder interface {
 	WithPageSize(size int) QueryBuilder
 	WithDescendingOrder() QueryBuilder
 	WithConsistentRead(consistentRead bool) QueryBuilder
+	WithPageToken(token string) QueryBuilder
 	Build(result interface{}) (*QueryOperation, error)
 }
 
@@ -59,11 +61,13 @@ type queryBuilder struct {
 
 	hashExprBuilder  keyExprBuilder
 	rangeExprBuilder keyExprBuilder
+	keyCondition     *KeyCondition
 	projection       interface{}
 	limit            *int64
 	pageSize         *int64
 	scanIndexForward *bool
 	consistentRead   *bool
+	pageToken        string
 }
 
 func NewQueryBuilder(metadata *Metadata, clock clock.Clock) QueryBuilder {
@@ -74,6 +78,10 @@ func NewQueryBuilder(metadata *Metadata, clock clock.Clock) QueryBuilder {
 	}
 }
 
+// WithIndex queries a local or global secondary index instead of the table. The hash and range conditions
+// then refer to the keys of the index. Keep in mind that an index only contains the attributes of its model:
+// the projection is limited to them. A global secondary index can't fetch other attributes from the table, so
+// the ttl filter requires the ttl attribute to be part of its model, and it doesn't support consistent reads.
 func (b *queryBuilder) WithIndex(name string) QueryBuilder {
 	index := b.metadata.Index(name)
 
@@ -198,12 +206,23 @@ func (b *queryBuilder) WithRangeLte(value interface{}) QueryBuilder {
 	return b
 }
 
+// WithKeyCondition replaces the conditions of WithHash and WithRange by a condition referencing the key attributes by
+// name. Building the query fails if they aren't the keys of the table or the index of WithIndex.
+func (b *queryBuilder) WithKeyCondition(condition KeyCondition) QueryBuilder {
+	b.keyCondition = &condition
+
+	return b
+}
+
 func (b *queryBuilder) WithFilter(filter expression.ConditionBuilder) QueryBuilder {
 	b.filterCondition = &filter
 
 	return b
 }
 
+// DisableTtlFilter removes the ttl condition from the filter expression, so the query also returns expired items which
+// were not deleted by DynamoDB yet. This is useful to clean up or audit expired items and allows to query an index
+// which doesn't contain the ttl attribute.
 func (b *queryBuilder) DisableTtlFilter() QueryBuilder {
 	b.disableTtlFilter = true
 
@@ -234,12 +253,23 @@ func (b *queryBuilder) WithDescendingOrder() QueryBuilder {
 	return b
 }
 
+// WithConsistentRead makes the query return the latest written versions of the items, which consumes twice the read
+// capacity of an eventually consistent query. Global secondary indexes don't support consistent reads, building such
+// a query fails.
 func (b *queryBuilder) WithConsistentRead(consistentRead bool) QueryBuilder {
 	b.consistentRead = &consistentRead
 
 	return b
 }
 
+// WithPageToken resumes a query at the position described by the NextPageToken of a former QueryResult.
+// The token has to be created by a query on the same table and index.
+func (b *queryBuilder) WithPageToken(token string) QueryBuilder {
+	b.pageToken = token
+
+	return b
+}
+
 func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 	var err error
 	var keyCondition expression.KeyConditionBuilder
@@ -249,6 +279,10 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 		return nil, b.err
 	}
 
+	if err = b.validateIndex(); err != nil {
+		return nil, err
+	}
+
 	exprBuilder := expression.NewBuilder()
 
 	if keyCondition, err = b.buildKeyCondition(); err != nil {
@@ -277,6 +311,12 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 		return nil, err
 	}
 
+	startKey, err := b.buildStartKey()
+
+	if err != nil {
+		return nil, err
+	}
+
 	progress := buildPageIterator(b.limit, b.pageSize)
 	input := &dynamodb.QueryInput{
 		TableName:                 aws.String(b.metadata.TableName),
@@ -289,6 +329,7 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 		ProjectionExpression:      expr.Projection(),
 		Limit:                     progress.size,
 		ScanIndexForward:          b.scanIndexForward,
+		ExclusiveStartKey:         startKey,
 	}
 
 	operation := &QueryOperation{
@@ -301,20 +342,46 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 	return operation, nil
 }
 
+func (b *queryBuilder) validateIndex() error {
+	if b.indexName == nil {
+		return nil
+	}
+
+	name := *b.indexName
+	_, isGlobal := b.metadata.Global[name]
+
+	if isGlobal && b.consistentRead != nil && *b.consistentRead {
+		return fmt.Errorf("consistent reads are not supported on the global secondary index %s of table %s", name, b.metadata.TableName)
+	}
+
+	ttl := b.metadata.TimeToLive
+
+	// a local secondary index fetches attributes it doesn't contain from the table, so only global ones need the ttl
+	if isGlobal && ttl.Enabled && !b.disableTtlFilter && !b.selected.ContainsField(ttl.Field) {
+		return fmt.Errorf("the ttl attribute %s is not part of the index %s of table %s: add it to the index model or disable the ttl filter", ttl.Field, name, b.metadata.TableName)
+	}
+
+	return nil
+}
+
 func (b *queryBuilder) buildKeyCondition() (expression.KeyConditionBuilder, error) {
+	if b.keyCondition != nil {
+		return b.buildFromKeyCondition()
+	}
+
 	if b.selected.GetHashKey() == nil {
-		return expression.KeyConditionBuilder{}, fmt.Errorf("no hash key defined for table %s", b.metadata.TableName)
+		return expression.KeyConditionBuilder{}, fmt.Errorf("no hash key defined for %s", b.describeSelected())
 	}
 
 	if b.hashExprBuilder == nil {
-		return expression.KeyConditionBuilder{}, fmt.Errorf("no value for the hash key provided for table %s", b.metadata.TableName)
+		return expression.KeyConditionBuilder{}, fmt.Errorf("no value for the hash key provided for %s", b.describeSelected())
 	}
 
 	condition := b.hashExprBuilder()
 
 	if b.rangeExprBuilder != nil {
 		if b.selected.GetRangeKey() == nil {
-			return expression.KeyConditionBuilder{}, fmt.Errorf("no range key defined for table %s", b.metadata.TableName)
+			return expression.KeyConditionBuilder{}, fmt.Errorf("no range key defined for %s", b.describeSelected())
 		}
 
 		rangeCondition := b.rangeExprBuilder()
@@ -323,3 +390,35 @@ func (b *queryBuilder) buildKeyCondition() (expression.KeyConditionBuilder, erro
 
 	return condition, nil
 }
+
+func (b *queryBuilder) buildFromKeyCondition() (expression.KeyConditionBuilder, error) {
+	if b.hashExprBuilder != nil || b.rangeExprBuilder != nil {
+		return expression.KeyConditionBuilder{}, fmt.Errorf("the key condition for %s can't be combined with WithHash or WithRange", b.describeSelected())
+	}
+
+	condition, err := b.keyCondition.Build(b.selected)
+
+	if err != nil {
+		return expression.KeyConditionBuilder{}, fmt.Errorf("invalid key condition for %s: %w", b.describeSelected(), err)
+	}
+
+	return condition, nil
+}
+
+func (b *queryBuilder) buildStartKey() (map[string]*dynamodb.AttributeValue, error) {
+	if b.pageToken == "" {
+		return nil, nil
+	}
+
+	keyFields := append(b.selected.GetKeyFields(), b.metadata.Main.GetKeyFields()...)
+
+	return decodePageTokenForKeys(b.metadata.TableName, b.pageToken, keyFields)
+}
+
+func (b *queryBuilder) describeSelected() string {
+	if b.indexName == nil {
+		return fmt.Sprintf("table %s", b.metadata.TableName)
+	}
+
+	return fmt.Sprintf("index %s of table %s", *b.indexName, b.metadata.TableName)
+}
@@ -0,0 +1,156 @@
+package ddb_test
+
+import (
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type ttlModel struct {
+	Id       int    `json:"id" ddb:"key=hash"`
+	Category string `json:"category"`
+	Ttl      int64  `json:"ttl" ddb:"ttl=enabled"`
+}
+
+type categoryIndex struct {
+	Category string `json:"category" ddb:"global=hash"`
+	Id       int    `json:"id"`
+}
+
+type categoryIndexWithTtl struct {
+	Category string `json:"category" ddb:"global=hash"`
+	Id       int    `json:"id"`
+	Ttl      int64  `json:"ttl"`
+}
+
+type ttlRangeModel struct {
+	Id       int    `json:"id" ddb:"key=hash"`
+	Rev      string `json:"rev" ddb:"key=range"`
+	Category string `json:"category"`
+	Ttl      int64  `json:"ttl" ddb:"ttl=enabled"`
+}
+
+type categoryLocalIndex struct {
+	Id       int    `json:"id" ddb:"key=hash"`
+	Category string `json:"category" ddb:"local=range"`
+}
+
+func getTtlMetadata(t *testing.T) *ddb.Metadata {
+	metadata, err := ddb.NewMetadataFactory().GetMetadata(&ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "ttlModel",
+		},
+		Main: ddb.MainSettings{
+			Model: ttlModel{},
+		},
+		Global: []ddb.GlobalSettings{
+			{
+				Name:  "by-category",
+				Model: categoryIndex{},
+			},
+			{
+				Name:  "by-category-ttl",
+				Model: categoryIndexWithTtl{},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	return metadata
+}
+
+func TestQueryBuilder_WithIndex(t *testing.T) {
+	metadata := getTtlMetadata(t)
+
+	qb := ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category-ttl").WithHash("a")
+	_, err := qb.Build(&[]categoryIndexWithTtl{})
+	assert.NoError(t, err)
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category").WithHash("a")
+	_, err = qb.Build(&[]categoryIndex{})
+	assert.EqualError(t, err, "the ttl attribute ttl is not part of the index by-category of table ----ttlModel: add it to the index model or disable the ttl filter")
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category").WithHash("a").DisableTtlFilter()
+	_, err = qb.Build(&[]categoryIndex{})
+	assert.NoError(t, err)
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category-ttl").WithHash("a").WithConsistentRead(true)
+	_, err = qb.Build(&[]categoryIndexWithTtl{})
+	assert.EqualError(t, err, "consistent reads are not supported on the global secondary index by-category-ttl of table ----ttlModel")
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category-ttl").WithHash("a").WithRangeEq(1)
+	_, err = qb.Build(&[]categoryIndexWithTtl{})
+	assert.EqualError(t, err, "no range key defined for index by-category-ttl of table ----ttlModel")
+}
+
+func TestKeyCondition_Build(t *testing.T) {
+	metadata, err := ddb.NewMetadataFactory().GetMetadata(&ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "model",
+		},
+		Main: ddb.MainSettings{
+			Model: model{},
+		},
+	})
+	assert.NoError(t, err)
+
+	condition, err := ddb.KeyEquals("id", 1).And(ddb.SortBeginsWith("rev", "a")).Build(metadata.Main)
+	assert.NoError(t, err)
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "(#0 = :0) AND (begins_with (#1, :1))", *expr.KeyCondition())
+	assert.Equal(t, "id", *expr.Names()["#0"])
+	assert.Equal(t, "rev", *expr.Names()["#1"])
+
+	_, err = ddb.KeyEquals("foo", 1).Build(metadata.Main)
+	assert.EqualError(t, err, "the attribute foo is not the hash key, which is id")
+
+	_, err = ddb.KeyEquals("id", 1).And(ddb.SortBetween("foo", 1, 2)).Build(metadata.Main)
+	assert.EqualError(t, err, "the attribute foo is not the range key, which is rev")
+}
+
+func TestQueryBuilder_WithKeyCondition(t *testing.T) {
+	metadata := getTtlMetadata(t)
+
+	qb := ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category-ttl").WithKeyCondition(ddb.KeyEquals("category", "a"))
+	_, err := qb.Build(&[]categoryIndexWithTtl{})
+	assert.NoError(t, err)
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category-ttl").WithKeyCondition(ddb.KeyEquals("id", 1))
+	_, err = qb.Build(&[]categoryIndexWithTtl{})
+	assert.EqualError(t, err, "invalid key condition for index by-category-ttl of table ----ttlModel: the attribute id is not the hash key, which is category")
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithKeyCondition(ddb.KeyEquals("id", 1).And(ddb.SortEquals("category", "a")))
+	_, err = qb.Build(&[]ttlModel{})
+	assert.EqualError(t, err, "invalid key condition for table ----ttlModel: there is no range key to compare the attribute category with")
+
+	qb = ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithHash(1).WithKeyCondition(ddb.KeyEquals("id", 1))
+	_, err = qb.Build(&[]ttlModel{})
+	assert.EqualError(t, err, "the key condition for table ----ttlModel can't be combined with WithHash or WithRange")
+}
+
+func TestQueryBuilder_WithIndex_Local(t *testing.T) {
+	metadata, err := ddb.NewMetadataFactory().GetMetadata(&ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "ttlRangeModel",
+		},
+		Main: ddb.MainSettings{
+			Model: ttlRangeModel{},
+		},
+		Local: []ddb.LocalSettings{
+			{
+				Name:  "by-category",
+				Model: categoryLocalIndex{},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	qb := ddb.NewQueryBuilder(metadata, clock.NewFakeClock()).WithIndex("by-category").WithHash(1).WithConsistentRead(true)
+	_, err = qb.Build(&[]categoryLocalIndex{})
+	assert.NoError(t, err, "a local index without the ttl attribute should be queryable with the ttl filter")
+}
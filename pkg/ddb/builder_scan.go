@@ -26,6 +26,7 @@ type ScanBuilder interface {
 	WithPageSize(size int) ScanBuilder
 	WithSegment(segment int, total int) ScanBuilder
 	WithConsistentRead(consistentRead bool) ScanBuilder
+	WithPageToken(token string) ScanBuilder
 	Build(result interface{}) (*ScanOperation, error)
 }
 
@@ -41,6 +42,7 @@ type scanBuilder struct {
 	segment        *int64
 	segmentTotal   *int64
 	consistentRead *bool
+	pageToken      string
 }
 
 func NewScanBuilder(metadata *Metadata, clock clock.Clock) ScanBuilder {
@@ -71,6 +73,8 @@ func (b *scanBuilder) WithFilter(filter expression.ConditionBuilder) ScanBuilder
 	return b
 }
 
+// DisableTtlFilter removes the ttl condition from the filter expression, so the scan also returns expired items which
+// were not deleted by DynamoDB yet.
 func (b *scanBuilder) DisableTtlFilter() ScanBuilder {
 	b.disableTtlFilter = true
 
@@ -108,6 +112,14 @@ func (b *scanBuilder) WithConsistentRead(consistentRead bool) ScanBuilder {
 	return b
 }
 
+// WithPageToken resumes a scan at the position described by the NextPageToken of a former ScanResult. The token
+// has to be created by a scan of the same table, index and segment.
+func (b *scanBuilder) WithPageToken(token string) ScanBuilder {
+	b.pageToken = token
+
+	return b
+}
+
 func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 	targetType := resolveTargetType(b.selected, b.projection, result)
 	expr, err := b.buildExpression(targetType)
@@ -116,6 +128,12 @@ func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 		return nil, err
 	}
 
+	startKey, err := b.buildStartKey()
+
+	if err != nil {
+		return nil, err
+	}
+
 	progress := buildPageIterator(b.limit, b.pageSize)
 	input := &dynamodb.ScanInput{
 		TableName:                 aws.String(b.metadata.TableName),
@@ -128,6 +146,7 @@ func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 		Limit:                     b.limit,
 		Segment:                   b.segment,
 		TotalSegments:             b.segmentTotal,
+		ExclusiveStartKey:         startKey,
 	}
 
 	operation := &ScanOperation{
@@ -167,3 +186,13 @@ func (b *scanBuilder) buildExpression(result interface{}) (expression.Expression
 
 	return exprBuilder.Build()
 }
+
+func (b *scanBuilder) buildStartKey() (map[string]*dynamodb.AttributeValue, error) {
+	if b.pageToken == "" {
+		return nil, nil
+	}
+
+	keyFields := append(b.selected.GetKeyFields(), b.metadata.Main.GetKeyFields()...)
+
+	return decodePageTokenForKeys(b.metadata.TableName, b.pageToken, keyFields)
+}
@@ -2,6 +2,7 @@ package ddb
 
 import (
 	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
@@ -14,6 +15,9 @@ type UpdateItemBuilder interface {
 	WithCondition(cond expression.ConditionBuilder) UpdateItemBuilder
 	Add(path string, value interface{}) UpdateItemBuilder
 	Delete(path string, value interface{}) UpdateItemBuilder
+	Increment(path string, value interface{}) UpdateItemBuilder
+	Decrement(path string, value interface{}) UpdateItemBuilder
+	DisableTtlFilter() UpdateItemBuilder
 	Set(path string, value interface{}) UpdateItemBuilder
 	SetMap(values map[string]interface{}) UpdateItemBuilder
 	SetIfNotExist(path string, value interface{}) UpdateItemBuilder
@@ -28,16 +32,26 @@ type UpdateItemBuilder interface {
 }
 
 type updateItemBuilder struct {
-	metadata      *Metadata
-	keyBuilder    keyBuilder
-	condition     *expression.ConditionBuilder
-	updateBuilder *expression.UpdateBuilder
-	returnType    *string
+	metadata         *Metadata
+	clock            clock.Clock
+	keyBuilder       keyBuilder
+	condition        *expression.ConditionBuilder
+	updateBuilder    *expression.UpdateBuilder
+	returnType       *string
+	counting         bool
+	disableTtlFilter bool
+	ttlCutoff        *int64
 }
 
 func NewUpdateItemBuilder(metadata *Metadata) UpdateItemBuilder {
+	return NewUpdateItemBuilderWithInterfaces(metadata, clock.NewRealClock())
+}
+
+// NewUpdateItemBuilderWithInterfaces uses the clock to decide which items are expired, see Increment.
+func NewUpdateItemBuilderWithInterfaces(metadata *Metadata, clock clock.Clock) UpdateItemBuilder {
 	return &updateItemBuilder{
 		metadata: metadata,
+		clock:    clock,
 		keyBuilder: keyBuilder{
 			metadata: metadata.Main,
 		},
@@ -74,6 +88,42 @@ func (b *updateItemBuilder) Delete(path string, value interface{}) UpdateItemBui
 	})
 }
 
+// Increment atomically adds the value to the number at path, a missing attribute counts as 0. Combined with a
+// condition like ddb.Gte("stock", 1) on a Decrement, the counter can be kept from running below a limit.
+//
+// If the table has a ttl, the update only applies to items which are missing or not expired yet, otherwise the
+// counter of an expired item waiting for its deletion would be continued. See DisableTtlFilter.
+//
+// A skipped update is reported as ConditionalUpdateError by UpdateItem, its reason tells if the condition of the
+// builder failed or if the item is expired.
+func (b *updateItemBuilder) Increment(path string, value interface{}) UpdateItemBuilder {
+	b.counting = true
+
+	return b.update(func() expression.UpdateBuilder {
+		current := expression.IfNotExists(expression.Name(path), expression.Value(0))
+
+		return b.updateBuilder.Set(expression.Name(path), expression.Plus(current, expression.Value(value)))
+	})
+}
+
+// Decrement atomically subtracts the value from the number at path, see Increment.
+func (b *updateItemBuilder) Decrement(path string, value interface{}) UpdateItemBuilder {
+	b.counting = true
+
+	return b.update(func() expression.UpdateBuilder {
+		current := expression.IfNotExists(expression.Name(path), expression.Value(0))
+
+		return b.updateBuilder.Set(expression.Name(path), expression.Minus(current, expression.Value(value)))
+	})
+}
+
+// DisableTtlFilter allows Increment and Decrement to update expired items.
+func (b *updateItemBuilder) DisableTtlFilter() UpdateItemBuilder {
+	b.disableTtlFilter = true
+
+	return b
+}
+
 func (b *updateItemBuilder) Set(path string, value interface{}) UpdateItemBuilder {
 	return b.update(func() expression.UpdateBuilder {
 		return b.updateBuilder.Set(expression.Name(path), expression.Value(value))
@@ -164,7 +214,9 @@ func (b *updateItemBuilder) Build(item interface{}) (*dynamodb.UpdateItemInput,
 }
 
 func (b *updateItemBuilder) buildExpression() (expression.Expression, error) {
-	if b.updateBuilder == nil && b.condition == nil {
+	condition := b.buildCondition()
+
+	if b.updateBuilder == nil && condition == nil {
 		return expression.Expression{}, nil
 	}
 
@@ -174,13 +226,57 @@ func (b *updateItemBuilder) buildExpression() (expression.Expression, error) {
 		exprBuilder = exprBuilder.WithUpdate(*b.updateBuilder)
 	}
 
-	if b.condition != nil {
-		exprBuilder = exprBuilder.WithCondition(*b.condition)
+	if condition != nil {
+		exprBuilder = exprBuilder.WithCondition(*condition)
 	}
 
 	return exprBuilder.Build()
 }
 
+func (b *updateItemBuilder) buildCondition() *expression.ConditionBuilder {
+	ttl := b.metadata.TimeToLive
+
+	if !b.counting || !ttl.Enabled || b.disableTtlFilter {
+		return b.condition
+	}
+
+	now := b.clock.Now().Unix()
+	b.ttlCutoff = &now
+
+	notExpired := expression.AttributeNotExists(expression.Name(ttl.Field)).Or(expression.GreaterThan(expression.Name(ttl.Field), expression.Value(now)))
+
+	if b.condition == nil {
+		return &notExpired
+	}
+
+	cond := b.condition.And(notExpired)
+
+	return &cond
+}
+
+// filteredTtlCutoff returns the time items had to expire after to be updated, if the last built input of a counter
+// update got a ttl condition.
+func (b *updateItemBuilder) filteredTtlCutoff() (int64, bool) {
+	if !b.counting || b.ttlCutoff == nil {
+		return 0, false
+	}
+
+	return *b.ttlCutoff, true
+}
+
+// buildTtlInput reads only the ttl of the item with the given key.
+func (b *updateItemBuilder) buildTtlInput(key map[string]*dynamodb.AttributeValue) *dynamodb.GetItemInput {
+	return &dynamodb.GetItemInput{
+		TableName:            aws.String(b.metadata.TableName),
+		Key:                  key,
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String("#0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String(b.metadata.TimeToLive.Field),
+		},
+	}
+}
+
 func (b *updateItemBuilder) update(callback func() expression.UpdateBuilder) *updateItemBuilder {
 	if b.updateBuilder == nil {
 		ub := expression.UpdateBuilder{}
@@ -0,0 +1,55 @@
+package ddb_test
+
+import (
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUpdateItemBuilder_Increment(t *testing.T) {
+	metadata := getTtlMetadata(t)
+	fakeClock := clock.NewFakeClockAt(time.Unix(1600000000, 0))
+
+	input, err := ddb.NewUpdateItemBuilderWithInterfaces(metadata, fakeClock).
+		WithHash(1).
+		Increment("views", 1).
+		Decrement("stock", 2).
+		WithCondition(ddb.Gte("stock", 2)).
+		Build(&ttlModel{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "SET #2 = if_not_exists(#2, :2) + :3, #0 = if_not_exists(#0, :4) - :5\n", *input.UpdateExpression)
+	assert.Equal(t, "(#0 >= :0) AND ((attribute_not_exists (#1)) OR (#1 > :1))", *input.ConditionExpression)
+	assert.Equal(t, map[string]*string{
+		"#0": aws.String("stock"),
+		"#1": aws.String("ttl"),
+		"#2": aws.String("views"),
+	}, input.ExpressionAttributeNames)
+	assert.Equal(t, map[string]*dynamodb.AttributeValue{
+		":0": {N: aws.String("2")},
+		":1": {N: aws.String("1600000000")},
+		":2": {N: aws.String("0")},
+		":3": {N: aws.String("1")},
+		":4": {N: aws.String("0")},
+		":5": {N: aws.String("2")},
+	}, input.ExpressionAttributeValues)
+
+	input, err = ddb.NewUpdateItemBuilderWithInterfaces(metadata, fakeClock).
+		WithHash(1).
+		Increment("views", 1).
+		DisableTtlFilter().
+		Build(&ttlModel{})
+	assert.NoError(t, err)
+	assert.Nil(t, input.ConditionExpression, "there should be no ttl condition if the ttl filter is disabled")
+
+	input, err = ddb.NewUpdateItemBuilderWithInterfaces(metadata, fakeClock).
+		WithHash(1).
+		Set("category", "news").
+		Build(&ttlModel{})
+	assert.NoError(t, err)
+	assert.Nil(t, input.ConditionExpression, "updates without counters should not get a ttl condition")
+}
@@ -0,0 +1,122 @@
+package ddb
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type dryRunWrite struct {
+	operation                 string
+	tableName                 *string
+	key                       map[string]*dynamodb.AttributeValue
+	item                      map[string]*dynamodb.AttributeValue
+	conditionExpression       *string
+	updateExpression          *string
+	expressionAttributeNames  map[string]*string
+	expressionAttributeValues map[string]*dynamodb.AttributeValue
+}
+
+func logDryRunWrite(ctx context.Context, logger mon.Logger, write dryRunWrite) {
+	fields := mon.Fields{
+		"ddb_operation": write.operation,
+		"ddb_table":     aws.StringValue(write.tableName),
+	}
+
+	addDryRunAttributes(fields, "ddb_key", write.key)
+	addDryRunAttributes(fields, "ddb_item", write.item)
+	addDryRunAttributes(fields, "ddb_expression_attribute_values", write.expressionAttributeValues)
+
+	if write.conditionExpression != nil {
+		fields["ddb_condition_expression"] = *write.conditionExpression
+	}
+
+	if write.updateExpression != nil {
+		fields["ddb_update_expression"] = *write.updateExpression
+	}
+
+	if len(write.expressionAttributeNames) > 0 {
+		fields["ddb_expression_attribute_names"] = aws.StringValueMap(write.expressionAttributeNames)
+	}
+
+	logger.WithContext(ctx).WithFields(fields).Infof("dry run: skipped %s operation on table %s", write.operation, aws.StringValue(write.tableName))
+}
+
+func addDryRunAttributes(fields mon.Fields, name string, attributes map[string]*dynamodb.AttributeValue) {
+	if len(attributes) == 0 {
+		return
+	}
+
+	values := make(map[string]interface{})
+
+	if err := dynamodbattribute.UnmarshalMap(attributes, &values); err != nil {
+		fields[name] = attributes
+		return
+	}
+
+	fields[name] = values
+}
+
+func dryRunWriteRequest(tableName string, request *dynamodb.WriteRequest) dryRunWrite {
+	if request.PutRequest != nil {
+		return dryRunWrite{
+			operation: "BatchPutItems",
+			tableName: aws.String(tableName),
+			item:      request.PutRequest.Item,
+		}
+	}
+
+	return dryRunWrite{
+		operation: "BatchDeleteItems",
+		tableName: aws.String(tableName),
+		key:       request.DeleteRequest.Key,
+	}
+}
+
+func dryRunTransactWriteItem(item *dynamodb.TransactWriteItem) dryRunWrite {
+	switch {
+	case item.Put != nil:
+		return dryRunWrite{
+			operation:                 "TransactPut",
+			tableName:                 item.Put.TableName,
+			item:                      item.Put.Item,
+			conditionExpression:       item.Put.ConditionExpression,
+			expressionAttributeNames:  item.Put.ExpressionAttributeNames,
+			expressionAttributeValues: item.Put.ExpressionAttributeValues,
+		}
+	case item.Update != nil:
+		return dryRunWrite{
+			operation:                 "TransactUpdate",
+			tableName:                 item.Update.TableName,
+			key:                       item.Update.Key,
+			conditionExpression:       item.Update.ConditionExpression,
+			updateExpression:          item.Update.UpdateExpression,
+			expressionAttributeNames:  item.Update.ExpressionAttributeNames,
+			expressionAttributeValues: item.Update.ExpressionAttributeValues,
+		}
+	case item.Delete != nil:
+		return dryRunWrite{
+			operation:                 "TransactDelete",
+			tableName:                 item.Delete.TableName,
+			key:                       item.Delete.Key,
+			conditionExpression:       item.Delete.ConditionExpression,
+			expressionAttributeNames:  item.Delete.ExpressionAttributeNames,
+			expressionAttributeValues: item.Delete.ExpressionAttributeValues,
+		}
+	case item.ConditionCheck != nil:
+		return dryRunWrite{
+			operation:                 "TransactConditionCheck",
+			tableName:                 item.ConditionCheck.TableName,
+			key:                       item.ConditionCheck.Key,
+			conditionExpression:       item.ConditionCheck.ConditionExpression,
+			expressionAttributeNames:  item.ConditionCheck.ExpressionAttributeNames,
+			expressionAttributeValues: item.ConditionCheck.ExpressionAttributeValues,
+		}
+	}
+
+	return dryRunWrite{
+		operation: "TransactWriteItems",
+	}
+}
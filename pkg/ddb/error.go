@@ -28,3 +28,33 @@ func (t TableNotFoundError) Error() string {
 func (t TableNotFoundError) Unwrap() error {
 	return t.err
 }
+
+type ConditionalUpdateReason string
+
+const (
+	ConditionalUpdateReasonCondition ConditionalUpdateReason = "condition"
+	ConditionalUpdateReasonExpired   ConditionalUpdateReason = "expired"
+)
+
+func IsConditionalUpdateError(err error) bool {
+	return errors.As(err, &ConditionalUpdateError{})
+}
+
+// ConditionalUpdateError is returned by UpdateItem if a counter update with Increment or Decrement was skipped. The
+// reason is ConditionalUpdateReasonExpired if the item is expired and ConditionalUpdateReasonCondition if the
+// condition of the builder failed.
+type ConditionalUpdateError struct {
+	TableName string
+	Reason    ConditionalUpdateReason
+}
+
+func NewConditionalUpdateError(tableName string, reason ConditionalUpdateReason) ConditionalUpdateError {
+	return ConditionalUpdateError{
+		TableName: tableName,
+		Reason:    reason,
+	}
+}
+
+func (e ConditionalUpdateError) Error() string {
+	return fmt.Sprintf("skipped the counter update on ddb table %s: %s", e.TableName, e.Reason)
+}
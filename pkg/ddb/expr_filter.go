@@ -15,6 +15,10 @@ type ttlFilterer interface {
 	PerformFilterCondition(item map[string]*dynamodb.AttributeValue) (bool, error)
 }
 
+// filterBuilder hides expired items of tables with a ttl attribute. DynamoDB deletes expired items lazily, usually
+// within a few days after they expired, so until then they are still returned by reads and have to be filtered. For a
+// query or scan, the ttl condition is added to the filter expression; it is applied after reading, so expired items
+// still consume read capacity. Items of a get or batch get are filtered after they have been returned.
 type filterBuilder struct {
 	metadata         *Metadata
 	filterCondition  *expression.ConditionBuilder
@@ -0,0 +1,131 @@
+package ddb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// KeyCondition is the key condition of a query referencing the key attributes by name, e.g.
+//
+//	ddb.KeyEquals("userId", 1).And(ddb.SortBeginsWith("createdAt", "2020-"))
+//
+// Building it checks the attributes against the keys of the queried table or index, so a condition on an attribute
+// which isn't part of the key fails before the request is sent.
+type KeyCondition struct {
+	hashAttribute string
+	hashValue     interface{}
+	sort          *SortKeyCondition
+}
+
+// SortKeyCondition is the condition on the range key of a KeyCondition.
+type SortKeyCondition struct {
+	attribute string
+	build     func(key expression.KeyBuilder) expression.KeyConditionBuilder
+}
+
+func KeyEquals(attribute string, value interface{}) KeyCondition {
+	return KeyCondition{
+		hashAttribute: attribute,
+		hashValue:     value,
+	}
+}
+
+func (c KeyCondition) And(sort SortKeyCondition) KeyCondition {
+	c.sort = &sort
+
+	return c
+}
+
+// Build validates the condition against the keys and returns the condition builder for the KeyConditionExpression.
+func (c KeyCondition) Build(keys KeyAware) (expression.KeyConditionBuilder, error) {
+	hashKey := keys.GetHashKey()
+
+	if hashKey == nil {
+		return expression.KeyConditionBuilder{}, fmt.Errorf("there is no hash key to compare the attribute %s with", c.hashAttribute)
+	}
+
+	if c.hashAttribute != *hashKey {
+		return expression.KeyConditionBuilder{}, fmt.Errorf("the attribute %s is not the hash key, which is %s", c.hashAttribute, *hashKey)
+	}
+
+	condition := expression.KeyEqual(expression.Key(c.hashAttribute), expression.Value(c.hashValue))
+
+	if c.sort == nil {
+		return condition, nil
+	}
+
+	rangeKey := keys.GetRangeKey()
+
+	if rangeKey == nil {
+		return expression.KeyConditionBuilder{}, fmt.Errorf("there is no range key to compare the attribute %s with", c.sort.attribute)
+	}
+
+	if c.sort.attribute != *rangeKey {
+		return expression.KeyConditionBuilder{}, fmt.Errorf("the attribute %s is not the range key, which is %s", c.sort.attribute, *rangeKey)
+	}
+
+	return condition.And(c.sort.build(expression.Key(c.sort.attribute))), nil
+}
+
+func SortEquals(attribute string, value interface{}) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyEqual(key, expression.Value(value))
+		},
+	}
+}
+
+func SortBeginsWith(attribute string, prefix string) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyBeginsWith(key, prefix)
+		},
+	}
+}
+
+func SortBetween(attribute string, lower interface{}, upper interface{}) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyBetween(key, expression.Value(lower), expression.Value(upper))
+		},
+	}
+}
+
+func SortGt(attribute string, value interface{}) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyGreaterThan(key, expression.Value(value))
+		},
+	}
+}
+
+func SortGte(attribute string, value interface{}) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyGreaterThanEqual(key, expression.Value(value))
+		},
+	}
+}
+
+func SortLt(attribute string, value interface{}) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyLessThan(key, expression.Value(value))
+		},
+	}
+}
+
+func SortLte(attribute string, value interface{}) SortKeyCondition {
+	return SortKeyCondition{
+		attribute: attribute,
+		build: func(key expression.KeyBuilder) expression.KeyConditionBuilder {
+			return expression.KeyLessThanEqual(key, expression.Value(value))
+		},
+	}
+}
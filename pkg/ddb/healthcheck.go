@@ -0,0 +1,31 @@
+package ddb
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/healthcheck"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// NewTableHealthcheck reports the table as healthy if it can be described and is not being deleted.
+func NewTableHealthcheck(client dynamodbiface.DynamoDBAPI, tableName string) healthcheck.Healthchecker {
+	return healthcheck.HealthcheckerFunc(func(ctx context.Context) error {
+		out, err := client.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+
+		if err != nil {
+			return fmt.Errorf("can not describe ddb table %s: %w", tableName, err)
+		}
+
+		status := aws.StringValue(out.Table.TableStatus)
+
+		if status == dynamodb.TableStatusDeleting {
+			return fmt.Errorf("ddb table %s has the status %s", tableName, status)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+package ddb_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cloud/mocks"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTableHealthcheck(t *testing.T) {
+	ctx := context.Background()
+	client := new(mocks.DynamoDBAPI)
+	input := &dynamodb.DescribeTableInput{
+		TableName: aws.String("table"),
+	}
+
+	client.On("DescribeTableWithContext", ctx, input).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableStatus: aws.String(dynamodb.TableStatusActive),
+		},
+	}, nil).Once()
+	client.On("DescribeTableWithContext", ctx, input).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableStatus: aws.String(dynamodb.TableStatusDeleting),
+		},
+	}, nil).Once()
+	client.On("DescribeTableWithContext", ctx, input).Return(nil, fmt.Errorf("unreachable")).Once()
+
+	check := ddb.NewTableHealthcheck(client, "table")
+
+	assert.NoError(t, check.Check(ctx))
+	assert.EqualError(t, check.Check(ctx), "ddb table table has the status DELETING")
+	assert.EqualError(t, check.Check(ctx), "can not describe ddb table table: unreachable")
+
+	client.AssertExpectations(t)
+}
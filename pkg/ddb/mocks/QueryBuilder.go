@@ -130,6 +130,22 @@ func (_m *QueryBuilder) WithIndex(name string) ddb.QueryBuilder {
 	return r0
 }
 
+// WithKeyCondition provides a mock function with given fields: condition
+func (_m *QueryBuilder) WithKeyCondition(condition ddb.KeyCondition) ddb.QueryBuilder {
+	ret := _m.Called(condition)
+
+	var r0 ddb.QueryBuilder
+	if rf, ok := ret.Get(0).(func(ddb.KeyCondition) ddb.QueryBuilder); ok {
+		r0 = rf(condition)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
 // WithLimit provides a mock function with given fields: limit
 func (_m *QueryBuilder) WithLimit(limit int) ddb.QueryBuilder {
 	ret := _m.Called(limit)
@@ -162,6 +178,22 @@ func (_m *QueryBuilder) WithPageSize(size int) ddb.QueryBuilder {
 	return r0
 }
 
+// WithPageToken provides a mock function with given fields: token
+func (_m *QueryBuilder) WithPageToken(token string) ddb.QueryBuilder {
+	ret := _m.Called(token)
+
+	var r0 ddb.QueryBuilder
+	if rf, ok := ret.Get(0).(func(string) ddb.QueryBuilder); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
 // WithProjection provides a mock function with given fields: projection
 func (_m *QueryBuilder) WithProjection(projection interface{}) ddb.QueryBuilder {
 	ret := _m.Called(projection)
@@ -4,6 +4,7 @@ package mocks
 
 import context "context"
 import ddb "github.com/applike/gosoline/pkg/ddb"
+import expression "github.com/aws/aws-sdk-go/service/dynamodb/expression"
 import mdl "github.com/applike/gosoline/pkg/mdl"
 import mock "github.com/stretchr/testify/mock"
 
@@ -12,6 +13,11 @@ type Repository struct {
 	mock.Mock
 }
 
+// AddItemObserver provides a mock function with given fields: observer
+func (_m *Repository) AddItemObserver(observer ddb.ItemObserver) {
+	_m.Called(observer)
+}
+
 // BatchDeleteItems provides a mock function with given fields: ctx, value
 func (_m *Repository) BatchDeleteItems(ctx context.Context, value interface{}) (*ddb.OperationResult, error) {
 	ret := _m.Called(ctx, value)
@@ -290,6 +296,29 @@ func (_m *Repository) Scan(ctx context.Context, sb ddb.ScanBuilder, result inter
 	return r0, r1
 }
 
+// ScanAll provides a mock function with given fields: ctx, filter, result
+func (_m *Repository) ScanAll(ctx context.Context, filter *expression.ConditionBuilder, result interface{}) (*ddb.ScanResult, error) {
+	ret := _m.Called(ctx, filter, result)
+
+	var r0 *ddb.ScanResult
+	if rf, ok := ret.Get(0).(func(context.Context, *expression.ConditionBuilder, interface{}) *ddb.ScanResult); ok {
+		r0 = rf(ctx, filter, result)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ddb.ScanResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *expression.ConditionBuilder, interface{}) error); ok {
+		r1 = rf(ctx, filter, result)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ScanBuilder provides a mock function with given fields:
 func (_m *Repository) ScanBuilder() ddb.ScanBuilder {
 	ret := _m.Called()
@@ -130,6 +130,22 @@ func (_m *ScanBuilder) WithPageSize(size int) ddb.ScanBuilder {
 	return r0
 }
 
+// WithPageToken provides a mock function with given fields: token
+func (_m *ScanBuilder) WithPageToken(token string) ddb.ScanBuilder {
+	ret := _m.Called(token)
+
+	var r0 ddb.ScanBuilder
+	if rf, ok := ret.Get(0).(func(string) ddb.ScanBuilder); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.ScanBuilder)
+		}
+	}
+
+	return r0
+}
+
 // WithProjection provides a mock function with given fields: projection
 func (_m *ScanBuilder) WithProjection(projection interface{}) ddb.ScanBuilder {
 	ret := _m.Called(projection)
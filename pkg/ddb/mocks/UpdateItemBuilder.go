@@ -51,6 +51,22 @@ func (_m *UpdateItemBuilder) Build(item interface{}) (*dynamodb.UpdateItemInput,
 	return r0, r1
 }
 
+// Decrement provides a mock function with given fields: path, value
+func (_m *UpdateItemBuilder) Decrement(path string, value interface{}) ddb.UpdateItemBuilder {
+	ret := _m.Called(path, value)
+
+	var r0 ddb.UpdateItemBuilder
+	if rf, ok := ret.Get(0).(func(string, interface{}) ddb.UpdateItemBuilder); ok {
+		r0 = rf(path, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.UpdateItemBuilder)
+		}
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: path, value
 func (_m *UpdateItemBuilder) Delete(path string, value interface{}) ddb.UpdateItemBuilder {
 	ret := _m.Called(path, value)
@@ -67,6 +83,38 @@ func (_m *UpdateItemBuilder) Delete(path string, value interface{}) ddb.UpdateIt
 	return r0
 }
 
+// DisableTtlFilter provides a mock function with given fields:
+func (_m *UpdateItemBuilder) DisableTtlFilter() ddb.UpdateItemBuilder {
+	ret := _m.Called()
+
+	var r0 ddb.UpdateItemBuilder
+	if rf, ok := ret.Get(0).(func() ddb.UpdateItemBuilder); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.UpdateItemBuilder)
+		}
+	}
+
+	return r0
+}
+
+// Increment provides a mock function with given fields: path, value
+func (_m *UpdateItemBuilder) Increment(path string, value interface{}) ddb.UpdateItemBuilder {
+	ret := _m.Called(path, value)
+
+	var r0 ddb.UpdateItemBuilder
+	if rf, ok := ret.Get(0).(func(string, interface{}) ddb.UpdateItemBuilder); ok {
+		r0 = rf(path, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.UpdateItemBuilder)
+		}
+	}
+
+	return r0
+}
+
 // Remove provides a mock function with given fields: path
 func (_m *UpdateItemBuilder) Remove(path string) ddb.UpdateItemBuilder {
 	ret := _m.Called(path)
@@ -0,0 +1,71 @@
+package ddb
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"sync"
+)
+
+const (
+	ItemChangePut    = "put"
+	ItemChangeUpdate = "update"
+	ItemChangeDelete = "delete"
+)
+
+// ItemChange describes an item written by PutItem, UpdateItem or DeleteItem of a repository. Item is the value passed
+// to the operation, so it contains the attributes returned by the operation, if any.
+type ItemChange struct {
+	Operation string
+	TableName string
+	Key       map[string]*dynamodb.AttributeValue
+	Item      interface{}
+}
+
+// ItemObserver is called after every successful write of a repository it was added to, e.g. to invalidate a cache.
+// It runs in the goroutine of the write after the write was done, so it should return fast. Errors of the
+// observer are logged and don't fail the write. Writes failing on their condition, batch writes, transactions and
+// dry runs are not observed.
+//
+//go:generate mockery -name ItemObserver
+type ItemObserver interface {
+	ItemChanged(ctx context.Context, change ItemChange) error
+}
+
+type ItemObserverFunc func(ctx context.Context, change ItemChange) error
+
+func (f ItemObserverFunc) ItemChanged(ctx context.Context, change ItemChange) error {
+	return f(ctx, change)
+}
+
+type itemObservers struct {
+	lck       sync.RWMutex
+	logger    mon.Logger
+	observers []ItemObserver
+}
+
+func newItemObservers(logger mon.Logger) *itemObservers {
+	return &itemObservers{
+		logger:    logger,
+		observers: make([]ItemObserver, 0),
+	}
+}
+
+func (o *itemObservers) add(observer ItemObserver) {
+	o.lck.Lock()
+	defer o.lck.Unlock()
+
+	o.observers = append(o.observers, observer)
+}
+
+func (o *itemObservers) notify(ctx context.Context, change ItemChange) {
+	o.lck.RLock()
+	observers := o.observers
+	o.lck.RUnlock()
+
+	for _, observer := range observers {
+		if err := observer.ItemChanged(ctx, change); err != nil {
+			o.logger.WithContext(ctx).Warnf("%T item observer errored out on %s of an item of table %s: %v", observer, change.Operation, change.TableName, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package ddb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"sort"
+	"strings"
+)
+
+type PageTokenMismatchError struct {
+	TableName string
+	Expected  []string
+	Actual    []string
+}
+
+func (e PageTokenMismatchError) Error() string {
+	return fmt.Sprintf("the page token for table %s contains the key attributes [%s] but the query expects [%s]", e.TableName, strings.Join(e.Actual, ", "), strings.Join(e.Expected, ", "))
+}
+
+func IsPageTokenMismatchError(err error) bool {
+	return errors.As(err, &PageTokenMismatchError{})
+}
+
+// EncodePageToken serializes the LastEvaluatedKey of a read operation into an opaque string which can be
+// handed to a client. An empty key results in an empty token.
+func EncodePageToken(key map[string]*dynamodb.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	serialized, err := json.Marshal(key)
+
+	if err != nil {
+		return "", fmt.Errorf("can not marshal page token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(serialized), nil
+}
+
+// DecodePageToken is the inverse of EncodePageToken
+func DecodePageToken(token string) (map[string]*dynamodb.AttributeValue, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	serialized, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, fmt.Errorf("page token is not valid base64: %w", err)
+	}
+
+	key := make(map[string]*dynamodb.AttributeValue)
+
+	if err = json.Unmarshal(serialized, &key); err != nil {
+		return nil, fmt.Errorf("page token is malformed: %w", err)
+	}
+
+	return key, nil
+}
+
+// decodePageTokenForKeys decodes the token and ensures it contains exactly the given key attributes. A query on an
+// index returns the keys of the index and the table as LastEvaluatedKey, so a token created by a query on another
+// index or table can't be used to resume this one.
+func decodePageTokenForKeys(tableName string, token string, keyFields []string) (map[string]*dynamodb.AttributeValue, error) {
+	key, err := DecodePageToken(token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	actual := make([]string, 0, len(key))
+
+	for field := range key {
+		actual = append(actual, field)
+	}
+
+	expected := uniqueSortedStrings(keyFields)
+	sort.Strings(actual)
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		return nil, PageTokenMismatchError{
+			TableName: tableName,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+
+	return key, nil
+}
+
+func uniqueSortedStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		result = append(result, value)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
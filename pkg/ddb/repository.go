@@ -2,11 +2,13 @@ package ddb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/cloud/aws"
 	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/mdl"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/refl"
@@ -16,8 +18,10 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/cenkalti/backoff"
 	"github.com/hashicorp/go-multierror"
+	"strconv"
 	"time"
 )
 
@@ -41,6 +45,7 @@ const (
 //go:generate mockery -name Repository
 type Repository interface {
 	GetModelId() mdl.ModelId
+	AddItemObserver(observer ItemObserver)
 
 	BatchDeleteItems(ctx context.Context, value interface{}) (*OperationResult, error)
 	BatchGetItems(ctx context.Context, qb BatchGetItemsBuilder, result interface{}) (*OperationResult, error)
@@ -50,6 +55,7 @@ type Repository interface {
 	PutItem(ctx context.Context, qb PutItemBuilder, item interface{}) (*PutItemResult, error)
 	Query(ctx context.Context, qb QueryBuilder, result interface{}) (*QueryResult, error)
 	Scan(ctx context.Context, sb ScanBuilder, result interface{}) (*ScanResult, error)
+	ScanAll(ctx context.Context, filter *expression.ConditionBuilder, result interface{}) (*ScanResult, error)
 	UpdateItem(ctx context.Context, ub UpdateItemBuilder, item interface{}) (*UpdateItemResult, error)
 
 	BatchGetItemsBuilder() BatchGetItemsBuilder
@@ -71,6 +77,7 @@ type repository struct {
 	keyBuilder keyBuilder
 	metadata   *Metadata
 	settings   *Settings
+	observers  *itemObservers
 }
 
 func NewRepository(config cfg.Config, logger mon.Logger, settings *Settings) (Repository, error) {
@@ -81,6 +88,15 @@ func NewRepository(config cfg.Config, logger mon.Logger, settings *Settings) (Re
 	settings.ModelId.PadFromConfig(config)
 	settings.AutoCreate = config.GetBool("aws_dynamoDb_autoCreate")
 	settings.Client.MaxRetries = config.GetInt("aws_sdk_retries")
+	settings.DryRun = settings.DryRun || config.GetBool("ddb.dry_run", false)
+
+	if settings.OperationTimeout == 0 {
+		settings.OperationTimeout = config.GetDuration("ddb.operation_timeout", 0)
+	}
+
+	if settings.ScanCapacityThreshold == 0 {
+		settings.ScanCapacityThreshold = config.GetFloat64("ddb.scan_capacity_threshold", 0)
+	}
 
 	tableName := TableName(settings)
 	client := ProvideClient(config, logger, settings)
@@ -120,6 +136,8 @@ func NewRepository(config cfg.Config, logger mon.Logger, settings *Settings) (Re
 		}
 	}
 
+	healthcheck.Register(fmt.Sprintf("ddb.%s", tableName), NewTableHealthcheck(client, tableName))
+
 	return NewWithInterfaces(logger, tracer, client, executor, settings)
 }
 
@@ -144,6 +162,7 @@ func NewWithInterfaces(logger mon.Logger, tracer tracing.Tracer, client dynamodb
 		keyBuilder: keyBuilder,
 		metadata:   metadata,
 		settings:   settings,
+		observers:  newItemObservers(logger),
 		clock:      clock.Provider,
 	}, nil
 }
@@ -152,10 +171,19 @@ func (r *repository) GetModelId() mdl.ModelId {
 	return r.settings.ModelId
 }
 
+// AddItemObserver registers an observer for the puts, updates and deletes of single items, see ItemObserver. It is
+// safe to add observers while the repository is in use.
+func (r *repository) AddItemObserver(observer ItemObserver) {
+	r.observers.add(observer)
+}
+
 func (r *repository) BatchGetItems(ctx context.Context, qb BatchGetItemsBuilder, items interface{}) (*OperationResult, error) {
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.BatchGetItems")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	unmarshaller, err := NewUnmarshallerFromPtrSlice(items)
 
 	if err != nil {
@@ -247,6 +275,9 @@ func (r *repository) BatchPutItems(ctx context.Context, value interface{}) (*Ope
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.BatchPutItems")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	return r.batchWriteItem(ctx, value, func(item interface{}) (*dynamodb.WriteRequest, error) {
 		marshalledItem, err := dynamodbattribute.MarshalMap(item)
 
@@ -266,6 +297,9 @@ func (r *repository) BatchDeleteItems(ctx context.Context, value interface{}) (*
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.BatchDeleteItems")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	return r.batchWriteItem(ctx, value, func(item interface{}) (*dynamodb.WriteRequest, error) {
 		key, err := r.keyBuilder.fromItem(item)
 
@@ -302,6 +336,14 @@ func (r *repository) batchWriteItem(ctx context.Context, value interface{}, reqB
 			}
 		}
 
+		if r.settings.DryRun {
+			for _, request := range requests {
+				logDryRunWrite(ctx, r.logger, dryRunWriteRequest(r.metadata.TableName, request))
+			}
+
+			continue
+		}
+
 		input := &dynamodb.BatchWriteItemInput{
 			RequestItems: map[string][]*dynamodb.WriteRequest{
 				r.metadata.TableName: requests,
@@ -371,6 +413,9 @@ func (r *repository) DeleteItem(ctx context.Context, db DeleteItemBuilder, item
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.DeleteItem")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	if db == nil {
 		db = r.DeleteItemBuilder()
 	}
@@ -382,6 +427,19 @@ func (r *repository) DeleteItem(ctx context.Context, db DeleteItemBuilder, item
 		return nil, fmt.Errorf("could not build input for DeleteItem operation on table %s: %w", r.metadata.TableName, err)
 	}
 
+	if r.settings.DryRun {
+		logDryRunWrite(ctx, r.logger, dryRunWrite{
+			operation:                 "DeleteItem",
+			tableName:                 input.TableName,
+			key:                       input.Key,
+			conditionExpression:       input.ConditionExpression,
+			expressionAttributeNames:  input.ExpressionAttributeNames,
+			expressionAttributeValues: input.ExpressionAttributeValues,
+		})
+
+		return result, nil
+	}
+
 	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
 		return r.client.DeleteItemRequest(input)
 	})
@@ -402,14 +460,16 @@ func (r *repository) DeleteItem(ctx context.Context, db DeleteItemBuilder, item
 	result.ConditionalCheckFailed = isError(err, dynamodb.ErrCodeConditionalCheckFailedException)
 	result.ConsumedCapacity.add(out.ConsumedCapacity)
 
-	if out.Attributes == nil {
-		return result, nil
-	}
+	if out.Attributes != nil {
+		err = dynamodbattribute.UnmarshalMap(out.Attributes, item)
 
-	err = dynamodbattribute.UnmarshalMap(out.Attributes, item)
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal old value after DeleteItem operation on table %s: %w", r.metadata.TableName, err)
+		}
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal old value after DeleteItem operation on table %s: %w", r.metadata.TableName, err)
+	if !result.ConditionalCheckFailed {
+		r.observers.notify(ctx, r.itemChange(ItemChangeDelete, input.Key, item))
 	}
 
 	return result, nil
@@ -419,6 +479,9 @@ func (r *repository) GetItem(ctx context.Context, qb GetItemBuilder, item interf
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.GetItem")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	if qb == nil {
 		qb = r.GetItemBuilder()
 	}
@@ -479,6 +542,9 @@ func (r *repository) PutItem(ctx context.Context, qb PutItemBuilder, item interf
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.PutItem")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	if !isStruct(item) {
 		return nil, fmt.Errorf("you have to provice a struct value to PutItem on table [%s] but instead used [%T]", r.metadata.TableName, item)
 	}
@@ -495,6 +561,21 @@ func (r *repository) PutItem(ctx context.Context, qb PutItemBuilder, item interf
 
 	result := newPutItemResult()
 
+	if r.settings.DryRun {
+		logDryRunWrite(ctx, r.logger, dryRunWrite{
+			operation:                 "PutItem",
+			tableName:                 input.TableName,
+			item:                      input.Item,
+			conditionExpression:       input.ConditionExpression,
+			expressionAttributeNames:  input.ExpressionAttributeNames,
+			expressionAttributeValues: input.ExpressionAttributeValues,
+		})
+
+		result.IsReturnEmpty = true
+
+		return result, nil
+	}
+
 	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
 		return r.client.PutItemRequest(input)
 	})
@@ -514,17 +595,18 @@ func (r *repository) PutItem(ctx context.Context, qb PutItemBuilder, item interf
 	out := outI.(*dynamodb.PutItemOutput)
 	result.ConditionalCheckFailed = isError(err, dynamodb.ErrCodeConditionalCheckFailedException)
 	result.ConsumedCapacity.add(out.ConsumedCapacity)
+	result.IsReturnEmpty = out.Attributes == nil
 
-	if out.Attributes == nil {
-		result.IsReturnEmpty = true
+	if out.Attributes != nil {
+		err = dynamodbattribute.UnmarshalMap(out.Attributes, item)
 
-		return result, nil
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal old value after PutItem operation on table %s: %w", r.metadata.TableName, err)
+		}
 	}
 
-	err = dynamodbattribute.UnmarshalMap(out.Attributes, item)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal old value after PutItem operation on table %s: %w", r.metadata.TableName, err)
+	if !result.ConditionalCheckFailed {
+		r.observers.notify(ctx, r.itemChange(ItemChangePut, r.keyFromItem(input.Item), item))
 	}
 
 	return result, nil
@@ -534,6 +616,9 @@ func (r *repository) Query(ctx context.Context, qb QueryBuilder, items interface
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.Query")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	op, err := qb.Build(items)
 
 	if err != nil {
@@ -548,7 +633,7 @@ func (r *repository) Query(ctx context.Context, qb QueryBuilder, items interface
 		return op.result, err
 	}
 
-	err = r.readAll(items, func() (*readResult, error) {
+	err = r.readAll(ctx, items, func() (*readResult, error) {
 		return r.doQuery(ctx, op)
 	})
 
@@ -587,6 +672,10 @@ func (r *repository) doQuery(ctx context.Context, op *QueryOperation) (*readResu
 	op.input.Limit = nextPageSize
 	op.input.ExclusiveStartKey = out.LastEvaluatedKey
 
+	if op.result.NextPageToken, err = EncodePageToken(out.LastEvaluatedKey); err != nil {
+		return nil, fmt.Errorf("could not create page token for Query operation on table %s: %w", r.metadata.TableName, err)
+	}
+
 	resp := &readResult{
 		Items:            out.Items,
 		LastEvaluatedKey: out.LastEvaluatedKey,
@@ -600,6 +689,9 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.UpdateItem")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	input, err := ub.Build(item)
 
 	if err != nil {
@@ -607,6 +699,21 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 	}
 
 	result := newUpdateItemResult()
+
+	if r.settings.DryRun {
+		logDryRunWrite(ctx, r.logger, dryRunWrite{
+			operation:                 "UpdateItem",
+			tableName:                 input.TableName,
+			key:                       input.Key,
+			conditionExpression:       input.ConditionExpression,
+			updateExpression:          input.UpdateExpression,
+			expressionAttributeNames:  input.ExpressionAttributeNames,
+			expressionAttributeValues: input.ExpressionAttributeValues,
+		})
+
+		return result, nil
+	}
+
 	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
 		return r.client.UpdateItemRequest(input)
 	})
@@ -627,23 +734,72 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 	result.ConditionalCheckFailed = isError(err, dynamodb.ErrCodeConditionalCheckFailedException)
 	result.ConsumedCapacity.add(out.ConsumedCapacity)
 
-	if out.Attributes == nil {
-		return result, nil
+	if out.Attributes != nil {
+		err = dynamodbattribute.UnmarshalMap(out.Attributes, item)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal old value after UpdateItem operation on table %s: %w", r.metadata.TableName, err)
+		}
 	}
 
-	err = dynamodbattribute.UnmarshalMap(out.Attributes, item)
+	if !result.ConditionalCheckFailed {
+		r.observers.notify(ctx, r.itemChange(ItemChangeUpdate, input.Key, item))
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal old value after UpdateItem operation on table %s: %w", r.metadata.TableName, err)
+	if counter, ok := ub.(*updateItemBuilder); ok && counter.counting && result.ConditionalCheckFailed {
+		return result, r.conditionalUpdateError(ctx, counter, input.Key)
 	}
 
 	return result, nil
 }
 
+// conditionalUpdateError tells apart a failed condition and an expired item for a skipped counter update by reading
+// the ttl of the item.
+func (r *repository) conditionalUpdateError(ctx context.Context, counter *updateItemBuilder, key map[string]*dynamodb.AttributeValue) error {
+	cutoff, ok := counter.filteredTtlCutoff()
+
+	if !ok {
+		return NewConditionalUpdateError(r.metadata.TableName, ConditionalUpdateReasonCondition)
+	}
+
+	ttlField := r.metadata.TimeToLive.Field
+	input := counter.buildTtlInput(key)
+
+	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
+		return r.client.GetItemRequest(input)
+	})
+
+	if err != nil {
+		return fmt.Errorf("could not read the ttl after a skipped counter update on table %s: %w", r.metadata.TableName, err)
+	}
+
+	out := outI.(*dynamodb.GetItemOutput)
+	ttl, ok := out.Item[ttlField]
+
+	if !ok || ttl.N == nil {
+		return NewConditionalUpdateError(r.metadata.TableName, ConditionalUpdateReasonCondition)
+	}
+
+	expiresAt, err := strconv.ParseInt(*ttl.N, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("could not parse the ttl after a skipped counter update on table %s: %w", r.metadata.TableName, err)
+	}
+
+	if expiresAt <= cutoff {
+		return NewConditionalUpdateError(r.metadata.TableName, ConditionalUpdateReasonExpired)
+	}
+
+	return NewConditionalUpdateError(r.metadata.TableName, ConditionalUpdateReasonCondition)
+}
+
 func (r *repository) Scan(ctx context.Context, sb ScanBuilder, items interface{}) (*ScanResult, error) {
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.Scan")
 	defer span.Finish()
 
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
 	if sb == nil {
 		sb = r.ScanBuilder()
 	}
@@ -662,10 +818,47 @@ func (r *repository) Scan(ctx context.Context, sb ScanBuilder, items interface{}
 		return op.result, err
 	}
 
-	err = r.readAll(items, func() (*readResult, error) {
+	err = r.readAll(ctx, items, func() (*readResult, error) {
+		return r.doScan(ctx, op)
+	})
+
+	return op.result, err
+}
+
+// ScanAll reads all pages of a scan of the table into the result slice. The filter is combined with the ttl condition
+// of the table and may be nil. A warning is logged if the scan consumed more capacity than the ScanCapacityThreshold.
+func (r *repository) ScanAll(ctx context.Context, filter *expression.ConditionBuilder, items interface{}) (*ScanResult, error) {
+	_, span := r.tracer.StartSubSpan(ctx, "ddb.ScanAll")
+	defer span.Finish()
+
+	ctx, cancel := r.withOperationTimeout(ctx)
+	defer cancel()
+
+	sb := r.ScanBuilder()
+
+	if filter != nil {
+		sb = sb.WithFilter(*filter)
+	}
+
+	op, err := sb.Build(items)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not build scan operation: %w", err)
+	}
+
+	returnConsumedCapacity := dynamodb.ReturnConsumedCapacityTotal
+	op.input.ReturnConsumedCapacity = &returnConsumedCapacity
+
+	err = r.readAll(ctx, items, func() (*readResult, error) {
 		return r.doScan(ctx, op)
 	})
 
+	threshold := r.settings.ScanCapacityThreshold
+
+	if threshold > 0 && op.result.ConsumedCapacity.Total > threshold {
+		r.logger.WithContext(ctx).Warnf("scan of table %s consumed %.1f capacity units, more than the threshold of %.1f", r.metadata.TableName, op.result.ConsumedCapacity.Total, threshold)
+	}
+
 	return op.result, err
 }
 
@@ -701,6 +894,10 @@ func (r *repository) doScan(ctx context.Context, op *ScanOperation) (*readResult
 	op.input.Limit = nextPageSize
 	op.input.ExclusiveStartKey = out.LastEvaluatedKey
 
+	if op.result.NextPageToken, err = EncodePageToken(out.LastEvaluatedKey); err != nil {
+		return nil, fmt.Errorf("could not create page token for Scan operation on table %s: %w", r.metadata.TableName, err)
+	}
+
 	return &readResult{
 		Items:            out.Items,
 		LastEvaluatedKey: out.LastEvaluatedKey,
@@ -733,10 +930,10 @@ func (r *repository) ScanBuilder() ScanBuilder {
 }
 
 func (r *repository) UpdateItemBuilder() UpdateItemBuilder {
-	return NewUpdateItemBuilder(r.metadata)
+	return NewUpdateItemBuilderWithInterfaces(r.metadata, r.clock)
 }
 
-func (r *repository) readAll(items interface{}, read func() (*readResult, error)) error {
+func (r *repository) readAll(ctx context.Context, items interface{}, read func() (*readResult, error)) error {
 	unmarshaller, err := NewUnmarshallerFromPtrSlice(items)
 
 	if err != nil {
@@ -744,6 +941,11 @@ func (r *repository) readAll(items interface{}, read func() (*readResult, error)
 	}
 
 	for {
+		// the items read so far stay in the result, so the caller can work with a partial result
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("stopped read operation for table %s early: %w", r.metadata.TableName, err)
+		}
+
 		out, err := read()
 
 		if err != nil {
@@ -778,6 +980,10 @@ func (r *repository) readCallback(ctx context.Context, items interface{}, callba
 	var callbackErrors error
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return multierror.Append(callbackErrors, fmt.Errorf("stopped read operation for table %s early: %w", r.metadata.TableName, err))
+		}
+
 		out, err := read()
 
 		if err != nil {
@@ -812,6 +1018,33 @@ func (r *repository) readCallback(ctx context.Context, items interface{}, callba
 	return callbackErrors
 }
 
+func (r *repository) itemChange(operation string, key map[string]*dynamodb.AttributeValue, item interface{}) ItemChange {
+	return ItemChange{
+		Operation: operation,
+		TableName: r.metadata.TableName,
+		Key:       key,
+		Item:      item,
+	}
+}
+
+func (r *repository) keyFromItem(attributes map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	key := make(map[string]*dynamodb.AttributeValue)
+
+	for _, field := range r.metadata.Main.GetKeyFields() {
+		key[field] = attributes[field]
+	}
+
+	return key
+}
+
+func (r *repository) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.settings.OperationTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, r.settings.OperationTimeout)
+}
+
 func isError(err error, awsCode string) bool {
 	var ok bool
 	var aerr awserr.Error
@@ -820,7 +1053,7 @@ func isError(err error, awsCode string) bool {
 		return false
 	}
 
-	if aerr, ok = err.(awserr.Error); !ok {
+	if ok = errors.As(err, &aerr); !ok {
 		return false
 	}
 
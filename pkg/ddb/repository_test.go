@@ -7,14 +7,19 @@ import (
 	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
 	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
 	"github.com/applike/gosoline/pkg/ddb"
+	ddbMocks "github.com/applike/gosoline/pkg/ddb/mocks"
 	"github.com/applike/gosoline/pkg/exec"
 	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/tracing"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"strconv"
 	"testing"
@@ -290,6 +295,87 @@ func (s *RepositoryTestSuite) TestQuery() {
 	s.executor.AssertExpectations(s.T())
 }
 
+func (s *RepositoryTestSuite) TestQuery_PageToken() {
+	lastEvaluatedKey := map[string]*dynamodb.AttributeValue{
+		"id":  {N: aws.String("1")},
+		"rev": {S: aws.String("0")},
+	}
+	input := &dynamodb.QueryInput{
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String("id"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":0": {
+				N: aws.String("1"),
+			},
+		},
+		KeyConditionExpression: aws.String("#0 = :0"),
+		TableName:              aws.String("applike-test-gosoline-ddb-myModel"),
+		Limit:                  aws.Int64(1),
+	}
+	output := &dynamodb.QueryOutput{
+		Count:        aws.Int64(1),
+		ScannedCount: aws.Int64(1),
+		Items: []map[string]*dynamodb.AttributeValue{
+			{
+				"id":  {N: aws.String("1")},
+				"rev": {S: aws.String("0")},
+				"foo": {S: aws.String("bar")},
+			},
+		},
+		LastEvaluatedKey: lastEvaluatedKey,
+	}
+	s.executor.ExpectExecution("QueryRequest", input, output, nil)
+
+	result := make([]model, 0)
+	qb := s.repo.QueryBuilder().WithHash(1).WithLimit(1)
+	res, err := s.repo.Query(context.Background(), qb, &result)
+
+	s.NoError(err)
+	s.Len(result, 1)
+	s.NotEmpty(res.NextPageToken)
+
+	resumedInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  input.ExpressionAttributeNames,
+		ExpressionAttributeValues: input.ExpressionAttributeValues,
+		KeyConditionExpression:    input.KeyConditionExpression,
+		TableName:                 input.TableName,
+		Limit:                     aws.Int64(1),
+		ExclusiveStartKey:         lastEvaluatedKey,
+	}
+	resumedOutput := &dynamodb.QueryOutput{
+		Count:        aws.Int64(0),
+		ScannedCount: aws.Int64(0),
+		Items:        []map[string]*dynamodb.AttributeValue{},
+	}
+	s.executor.ExpectExecution("QueryRequest", resumedInput, resumedOutput, nil)
+
+	result = make([]model, 0)
+	qb = s.repo.QueryBuilder().WithHash(1).WithLimit(1).WithPageToken(res.NextPageToken)
+	res, err = s.repo.Query(context.Background(), qb, &result)
+
+	s.NoError(err)
+	s.Len(result, 0)
+	s.Empty(res.NextPageToken)
+
+	s.executor.AssertExpectations(s.T())
+}
+
+func (s *RepositoryTestSuite) TestQuery_PageTokenMismatch() {
+	token, err := ddb.EncodePageToken(map[string]*dynamodb.AttributeValue{
+		"id":    {N: aws.String("1")},
+		"other": {S: aws.String("0")},
+	})
+	s.NoError(err)
+
+	result := make([]model, 0)
+	qb := s.repo.QueryBuilder().WithHash(1).WithPageToken(token)
+	_, err = s.repo.Query(context.Background(), qb, &result)
+
+	s.Error(err)
+	s.True(ddb.IsPageTokenMismatchError(err))
+}
+
 func (s *RepositoryTestSuite) TestQuery_Canceled() {
 	awsErr := awserr.New(request.CanceledErrorCode, "got canceled", nil)
 
@@ -649,6 +735,362 @@ func (s *RepositoryTestSuite) TestDeleteItem() {
 	s.executor.AssertExpectations(s.T())
 }
 
+func (s *RepositoryTestSuite) TestPutItem_ItemObserver() {
+	item := &model{
+		Id:  1,
+		Rev: "0",
+		Foo: "foo",
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String("applike-test-gosoline-ddb-myModel"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id": {
+				N: aws.String("1"),
+			},
+			"rev": {
+				S: aws.String("0"),
+			},
+			"foo": {
+				S: aws.String("foo"),
+			},
+		},
+	}
+
+	observer := new(ddbMocks.ItemObserver)
+	observer.On("ItemChanged", mock.Anything, ddb.ItemChange{
+		Operation: ddb.ItemChangePut,
+		TableName: "applike-test-gosoline-ddb-myModel",
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				N: aws.String("1"),
+			},
+			"rev": {
+				S: aws.String("0"),
+			},
+		},
+		Item: item,
+	}).Return(fmt.Errorf("observer failed")).Once()
+
+	s.repo.AddItemObserver(observer)
+
+	s.executor.ExpectExecution("PutItemRequest", input, &dynamodb.PutItemOutput{}, nil)
+	_, err := s.repo.PutItem(context.Background(), nil, item)
+	s.NoError(err, "errors of an observer should not fail the write")
+
+	conditionFailed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil)
+	s.executor.ExpectExecution("PutItemRequest", input, &dynamodb.PutItemOutput{}, conditionFailed)
+	res, err := s.repo.PutItem(context.Background(), nil, item)
+	s.NoError(err)
+	s.True(res.ConditionalCheckFailed)
+
+	s.executor.AssertExpectations(s.T())
+	observer.AssertExpectations(s.T())
+}
+
 func TestRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(RepositoryTestSuite))
 }
+
+func TestRepository_Query_CanceledDuringPagination(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+	client := new(cloudMocks.DynamoDBAPI)
+	client.On("QueryRequest", mock.AnythingOfType("*dynamodb.QueryInput")).Run(func(args mock.Arguments) {
+		cancel()
+	}).Return(nil, nil).Once()
+
+	executor := gosoAws.NewTestableExecutor(&client.Mock, gosoAws.TestExecution{
+		Output: &dynamodb.QueryOutput{
+			Count:        aws.Int64(1),
+			ScannedCount: aws.Int64(1),
+			Items: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {N: aws.String("1")},
+					"rev": {S: aws.String("0")},
+					"foo": {S: aws.String("bar")},
+				},
+			},
+			LastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+				"id":  {N: aws.String("1")},
+				"rev": {S: aws.String("0")},
+			},
+		},
+	})
+
+	repo, err := ddb.NewWithInterfaces(logger, tracer, client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "myModel",
+		},
+		Main: ddb.MainSettings{
+			Model: model{},
+		},
+	})
+	assert.NoError(t, err)
+
+	result := make([]model, 0)
+	qb := repo.QueryBuilder().WithHash(1)
+	_, err = repo.Query(ctx, qb, &result)
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Len(t, result, 1, "the items of the first page should be returned")
+
+	executor.AssertExpectations(t)
+}
+
+func TestRepository_ScanAll(t *testing.T) {
+	logger := new(monMocks.Logger)
+	logger.On("WithContext", mock.Anything).Return(logger)
+	logger.On("Warnf", "scan of table %s consumed %.1f capacity units, more than the threshold of %.1f", "----myModel", 12.0, 10.0).Once()
+
+	tracer := tracing.NewNoopTracer()
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo, err := ddb.NewWithInterfaces(logger, tracer, client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "myModel",
+		},
+		Main: ddb.MainSettings{
+			Model: model{},
+		},
+		ScanCapacityThreshold: 10,
+	})
+	assert.NoError(t, err)
+
+	lastEvaluatedKey := map[string]*dynamodb.AttributeValue{
+		"id":  {N: aws.String("1")},
+		"rev": {S: aws.String("0")},
+	}
+
+	firstInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String("foo"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":0": {S: aws.String("bar")},
+		},
+		FilterExpression:       aws.String("#0 = :0"),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+		TableName:              aws.String("----myModel"),
+	}
+	secondInput := *firstInput
+	secondInput.ExclusiveStartKey = lastEvaluatedKey
+
+	executor.ExpectExecution("ScanRequest", firstInput, &dynamodb.ScanOutput{
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(2),
+		ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(6)},
+		Items: []map[string]*dynamodb.AttributeValue{
+			{
+				"id":  {N: aws.String("1")},
+				"rev": {S: aws.String("0")},
+				"foo": {S: aws.String("bar")},
+			},
+		},
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil)
+	executor.ExpectExecution("ScanRequest", &secondInput, &dynamodb.ScanOutput{
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(1),
+		ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(6)},
+		Items: []map[string]*dynamodb.AttributeValue{
+			{
+				"id":  {N: aws.String("2")},
+				"rev": {S: aws.String("0")},
+				"foo": {S: aws.String("bar")},
+			},
+		},
+	}, nil)
+
+	filter := expression.Name("foo").Equal(expression.Value("bar"))
+	result := make([]model, 0)
+	res, err := repo.ScanAll(context.Background(), &filter, &result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model{
+		{Id: 1, Rev: "0", Foo: "bar"},
+		{Id: 2, Rev: "0", Foo: "bar"},
+	}, result)
+	assert.Equal(t, int64(2), res.RequestCount)
+	assert.Equal(t, 12.0, res.ConsumedCapacity.Total)
+
+	executor.AssertExpectations(t)
+	logger.AssertExpectations(t)
+}
+
+func TestRepository_DryRun(t *testing.T) {
+	logger := new(monMocks.Logger)
+	logger.On("WithContext", mock.Anything).Return(logger)
+	logger.On("WithFields", mon.Fields{
+		"ddb_operation":                   "UpdateItem",
+		"ddb_table":                       "----myModel",
+		"ddb_key":                         map[string]interface{}{"id": float64(1), "rev": "0"},
+		"ddb_condition_expression":        "#0 = :0",
+		"ddb_update_expression":           "SET #0 = :1\n",
+		"ddb_expression_attribute_names":  map[string]string{"#0": "foo"},
+		"ddb_expression_attribute_values": map[string]interface{}{":0": "bar", ":1": "baz"},
+	}).Return(logger).Once()
+	logger.On("WithFields", mon.Fields{
+		"ddb_operation": "BatchPutItems",
+		"ddb_table":     "----myModel",
+		"ddb_item":      map[string]interface{}{"id": float64(2), "rev": "0", "foo": "bar"},
+	}).Return(logger).Once()
+	logger.On("WithFields", mon.Fields{
+		"ddb_operation": "BatchDeleteItems",
+		"ddb_table":     "----myModel",
+		"ddb_key":       map[string]interface{}{"id": float64(3), "rev": "0"},
+	}).Return(logger).Once()
+	logger.On("Infof", "dry run: skipped %s operation on table %s", "UpdateItem", "----myModel").Once()
+	logger.On("Infof", "dry run: skipped %s operation on table %s", "BatchPutItems", "----myModel").Once()
+	logger.On("Infof", "dry run: skipped %s operation on table %s", "BatchDeleteItems", "----myModel").Once()
+
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo, err := ddb.NewWithInterfaces(logger, tracing.NewNoopTracer(), client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "myModel",
+		},
+		Main: ddb.MainSettings{
+			Model: model{},
+		},
+		DryRun: true,
+	})
+	assert.NoError(t, err)
+
+	ub := repo.UpdateItemBuilder().
+		WithHash(1).
+		WithRange("0").
+		WithCondition(ddb.Eq("foo", "bar")).
+		Set("foo", "baz")
+	updateResult, err := repo.UpdateItem(context.Background(), ub, &model{})
+
+	assert.NoError(t, err)
+	assert.False(t, updateResult.ConditionalCheckFailed)
+
+	_, err = repo.BatchPutItems(context.Background(), []model{{Id: 2, Rev: "0", Foo: "bar"}})
+	assert.NoError(t, err)
+
+	_, err = repo.BatchDeleteItems(context.Background(), []model{{Id: 3, Rev: "0"}})
+	assert.NoError(t, err)
+
+	logger.AssertExpectations(t)
+	client.AssertExpectations(t)
+	executor.AssertExpectations(t)
+}
+
+func TestRepository_GetItem_DisableTtlFilter(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo, err := ddb.NewWithInterfaces(logger, tracer, client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "ttlModel",
+		},
+		Main: ddb.MainSettings{
+			Model: ttlModel{},
+		},
+	})
+	assert.NoError(t, err)
+
+	input := &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {N: aws.String("1")},
+		},
+		TableName: aws.String("----ttlModel"),
+	}
+	output := &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":       {N: aws.String("1")},
+			"category": {S: aws.String("a")},
+			"ttl":      {N: aws.String("1")},
+		},
+	}
+
+	executor.ExpectExecution("GetItemRequest", input, output, nil)
+	executor.ExpectExecution("GetItemRequest", input, output, nil)
+
+	item := ttlModel{}
+	res, err := repo.GetItem(context.Background(), repo.GetItemBuilder().WithHash(1), &item)
+
+	assert.NoError(t, err)
+	assert.False(t, res.IsFound)
+	assert.Equal(t, ttlModel{}, item)
+
+	res, err = repo.GetItem(context.Background(), repo.GetItemBuilder().WithHash(1).DisableTtlFilter(), &item)
+
+	assert.NoError(t, err)
+	assert.True(t, res.IsFound)
+	assert.Equal(t, ttlModel{Id: 1, Category: "a", Ttl: 1}, item)
+
+	executor.AssertExpectations(t)
+}
+
+func TestRepository_UpdateItem_CounterSkipped(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo, err := ddb.NewWithInterfaces(logger, tracer, client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "ttlModel",
+		},
+		Main: ddb.MainSettings{
+			Model: ttlModel{},
+		},
+	})
+	assert.NoError(t, err)
+
+	conditionFailed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil)
+	updateInput := mock.AnythingOfType("*dynamodb.UpdateItemInput")
+	ttlInput := &dynamodb.GetItemInput{
+		TableName: aws.String("----ttlModel"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {N: aws.String("1")},
+		},
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     aws.String("#0"),
+		ExpressionAttributeNames: map[string]*string{"#0": aws.String("ttl")},
+	}
+
+	executor.ExpectExecution("UpdateItemRequest", updateInput, &dynamodb.UpdateItemOutput{}, conditionFailed)
+	executor.ExpectExecution("GetItemRequest", ttlInput, &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"ttl": {N: aws.String("1")},
+		},
+	}, nil)
+
+	res, err := repo.UpdateItem(context.Background(), repo.UpdateItemBuilder().WithHash(1).Decrement("stock", 1), &ttlModel{})
+	assert.Equal(t, ddb.NewConditionalUpdateError("----ttlModel", ddb.ConditionalUpdateReasonExpired), err)
+	assert.True(t, res.ConditionalCheckFailed)
+
+	executor.ExpectExecution("UpdateItemRequest", updateInput, &dynamodb.UpdateItemOutput{}, conditionFailed)
+	executor.ExpectExecution("GetItemRequest", ttlInput, &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"ttl": {N: aws.String("32503680000")},
+		},
+	}, nil)
+
+	_, err = repo.UpdateItem(context.Background(), repo.UpdateItemBuilder().WithHash(1).Decrement("stock", 1).WithCondition(ddb.Gte("stock", 1)), &ttlModel{})
+	assert.Equal(t, ddb.NewConditionalUpdateError("----ttlModel", ddb.ConditionalUpdateReasonCondition), err)
+
+	executor.ExpectExecution("UpdateItemRequest", updateInput, &dynamodb.UpdateItemOutput{}, conditionFailed)
+
+	_, err = repo.UpdateItem(context.Background(), repo.UpdateItemBuilder().WithHash(1).Decrement("stock", 1).WithCondition(ddb.Gte("stock", 1)).DisableTtlFilter(), &ttlModel{})
+	assert.True(t, ddb.IsConditionalUpdateError(err))
+	assert.EqualError(t, err, "skipped the counter update on ddb table ----ttlModel: condition")
+
+	executor.ExpectExecution("UpdateItemRequest", updateInput, &dynamodb.UpdateItemOutput{}, conditionFailed)
+
+	res, err = repo.UpdateItem(context.Background(), repo.UpdateItemBuilder().WithHash(1).Set("category", "a").WithCondition(ddb.Eq("category", "b")), &ttlModel{})
+	assert.NoError(t, err, "only skipped counter updates should be reported as error")
+	assert.True(t, res.ConditionalCheckFailed)
+
+	executor.AssertExpectations(t)
+}
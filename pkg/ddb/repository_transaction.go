@@ -28,6 +28,7 @@ type transactionRepository struct {
 	client   dynamodbiface.DynamoDBAPI
 	executor aws.Executor
 	tracer   tracing.Tracer
+	dryRun   bool
 }
 
 func NewTransactionRepository(config cfg.Config, logger mon.Logger) (*transactionRepository, error) {
@@ -61,7 +62,19 @@ func NewTransactionRepository(config cfg.Config, logger mon.Logger) (*transactio
 
 	executor := aws.NewExecutor(logger, res, &settings.Backoff, checks...)
 
-	return NewTransactionRepositoryWithInterfaces(logger, client, executor, tracer), nil
+	repository := NewTransactionRepositoryWithInterfaces(logger, client, executor, tracer)
+	repository.dryRun = config.GetBool("ddb.dry_run", false)
+
+	return repository, nil
+}
+
+// NewDryRunTransactionRepositoryWithInterfaces returns a transaction repository which logs every write of a
+// transaction instead of executing it. Get transactions are still executed.
+func NewDryRunTransactionRepositoryWithInterfaces(logger mon.Logger, client dynamodbiface.DynamoDBAPI, executor aws.Executor, tracer tracing.Tracer) *transactionRepository {
+	repository := NewTransactionRepositoryWithInterfaces(logger, client, executor, tracer)
+	repository.dryRun = true
+
+	return repository
 }
 
 func NewTransactionRepositoryWithInterfaces(logger mon.Logger, client dynamodbiface.DynamoDBAPI, executor aws.Executor, tracer tracing.Tracer) *transactionRepository {
@@ -149,6 +162,14 @@ func (r transactionRepository) TransactWriteItemsIdempotent(ctx context.Context,
 		transactionItems = append(transactionItems, item)
 	}
 
+	if r.dryRun {
+		for _, item := range transactionItems {
+			logDryRunWrite(ctx, r.logger, dryRunTransactWriteItem(item))
+		}
+
+		return res, nil
+	}
+
 	input := dynamodb.TransactWriteItemsInput{
 		ClientRequestToken: clientRequestToken,
 		TransactItems:      transactionItems,
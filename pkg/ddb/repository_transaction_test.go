@@ -7,6 +7,7 @@ import (
 	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
 	"github.com/applike/gosoline/pkg/ddb"
 	ddbMocks "github.com/applike/gosoline/pkg/ddb/mocks"
+	"github.com/applike/gosoline/pkg/mon"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	tracingMocks "github.com/applike/gosoline/pkg/tracing/mocks"
 	"github.com/aws/aws-sdk-go/aws"
@@ -359,6 +360,77 @@ func (s *RepositoryTransactionTestSuite) TestTransactWriteItems() {
 	assert.Equal(s.T(), expected, result)
 }
 
+func (s *RepositoryTransactionTestSuite) TestTransactWriteItems_DryRun() {
+	logger := new(monMocks.Logger)
+	logger.On("WithContext", mock.Anything).Return(logger)
+	logger.On("WithFields", mon.Fields{
+		"ddb_operation":                   "TransactUpdate",
+		"ddb_table":                       "model",
+		"ddb_key":                         map[string]interface{}{"id": float64(42), "rev": "foo"},
+		"ddb_condition_expression":        "attribute_exists(#id)",
+		"ddb_update_expression":           "SET #foo = :foo",
+		"ddb_expression_attribute_names":  map[string]string{"#id": "id", "#foo": "foo"},
+		"ddb_expression_attribute_values": map[string]interface{}{":foo": "bar"},
+	}).Return(logger).Once()
+	logger.On("Infof", "dry run: skipped %s operation on table %s", "TransactUpdate", "model").Once()
+
+	updateItem := &model{
+		Id:  42,
+		Rev: "foo",
+	}
+
+	updateItemBuilder := new(ddbMocks.UpdateItemBuilder)
+	updateItemBuilder.
+		On("Build", updateItem).
+		Return(&dynamodb.UpdateItemInput{
+			ConditionExpression: aws.String("attribute_exists(#id)"),
+			ExpressionAttributeNames: map[string]*string{
+				"#id":  aws.String("id"),
+				"#foo": aws.String("foo"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":foo": {
+					S: aws.String("bar"),
+				},
+			},
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {
+					N: aws.String("42"),
+				},
+				"rev": {
+					S: aws.String("foo"),
+				},
+			},
+			TableName:        aws.String("model"),
+			UpdateExpression: aws.String("SET #foo = :foo"),
+		}, nil)
+
+	ctx := context.Background()
+
+	items := []ddb.TransactWriteItemBuilder{
+		&ddb.TransactUpdateItem{
+			Builder: updateItemBuilder,
+			Item:    updateItem,
+		},
+	}
+
+	s.tracer.
+		On("StartSubSpan", ctx, "ddb.TransactWriteItems").
+		Return(ctx, s.span)
+
+	s.span.
+		On("Finish").
+		Return()
+
+	repository := ddb.NewDryRunTransactionRepositoryWithInterfaces(logger, s.client, s.executor, s.tracer)
+	result, err := repository.TransactWriteItems(ctx, items)
+
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), result)
+
+	logger.AssertExpectations(s.T())
+}
+
 func buildTransactGetItemBuilder(item *model) ddb.TransactGetItemBuilder {
 	builder := new(ddbMocks.GetItemBuilder)
 
@@ -12,6 +12,7 @@ type Progress interface {
 	GetItemCount() int64
 	GetScannedCount() int64
 	GetConsumedCapacity() *ConsumedCapacity
+	GetNextPageToken() string
 }
 
 type readResult struct {
@@ -69,6 +70,8 @@ type QueryResult struct {
 	ItemCount        int64
 	ScannedCount     int64
 	ConsumedCapacity *ConsumedCapacity
+	// NextPageToken can be passed to QueryBuilder.WithPageToken to continue the query. It is empty if there are no more items.
+	NextPageToken string
 }
 
 func (q QueryResult) GetRequestCount() int64 {
@@ -87,6 +90,10 @@ func (q QueryResult) GetConsumedCapacity() *ConsumedCapacity {
 	return q.ConsumedCapacity
 }
 
+func (q QueryResult) GetNextPageToken() string {
+	return q.NextPageToken
+}
+
 func newQueryResult() *QueryResult {
 	return &QueryResult{
 		ConsumedCapacity: newConsumedCapacity(),
@@ -98,6 +105,8 @@ type ScanResult struct {
 	ItemCount        int64
 	ScannedCount     int64
 	ConsumedCapacity *ConsumedCapacity
+	// NextPageToken can be passed to ScanBuilder.WithPageToken to continue the scan. It is empty if there are no more items.
+	NextPageToken string
 }
 
 func (s ScanResult) GetRequestCount() int64 {
@@ -116,6 +125,10 @@ func (s ScanResult) GetConsumedCapacity() *ConsumedCapacity {
 	return s.ConsumedCapacity
 }
 
+func (s ScanResult) GetNextPageToken() string {
+	return s.NextPageToken
+}
+
 func newScanResult() *ScanResult {
 	return &ScanResult{
 		ConsumedCapacity: newConsumedCapacity(),
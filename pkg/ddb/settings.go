@@ -4,6 +4,7 @@ import (
 	"github.com/applike/gosoline/pkg/cloud"
 	"github.com/applike/gosoline/pkg/exec"
 	"github.com/applike/gosoline/pkg/mdl"
+	"time"
 )
 
 const defaultMaxWaitSeconds = 60
@@ -18,6 +19,18 @@ type Settings struct {
 	Main           MainSettings
 	Local          []LocalSettings
 	Global         []GlobalSettings
+
+	// OperationTimeout limits the duration of every repository operation, 0 disables it. A deadline of
+	// the context passed to an operation is respected as well, the earlier one wins.
+	OperationTimeout time.Duration
+
+	// DryRun logs every put, update and delete with its key, attributes and expressions instead of executing it.
+	// Reads are still executed. It is enabled for all tables by ddb.dry_run as well.
+	DryRun bool
+
+	// ScanCapacityThreshold makes ScanAll log a warning if a scan consumed more capacity units than this, 0 disables
+	// the warning. It is read from ddb.scan_capacity_threshold if not set.
+	ScanCapacityThreshold float64
 }
 
 type MainSettings struct {
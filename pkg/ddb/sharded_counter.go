@@ -0,0 +1,105 @@
+package ddb
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"math/rand"
+)
+
+// a batch get reads at most 100 items, so all shards of a counter can be read with one request
+const maxCounterShards = 100
+
+type ShardedCounterSettings struct {
+	ModelId mdl.ModelId
+	// Shards is the number of items the writes of every counter are spread across, at most 100
+	Shards int
+}
+
+type shardedCounterItem struct {
+	Shard string `json:"shard" ddb:"key=hash"`
+	Count int64  `json:"count"`
+}
+
+// ShardedCounter spreads the writes to a counter across several items with the keys <name>#0 to <name>#<shards-1>,
+// so a frequently written counter doesn't exceed the write throughput of a single partition. Reading a counter sums
+// up all of its shards.
+//
+//go:generate mockery -name ShardedCounter
+type ShardedCounter interface {
+	Increment(ctx context.Context, name string, value int64) error
+	Get(ctx context.Context, name string) (int64, error)
+}
+
+type shardedCounter struct {
+	repo   Repository
+	shards int
+}
+
+func NewShardedCounter(config cfg.Config, logger mon.Logger, settings *ShardedCounterSettings) (ShardedCounter, error) {
+	repo, err := NewRepository(config, logger, &Settings{
+		ModelId: settings.ModelId,
+		Main: MainSettings{
+			Model: shardedCounterItem{},
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create repository for sharded counter: %w", err)
+	}
+
+	return NewShardedCounterWithInterfaces(repo, settings.Shards)
+}
+
+func NewShardedCounterWithInterfaces(repo Repository, shards int) (ShardedCounter, error) {
+	if shards < 1 || shards > maxCounterShards {
+		return nil, fmt.Errorf("the number of counter shards has to be between 1 and %d, got %d", maxCounterShards, shards)
+	}
+
+	return &shardedCounter{
+		repo:   repo,
+		shards: shards,
+	}, nil
+}
+
+// Increment adds the value to a randomly chosen shard of the counter, negative values decrement it.
+func (c *shardedCounter) Increment(ctx context.Context, name string, value int64) error {
+	shard := shardKey(name, rand.Intn(c.shards))
+	ub := c.repo.UpdateItemBuilder().WithHash(shard).Increment("count", value)
+
+	if _, err := c.repo.UpdateItem(ctx, ub, &shardedCounterItem{}); err != nil {
+		return fmt.Errorf("can not increment shard %s: %w", shard, err)
+	}
+
+	return nil
+}
+
+// Get returns the sum of all shards of the counter, 0 if it was never incremented.
+func (c *shardedCounter) Get(ctx context.Context, name string) (int64, error) {
+	keys := make([]string, c.shards)
+
+	for i := range keys {
+		keys[i] = shardKey(name, i)
+	}
+
+	items := make([]shardedCounterItem, 0, c.shards)
+	qb := c.repo.BatchGetItemsBuilder().WithHashKeys(keys)
+
+	if _, err := c.repo.BatchGetItems(ctx, qb, &items); err != nil {
+		return 0, fmt.Errorf("can not read the shards of counter %s: %w", name, err)
+	}
+
+	var sum int64
+
+	for _, item := range items {
+		sum += item.Count
+	}
+
+	return sum, nil
+}
+
+func shardKey(name string, shard int) string {
+	return fmt.Sprintf("%s#%d", name, shard)
+}
@@ -0,0 +1,111 @@
+package ddb_test
+
+import (
+	"context"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/mdl"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type counterShard struct {
+	Shard string `json:"shard" ddb:"key=hash"`
+	Count int64  `json:"count"`
+}
+
+func getShardedCounter(t *testing.T, shards int) (ddb.ShardedCounter, *gosoAws.TestableExecutor) {
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo, err := ddb.NewWithInterfaces(monMocks.NewLoggerMockedAll(), tracing.NewNoopTracer(), client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "counters",
+		},
+		Main: ddb.MainSettings{
+			Model: counterShard{},
+		},
+	})
+	assert.NoError(t, err)
+
+	counter, err := ddb.NewShardedCounterWithInterfaces(repo, shards)
+	assert.NoError(t, err)
+
+	return counter, executor
+}
+
+func TestShardedCounter_Increment(t *testing.T) {
+	counter, executor := getShardedCounter(t, 1)
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String("----counters"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"shard": {S: aws.String("visits#0")},
+		},
+		UpdateExpression: aws.String("SET #0 = if_not_exists(#0, :0) + :1\n"),
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String("count"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":0": {N: aws.String("0")},
+			":1": {N: aws.String("5")},
+		},
+	}
+	executor.ExpectExecution("UpdateItemRequest", input, &dynamodb.UpdateItemOutput{}, nil)
+
+	err := counter.Increment(context.Background(), "visits", 5)
+	assert.NoError(t, err)
+
+	executor.AssertExpectations(t)
+}
+
+func TestShardedCounter_Get(t *testing.T) {
+	counter, executor := getShardedCounter(t, 3)
+
+	input := &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"----counters": {
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"shard": {S: aws.String("visits#0")}},
+					{"shard": {S: aws.String("visits#1")}},
+					{"shard": {S: aws.String("visits#2")}},
+				},
+			},
+		},
+	}
+	output := &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"----counters": {
+				{
+					"shard": {S: aws.String("visits#0")},
+					"count": {N: aws.String("3")},
+				},
+				{
+					"shard": {S: aws.String("visits#2")},
+					"count": {N: aws.String("4")},
+				},
+			},
+		},
+		UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{},
+	}
+	executor.ExpectExecution("BatchGetItemRequest", input, output, nil)
+
+	count, err := counter.Get(context.Background(), "visits")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), count, "missing shards should count as 0")
+
+	executor.AssertExpectations(t)
+}
+
+func TestNewShardedCounterWithInterfaces_InvalidShards(t *testing.T) {
+	_, err := ddb.NewShardedCounterWithInterfaces(nil, 0)
+	assert.EqualError(t, err, "the number of counter shards has to be between 1 and 100, got 0")
+
+	_, err = ddb.NewShardedCounterWithInterfaces(nil, 101)
+	assert.EqualError(t, err, "the number of counter shards has to be between 1 and 100, got 101")
+}
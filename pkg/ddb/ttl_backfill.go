@@ -0,0 +1,239 @@
+package ddb
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TtlFunc computes the expiry of an item read by a TtlBackfill, e.g. its creation time plus the retention. The item
+// is a value of the model of the repository. Returning the zero time leaves the item without ttl.
+type TtlFunc func(item interface{}) (time.Time, error)
+
+type TtlBackfillSettings struct {
+	// Segments is the number of segments of the parallel scan, every segment is scanned by its own go routine
+	Segments int
+	// PageSize is the number of items read by one scan request. All of them are updated before the next page is read.
+	PageSize int
+	// PageTokens resumes an interrupted backfill with the PageTokens of its result. Only the contained segments are
+	// scanned, so segments which finished already aren't scanned again.
+	PageTokens map[int]string
+	// DryRun counts and logs the items which would be updated without writing them
+	DryRun bool
+}
+
+type TtlBackfillResult struct {
+	// ItemCount is the number of items without ttl which were read
+	ItemCount    int64
+	UpdatedCount int64
+	// SkippedCount contains the items the TtlFunc returned no expiry for and the items which got a ttl written
+	// by someone else while the backfill was running
+	SkippedCount int64
+	// PageTokens contains the position of every segment which didn't finish, keyed by the segment. An empty token
+	// restarts the segment from its beginning.
+	PageTokens map[int]string
+}
+
+// TtlBackfill writes the ttl attribute of all items of a table which don't have one yet, which is needed after
+// enabling the ttl of a table with existing items, as they would never expire otherwise. DynamoDB doesn't support
+// batch updates and a batch put would replace attributes written in the meantime, so every item is updated with a
+// conditional UpdateItem which only succeeds if the item still has no ttl.
+//
+//go:generate mockery -name TtlBackfill
+type TtlBackfill interface {
+	Run(ctx context.Context, ttl TtlFunc) (*TtlBackfillResult, error)
+}
+
+type ttlBackfill struct {
+	logger   mon.Logger
+	repo     Repository
+	ttlField string
+	settings *TtlBackfillSettings
+}
+
+func NewTtlBackfill(config cfg.Config, logger mon.Logger, repoSettings *Settings, settings *TtlBackfillSettings) (TtlBackfill, error) {
+	metadata, err := NewMetadataFactory().GetMetadata(repoSettings)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not read the metadata of ddb table %s: %w", TableName(repoSettings), err)
+	}
+
+	if !metadata.TimeToLive.Enabled {
+		return nil, fmt.Errorf("the model of ddb table %s has no ttl attribute", metadata.TableName)
+	}
+
+	repo, err := NewRepository(config, logger, repoSettings)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create repository for ttl backfill: %w", err)
+	}
+
+	return NewTtlBackfillWithInterfaces(logger, repo, metadata.TimeToLive.Field, settings), nil
+}
+
+func NewTtlBackfillWithInterfaces(logger mon.Logger, repo Repository, ttlField string, settings *TtlBackfillSettings) TtlBackfill {
+	if settings.Segments < 1 {
+		settings.Segments = 1
+	}
+
+	return &ttlBackfill{
+		logger:   logger,
+		repo:     repo,
+		ttlField: ttlField,
+		settings: settings,
+	}
+}
+
+// Run scans all segments in parallel and writes the ttl computed by the given function to every item without one.
+// The result contains the page tokens of all segments which didn't finish, also if an error is returned, so a failed
+// or canceled backfill can be resumed by passing them as TtlBackfillSettings.PageTokens.
+func (b *ttlBackfill) Run(ctx context.Context, ttl TtlFunc) (*TtlBackfillResult, error) {
+	result := &TtlBackfillResult{
+		PageTokens: make(map[int]string),
+	}
+
+	segments := b.settings.PageTokens
+
+	if len(segments) == 0 {
+		segments = make(map[int]string, b.settings.Segments)
+
+		for segment := 0; segment < b.settings.Segments; segment++ {
+			segments[segment] = ""
+		}
+	}
+
+	for segment, token := range segments {
+		if segment < 0 || segment >= b.settings.Segments {
+			return nil, fmt.Errorf("the page token of segment %d doesn't fit into a scan of %d segments", segment, b.settings.Segments)
+		}
+
+		result.PageTokens[segment] = token
+	}
+
+	lck := &sync.Mutex{}
+	cfn := coffin.New()
+
+	for segment, token := range segments {
+		segment, token := segment, token
+
+		cfn.GoWithContextf(ctx, func(ctx context.Context) error {
+			return b.backfillSegment(ctx, ttl, segment, token, result, lck)
+		}, "panic during the ttl backfill of segment %d", segment)
+	}
+
+	err := cfn.Wait()
+
+	b.logger.WithContext(ctx).Infof("ttl backfill done: read %d items without ttl, updated %d and skipped %d, %d segments unfinished", result.ItemCount, result.UpdatedCount, result.SkippedCount, len(result.PageTokens))
+
+	return result, err
+}
+
+func (b *ttlBackfill) backfillSegment(ctx context.Context, ttl TtlFunc, segment int, token string, result *TtlBackfillResult, lck *sync.Mutex) error {
+	logger := b.logger.WithContext(ctx).WithFields(mon.Fields{
+		"segment": segment,
+	})
+
+	sb := b.repo.ScanBuilder().
+		DisableTtlFilter().
+		WithFilter(expression.AttributeNotExists(expression.Name(b.ttlField))).
+		WithSegment(segment, b.settings.Segments).
+		WithPageToken(token)
+
+	if b.settings.PageSize > 0 {
+		sb = sb.WithPageSize(b.settings.PageSize)
+	}
+
+	var segmentErr error
+	var updated, skipped int64
+
+	// the repository continues with the next page after a failed callback, so errors stop the scan explicitly
+	_, err := b.repo.Scan(ctx, sb, func(ctx context.Context, items interface{}, progress Progress) (bool, error) {
+		pageUpdated, pageSkipped, err := b.backfillItems(ctx, ttl, items)
+
+		lck.Lock()
+		defer lck.Unlock()
+
+		updated += pageUpdated
+		skipped += pageSkipped
+		result.UpdatedCount += pageUpdated
+		result.SkippedCount += pageSkipped
+
+		if err != nil {
+			segmentErr = fmt.Errorf("can not backfill the ttl of segment %d: %w", segment, err)
+			return false, nil
+		}
+
+		result.ItemCount += int64(reflect.ValueOf(items).Len())
+		result.PageTokens[segment] = progress.GetNextPageToken()
+
+		logger.WithFields(mon.Fields{
+			"page_token": progress.GetNextPageToken(),
+		}).Infof("ttl backfill of segment %d: scanned %d items, updated %d, skipped %d", segment, progress.GetScannedCount(), updated, skipped)
+
+		return true, nil
+	})
+
+	if segmentErr != nil {
+		return segmentErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not scan segment %d: %w", segment, err)
+	}
+
+	lck.Lock()
+	defer lck.Unlock()
+
+	delete(result.PageTokens, segment)
+
+	return nil
+}
+
+func (b *ttlBackfill) backfillItems(ctx context.Context, ttl TtlFunc, items interface{}) (updated int64, skipped int64, err error) {
+	values := reflect.ValueOf(items)
+
+	for i := 0; i < values.Len(); i++ {
+		item := values.Index(i).Interface()
+		expiry, err := ttl(item)
+
+		if err != nil {
+			return updated, skipped, fmt.Errorf("can not compute the ttl of an item: %w", err)
+		}
+
+		if expiry.IsZero() {
+			skipped++
+			continue
+		}
+
+		if b.settings.DryRun {
+			b.logger.WithContext(ctx).Debugf("dry run: skipped setting the ttl of %v to %s", item, expiry.Format(time.RFC3339))
+			updated++
+			continue
+		}
+
+		ub := b.repo.UpdateItemBuilder().
+			WithCondition(expression.AttributeNotExists(expression.Name(b.ttlField))).
+			Set(b.ttlField, expiry.Unix())
+
+		res, err := b.repo.UpdateItem(ctx, ub, item)
+
+		if err != nil {
+			return updated, skipped, fmt.Errorf("can not update the ttl of an item: %w", err)
+		}
+
+		if res.ConditionalCheckFailed {
+			skipped++
+			continue
+		}
+
+		updated++
+	}
+
+	return updated, skipped, nil
+}
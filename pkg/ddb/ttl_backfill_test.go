@@ -0,0 +1,145 @@
+package ddb_test
+
+import (
+	"context"
+	"fmt"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/mdl"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTtlBackfillTest(t *testing.T, settings *ddb.TtlBackfillSettings) (ddb.TtlBackfill, *gosoAws.TestableExecutor) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo, err := ddb.NewWithInterfaces(logger, tracing.NewNoopTracer(), client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "ttlModel",
+		},
+		Main: ddb.MainSettings{
+			Model: ttlModel{},
+		},
+	})
+	assert.NoError(t, err)
+
+	return ddb.NewTtlBackfillWithInterfaces(logger, repo, "ttl", settings), executor
+}
+
+func ttlBackfillScanInput(startKey map[string]*dynamodb.AttributeValue) *dynamodb.ScanInput {
+	return &dynamodb.ScanInput{
+		TableName:                aws.String("----ttlModel"),
+		ExpressionAttributeNames: map[string]*string{"#0": aws.String("ttl")},
+		FilterExpression:         aws.String("attribute_not_exists (#0)"),
+		Segment:                  aws.Int64(0),
+		TotalSegments:            aws.Int64(1),
+		ExclusiveStartKey:        startKey,
+	}
+}
+
+func ttlBackfillUpdateInput(id string, expiry time.Time) *dynamodb.UpdateItemInput {
+	return &dynamodb.UpdateItemInput{
+		TableName:                 aws.String("----ttlModel"),
+		Key:                       map[string]*dynamodb.AttributeValue{"id": {N: aws.String(id)}},
+		ConditionExpression:       aws.String("attribute_not_exists (#0)"),
+		UpdateExpression:          aws.String("SET #0 = :0\n"),
+		ExpressionAttributeNames:  map[string]*string{"#0": aws.String("ttl")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":0": {N: aws.String(fmt.Sprint(expiry.Unix()))}},
+	}
+}
+
+func ttlFromCategory(expiry time.Time) ddb.TtlFunc {
+	return func(item interface{}) (time.Time, error) {
+		if item.(ttlModel).Category == "keep" {
+			return time.Time{}, nil
+		}
+
+		return expiry, nil
+	}
+}
+
+func TestTtlBackfill_Run(t *testing.T) {
+	token, err := ddb.EncodePageToken(map[string]*dynamodb.AttributeValue{"id": {N: aws.String("1")}})
+	assert.NoError(t, err)
+
+	backfill, executor := newTtlBackfillTest(t, &ddb.TtlBackfillSettings{
+		PageTokens: map[int]string{0: token},
+	})
+
+	expiry := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastEvaluatedKey := map[string]*dynamodb.AttributeValue{"id": {N: aws.String("3")}}
+
+	executor.ExpectExecution("ScanRequest", ttlBackfillScanInput(map[string]*dynamodb.AttributeValue{"id": {N: aws.String("1")}}), &dynamodb.ScanOutput{
+		Count:        aws.Int64(2),
+		ScannedCount: aws.Int64(3),
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"id": {N: aws.String("2")}, "category": {S: aws.String("expire")}},
+			{"id": {N: aws.String("3")}, "category": {S: aws.String("keep")}},
+		},
+		LastEvaluatedKey: lastEvaluatedKey,
+	}, nil)
+	executor.ExpectExecution("UpdateItemRequest", ttlBackfillUpdateInput("2", expiry), &dynamodb.UpdateItemOutput{}, nil)
+	executor.ExpectExecution("ScanRequest", ttlBackfillScanInput(lastEvaluatedKey), &dynamodb.ScanOutput{
+		Count:        aws.Int64(0),
+		ScannedCount: aws.Int64(1),
+	}, nil)
+
+	result, err := backfill.Run(context.Background(), ttlFromCategory(expiry))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ddb.TtlBackfillResult{
+		ItemCount:    2,
+		UpdatedCount: 1,
+		SkippedCount: 1,
+		PageTokens:   map[int]string{},
+	}, result)
+
+	executor.AssertExpectations(t)
+}
+
+func TestTtlBackfill_Run_Resumable(t *testing.T) {
+	backfill, executor := newTtlBackfillTest(t, &ddb.TtlBackfillSettings{})
+
+	expiry := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstKey := map[string]*dynamodb.AttributeValue{"id": {N: aws.String("1")}}
+	secondKey := map[string]*dynamodb.AttributeValue{"id": {N: aws.String("2")}}
+
+	executor.ExpectExecution("ScanRequest", ttlBackfillScanInput(nil), &dynamodb.ScanOutput{
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(1),
+		Items:            []map[string]*dynamodb.AttributeValue{{"id": {N: aws.String("1")}}},
+		LastEvaluatedKey: firstKey,
+	}, nil)
+	executor.ExpectExecution("UpdateItemRequest", ttlBackfillUpdateInput("1", expiry), &dynamodb.UpdateItemOutput{}, nil)
+	executor.ExpectExecution("ScanRequest", ttlBackfillScanInput(firstKey), &dynamodb.ScanOutput{
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(1),
+		Items:            []map[string]*dynamodb.AttributeValue{{"id": {N: aws.String("2")}}},
+		LastEvaluatedKey: secondKey,
+	}, nil)
+	executor.ExpectExecution("UpdateItemRequest", ttlBackfillUpdateInput("2", expiry), nil, fmt.Errorf("throttled"))
+
+	result, err := backfill.Run(context.Background(), ttlFromCategory(expiry))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can not backfill the ttl of segment 0")
+
+	token, err := ddb.EncodePageToken(firstKey)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &ddb.TtlBackfillResult{
+		ItemCount:    1,
+		UpdatedCount: 1,
+		PageTokens:   map[int]string{0: token},
+	}, result, "the segment should be resumed at the failed page")
+
+	executor.AssertExpectations(t)
+}
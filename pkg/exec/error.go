@@ -53,6 +53,34 @@ func IsMaxElapsedTimeError(err error) bool {
 	return errors.As(err, &MaxElapsedTimeError{})
 }
 
+// AttemptsError is returned by the backoff executor if an execution failed after it was retried. It wraps the error
+// of the last attempt.
+type AttemptsError struct {
+	Attempts    int
+	ElapsedTime time.Duration
+	err         error
+}
+
+func NewAttemptsError(attempts int, elapsedTime time.Duration, err error) AttemptsError {
+	return AttemptsError{
+		Attempts:    attempts,
+		ElapsedTime: elapsedTime,
+		err:         err,
+	}
+}
+
+func (e AttemptsError) Error() string {
+	return fmt.Sprintf("failed after %d attempts in %s: %s", e.Attempts, e.ElapsedTime, e.err)
+}
+
+func (e AttemptsError) Unwrap() error {
+	return e.err
+}
+
+func IsAttemptsError(err error) bool {
+	return errors.As(err, &AttemptsError{})
+}
+
 func CheckUsedClosedConnectionError(_ interface{}, err error) ErrorType {
 	if IsUsedClosedConnectionError(err) {
 		return ErrorTypeRetryable
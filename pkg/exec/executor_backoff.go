@@ -17,6 +17,8 @@ type BackoffSettings struct {
 	Multiplier          float64       `cfg:"multiplier" default:"1.5"`
 	MaxInterval         time.Duration `cfg:"max_interval" default:"10s"`
 	MaxElapsedTime      time.Duration `cfg:"max_elapsed_time" default:"15m"`
+	// MaxAttempts limits the number of executions including the first one, 0 only limits the elapsed time
+	MaxAttempts int `cfg:"max_attempts" default:"0"`
 }
 
 type BackoffExecutor struct {
@@ -62,8 +64,15 @@ func (e *BackoffExecutor) Execute(ctx context.Context, f Executable) (interface{
 		backoffConfig.MaxElapsedTime = 0
 	}
 
-	backoffCtx := backoff.WithContext(backoffConfig, ctx)
+	var backoffPolicy backoff.BackOff = backoffConfig
 
+	if e.settings.MaxAttempts > 0 {
+		backoffPolicy = backoff.WithMaxRetries(backoffPolicy, uint64(e.settings.MaxAttempts-1))
+	}
+
+	backoffCtx := backoff.WithContext(backoffPolicy, ctx)
+
+	attempts := 0
 	retries := 0
 	start := time.Now()
 
@@ -73,6 +82,7 @@ func (e *BackoffExecutor) Execute(ctx context.Context, f Executable) (interface{
 	}
 
 	_ = backoff.RetryNotify(func() error {
+		attempts++
 		res, err = f(delayedCtx)
 
 		if err == nil {
@@ -86,6 +96,8 @@ func (e *BackoffExecutor) Execute(ctx context.Context, f Executable) (interface{
 			case ErrorTypeOk:
 				return nil
 			case ErrorTypeRetryable:
+				logger.Debugf("attempt %d on resource %s %s failed with a retryable error: %s", attempts, e.resource.Type, e.resource.Name, err.Error())
+
 				return err
 			case ErrorTypePermanent:
 				return backoff.Permanent(err)
@@ -97,6 +109,10 @@ func (e *BackoffExecutor) Execute(ctx context.Context, f Executable) (interface{
 
 	duration := time.Since(start)
 
+	if err != nil && errType != ErrorTypeOk && attempts > 1 {
+		err = NewAttemptsError(attempts, duration, err)
+	}
+
 	// we're having an error after reaching the MaxElapsedTime and the error isn't good-natured
 	if err != nil && errType != ErrorTypeOk && e.settings.MaxElapsedTime > 0 && duration > e.settings.MaxElapsedTime {
 		logger.Warnf("crossed max elapsed time with an error on requesting resource %s %s after %d retries in %s: %s", e.resource.Type, e.resource.Name, retries, duration, err.Error())
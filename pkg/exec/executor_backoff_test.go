@@ -230,6 +230,41 @@ func (s *ExecutorBackoffTestSuite) TestTlsHandshakeTimeoutError() {
 	s.Equal(3, tries)
 }
 
+func (s *ExecutorBackoffTestSuite) TestMaxAttempts() {
+	tries := 0
+	retryableError := fmt.Errorf("retryable error")
+
+	checker := func(result interface{}, err error) exec.ErrorType {
+		return exec.ErrorTypeRetryable
+	}
+
+	resource := &exec.ExecutableResource{
+		Type: "gosoline",
+		Name: "test",
+	}
+
+	settings := &exec.BackoffSettings{
+		Enabled:         true,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond * 2,
+		MaxElapsedTime:  time.Minute,
+		MaxAttempts:     3,
+	}
+
+	executor := exec.NewBackoffExecutor(mocks.NewLoggerMockedAll(), resource, settings, checker)
+	_, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		tries++
+		return nil, retryableError
+	})
+
+	attemptsErr := exec.AttemptsError{}
+
+	s.True(errors.As(err, &attemptsErr))
+	s.Equal(3, attemptsErr.Attempts)
+	s.True(errors.Is(err, retryableError))
+	s.Equal(3, tries)
+}
+
 func TestExecutorBackoffTestSuite(t *testing.T) {
 	suite.Run(t, new(ExecutorBackoffTestSuite))
 }
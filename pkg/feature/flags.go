@@ -0,0 +1,83 @@
+package feature
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/spf13/cast"
+	"hash/fnv"
+)
+
+const buckets = 10000
+
+//go:generate mockery -name Flags
+type Flags interface {
+	IsEnabled(flag string, key string) bool
+}
+
+type flags struct {
+	config cfg.Config
+}
+
+// NewFlags returns the flags configured below features, either as boolean
+//
+//	features:
+//	  new_checkout: true
+//
+// or as rollout to a percentage of the keys. Keys on the deny list are never enabled, keys on the allow list always.
+//
+//	features:
+//	  new_checkout:
+//	    percentage: 25
+//	    allow: [ "1", "7" ]
+//	    deny: [ "3" ]
+//
+// The config is read on every call, so changing the config changes the flags without a restart.
+func NewFlags(config cfg.Config) *flags {
+	return &flags{
+		config: config,
+	}
+}
+
+// IsEnabled returns whether the flag is enabled for the key, e.g. the id of a user. The same key always gets the same
+// result for a flag, as long as the percentage isn't changed. Unknown flags are disabled.
+func (f *flags) IsEnabled(flag string, key string) bool {
+	configKey := fmt.Sprintf("features.%s", flag)
+
+	if !f.config.IsSet(configKey) {
+		return false
+	}
+
+	value := f.config.Get(configKey)
+
+	if _, ok := value.(map[string]interface{}); !ok {
+		enabled, err := cast.ToBoolE(value)
+
+		return err == nil && enabled
+	}
+
+	for _, denied := range f.config.GetStringSlice(configKey+".deny", []string{}) {
+		if denied == key {
+			return false
+		}
+	}
+
+	for _, allowed := range f.config.GetStringSlice(configKey+".allow", []string{}) {
+		if allowed == key {
+			return true
+		}
+	}
+
+	percentage := f.config.GetFloat64(configKey+".percentage", 0)
+
+	return float64(bucket(flag, key)) < percentage*buckets/100
+}
+
+// bucket hashes the flag together with the key, so a key isn't in the first percent of every flag.
+func bucket(flag string, key string) uint32 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(flag))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(key))
+
+	return hash.Sum32() % buckets
+}
@@ -0,0 +1,52 @@
+package feature_test
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/feature"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFlags_IsEnabled(t *testing.T) {
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"features": map[string]interface{}{
+			"on":  true,
+			"off": false,
+			"rollout": map[string]interface{}{
+				"percentage": 50,
+				"allow":      []interface{}{"allowed"},
+				"deny":       []interface{}{"denied"},
+			},
+		},
+	}))
+	assert.NoError(t, err)
+
+	flags := feature.NewFlags(config)
+
+	assert.True(t, flags.IsEnabled("on", "1"))
+	assert.False(t, flags.IsEnabled("off", "1"))
+	assert.False(t, flags.IsEnabled("unknown", "1"), "unknown flags should be disabled")
+
+	enabled := 0
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprint(i)
+
+		if flags.IsEnabled("rollout", key) {
+			enabled++
+		}
+
+		assert.Equal(t, flags.IsEnabled("rollout", key), flags.IsEnabled("rollout", key), "the result for a key should not change")
+	}
+	assert.InDelta(t, 1000, enabled, 100)
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, flags.IsEnabled("rollout", "allowed"))
+		assert.False(t, flags.IsEnabled("rollout", "denied"))
+	}
+
+	err = config.Option(cfg.WithConfigSetting("features.off", true))
+	assert.NoError(t, err)
+	assert.True(t, flags.IsEnabled("off", "1"), "a changed config should take effect without creating the flags again")
+}
@@ -0,0 +1,163 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Healthchecker is implemented by components which can report the health of a dependency, like a database connection
+// or a loaded ip database. Check should respect the deadline of the context.
+//
+//go:generate mockery -name Healthchecker
+type Healthchecker interface {
+	Check(ctx context.Context) error
+}
+
+// TimeoutHealthchecker can be implemented by a Healthchecker to use its own timeout instead of the default one.
+//
+//go:generate mockery -name TimeoutHealthchecker
+type TimeoutHealthchecker interface {
+	GetHealthcheckTimeout() time.Duration
+}
+
+type HealthcheckerFunc func(ctx context.Context) error
+
+func (f HealthcheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type CheckResult struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Result struct {
+	Healthy bool                   `json:"healthy"`
+	Checks  map[string]CheckResult `json:"checks"`
+}
+
+// Failures returns the names of all failed checks in alphabetical order.
+func (r Result) Failures() []string {
+	failures := make([]string, 0)
+
+	for name, check := range r.Checks {
+		if !check.Healthy {
+			failures = append(failures, name)
+		}
+	}
+
+	sort.Strings(failures)
+
+	return failures
+}
+
+type Registry struct {
+	lck    sync.RWMutex
+	checks map[string]Healthchecker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		checks: make(map[string]Healthchecker),
+	}
+}
+
+// Register adds a check under the given name, registering a name twice replaces the former check.
+func (r *Registry) Register(name string, checker Healthchecker) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	r.checks[name] = checker
+}
+
+// Check runs all registered checks concurrently. Every check gets its own timeout, so a slow check only fails
+// itself and doesn't delay the result past its timeout, even if it ignores the deadline of its context.
+func (r *Registry) Check(ctx context.Context, defaultTimeout time.Duration) Result {
+	r.lck.RLock()
+	checks := make(map[string]Healthchecker, len(r.checks))
+
+	for name, checker := range r.checks {
+		checks[name] = checker
+	}
+
+	r.lck.RUnlock()
+
+	result := Result{
+		Healthy: true,
+		Checks:  make(map[string]CheckResult, len(checks)),
+	}
+
+	lck := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	wg.Add(len(checks))
+
+	for name, checker := range checks {
+		go func(name string, checker Healthchecker) {
+			defer wg.Done()
+
+			checkResult := runCheck(ctx, checker, defaultTimeout)
+
+			lck.Lock()
+			defer lck.Unlock()
+
+			result.Checks[name] = checkResult
+			result.Healthy = result.Healthy && checkResult.Healthy
+		}(name, checker)
+	}
+
+	wg.Wait()
+
+	return result
+}
+
+func runCheck(ctx context.Context, checker Healthchecker, timeout time.Duration) CheckResult {
+	if timeoutChecker, ok := checker.(TimeoutHealthchecker); ok {
+		timeout = timeoutChecker.GetHealthcheckTimeout()
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- checker.Check(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("the check did not finish in time: %w", ctx.Err())
+	}
+
+	if err != nil {
+		return CheckResult{
+			Healthy: false,
+			Error:   err.Error(),
+		}
+	}
+
+	return CheckResult{
+		Healthy: true,
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a check to the default registry, which is used by the health check of the apiserver. The sql
+// connections, ddb repositories, kinesis readers and ip readers register their checks there when they are created.
+func Register(name string, checker Healthchecker) {
+	defaultRegistry.Register(name, checker)
+}
+
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
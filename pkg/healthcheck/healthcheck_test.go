@@ -0,0 +1,75 @@
+package healthcheck_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/healthcheck"
+	"github.com/applike/gosoline/pkg/healthcheck/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type slowCheck struct {
+	timeout time.Duration
+}
+
+func (c slowCheck) Check(_ context.Context) error {
+	// ignores the context on purpose
+	time.Sleep(time.Second)
+
+	return nil
+}
+
+func (c slowCheck) GetHealthcheckTimeout() time.Duration {
+	return c.timeout
+}
+
+func TestRegistry_Check_Healthy(t *testing.T) {
+	checker := new(mocks.Healthchecker)
+	checker.On("Check", mock.Anything).Return(nil).Once()
+
+	registry := healthcheck.NewRegistry()
+	registry.Register("db", checker)
+	registry.Register("func", healthcheck.HealthcheckerFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	result := registry.Check(context.Background(), time.Second)
+
+	assert.Equal(t, healthcheck.Result{
+		Healthy: true,
+		Checks: map[string]healthcheck.CheckResult{
+			"db":   {Healthy: true},
+			"func": {Healthy: true},
+		},
+	}, result)
+	assert.Empty(t, result.Failures())
+
+	checker.AssertExpectations(t)
+}
+
+func TestRegistry_Check_Failures(t *testing.T) {
+	checker := new(mocks.Healthchecker)
+	checker.On("Check", mock.Anything).Return(fmt.Errorf("connection refused")).Once()
+
+	registry := healthcheck.NewRegistry()
+	registry.Register("db", checker)
+	registry.Register("slow", slowCheck{timeout: 10 * time.Millisecond})
+	registry.Register("healthy", healthcheck.HealthcheckerFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	start := time.Now()
+	result := registry.Check(context.Background(), time.Minute)
+
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond), "the slow check should not block past its timeout")
+	assert.False(t, result.Healthy)
+	assert.Equal(t, []string{"db", "slow"}, result.Failures())
+	assert.Equal(t, "connection refused", result.Checks["db"].Error)
+	assert.Equal(t, "the check did not finish in time: context deadline exceeded", result.Checks["slow"].Error)
+	assert.True(t, result.Checks["healthy"].Healthy)
+
+	checker.AssertExpectations(t)
+}
@@ -0,0 +1,135 @@
+package ipread
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+var v4InV6Prefix = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff}
+
+type cidrNode struct {
+	children [2]*cidrNode
+	terminal bool
+}
+
+// CidrSet is a set of IPv4 and IPv6 networks. It is stored as binary trie per ip version, so checking an ip takes at
+// most one step per bit of the address, regardless of the number of networks. IPv4-mapped IPv6 addresses like
+// ::ffff:10.0.0.1 are treated as the IPv4 address they contain, on both sides.
+type CidrSet struct {
+	v4 *cidrNode
+	v6 *cidrNode
+}
+
+func NewCidrSet(cidrs []string) (*CidrSet, error) {
+	set := &CidrSet{
+		v4: &cidrNode{},
+		v6: &cidrNode{},
+	}
+
+	for _, cidr := range cidrs {
+		if err := set.Add(cidr); err != nil {
+			return nil, fmt.Errorf("can not parse cidr %s: %w", cidr, err)
+		}
+	}
+
+	return set, nil
+}
+
+// Add adds the network to the set. It must not be called concurrently with Contains.
+func (s *CidrSet) Add(cidr string) error {
+	_, network, err := net.ParseCIDR(cidr)
+
+	if err != nil {
+		return err
+	}
+
+	s.add(network)
+
+	return nil
+}
+
+func (s *CidrSet) Contains(ip net.IP) bool {
+	root, ip := s.root(ip)
+
+	if root == nil {
+		return false
+	}
+
+	node := root
+
+	for i := 0; i < len(ip)*8; i++ {
+		if node.terminal {
+			return true
+		}
+
+		node = node.children[bit(ip, i)]
+
+		if node == nil {
+			return false
+		}
+	}
+
+	return node.terminal
+}
+
+func (s *CidrSet) add(network *net.IPNet) {
+	ones, _ := network.Mask.Size()
+	ip := network.IP
+
+	if len(ip) == net.IPv6len && ones >= 96 && bytes.Equal(ip[:12], v4InV6Prefix) {
+		// a network of IPv4-mapped addresses is stored as the IPv4 network it maps to
+		ones -= 96
+	} else if len(ip) == net.IPv6len && ones < 96 && prefixMatches(ip, v4InV6Prefix, ones) {
+		// a network containing all IPv4-mapped addresses, like ::/0, contains all IPv4 addresses as well
+		s.v4.terminal = true
+		s.v4.children = [2]*cidrNode{}
+	}
+
+	root, ip := s.root(ip)
+	node := root
+
+	for i := 0; i < ones; i++ {
+		if node.terminal {
+			// the network is part of a bigger one already in the set
+			return
+		}
+
+		b := bit(ip, i)
+
+		if node.children[b] == nil {
+			node.children[b] = &cidrNode{}
+		}
+
+		node = node.children[b]
+	}
+
+	node.terminal = true
+	node.children = [2]*cidrNode{}
+}
+
+func (s *CidrSet) root(ip net.IP) (*cidrNode, net.IP) {
+	if v4 := ip.To4(); v4 != nil {
+		return s.v4, v4
+	}
+
+	if v6 := ip.To16(); v6 != nil {
+		return s.v6, v6
+	}
+
+	return nil, nil
+}
+
+func prefixMatches(a []byte, b []byte, bits int) bool {
+	for i := 0; i < bits; i++ {
+		if bit(a, i) != bit(b, i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func bit(ip []byte, i int) int {
+	return int(ip[i/8]>>(7-uint(i%8))) & 1
+}
@@ -0,0 +1,53 @@
+package ipread_test
+
+import (
+	"github.com/applike/gosoline/pkg/ipread"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+func TestCidrSet_Contains(t *testing.T) {
+	set, err := ipread.NewCidrSet([]string{"10.0.0.0/8", "192.168.1.0/24", "192.168.0.0/16", "2001:db8::/32", "::ffff:172.16.0.0/108"})
+	assert.NoError(t, err)
+
+	tests := map[string]bool{
+		"10.1.2.3":           true,
+		"11.1.2.3":           false,
+		"192.168.5.5":        true,
+		"192.169.0.1":        false,
+		"::ffff:10.1.2.3":    true,
+		"::ffff:11.1.2.3":    false,
+		"172.16.0.1":         true,
+		"172.32.0.1":         false,
+		"2001:db8::1":        true,
+		"2001:db9::1":        false,
+		"::1":                false,
+		"::ffff:172.31.1.1":  true,
+		"::ffff:172.32.1.1":  false,
+		"2001:db8:ffff::abc": true,
+	}
+
+	for ip, expected := range tests {
+		assert.Equal(t, expected, set.Contains(net.ParseIP(ip)), ip)
+	}
+
+	assert.False(t, set.Contains(nil))
+}
+
+func TestCidrSet_ContainsAll(t *testing.T) {
+	set, err := ipread.NewCidrSet([]string{"::/0"})
+	assert.NoError(t, err)
+
+	assert.True(t, set.Contains(net.ParseIP("2001:db8::1")))
+	assert.True(t, set.Contains(net.ParseIP("1.2.3.4")), "::/0 should contain the IPv4-mapped addresses")
+
+	set, err = ipread.NewCidrSet([]string{"0.0.0.0/0"})
+	assert.NoError(t, err)
+
+	assert.True(t, set.Contains(net.ParseIP("1.2.3.4")))
+	assert.False(t, set.Contains(net.ParseIP("2001:db8::1")))
+
+	_, err = ipread.NewCidrSet([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, "can not parse cidr 10.0.0.0/33: invalid CIDR address: 10.0.0.0/33")
+}
@@ -0,0 +1,107 @@
+package ipread
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderForwardedFor = "X-Forwarded-For"
+	HeaderRealIp       = "X-Real-IP"
+)
+
+type ClientIpSettings struct {
+	TrustedProxies []string `cfg:"trusted_proxies"`
+}
+
+//go:generate mockery -name ClientIpResolver
+type ClientIpResolver interface {
+	ClientIp(request *http.Request) net.IP
+}
+
+type clientIpResolver struct {
+	trustedProxies *CidrSet
+}
+
+// NewClientIpResolver reads the trusted proxy CIDRs from ipread.<name>.trusted_proxies.
+func NewClientIpResolver(config cfg.Config, name string) (ClientIpResolver, error) {
+	key := fmt.Sprintf("ipread.%s", name)
+	settings := &ClientIpSettings{}
+	config.UnmarshalKey(key, settings)
+
+	return NewClientIpResolverWithSettings(settings)
+}
+
+func NewClientIpResolverWithSettings(settings *ClientIpSettings) (ClientIpResolver, error) {
+	trustedProxies, _ := NewCidrSet(nil)
+
+	for _, cidr := range settings.TrustedProxies {
+		if err := trustedProxies.Add(cidr); err != nil {
+			return nil, fmt.Errorf("can not parse trusted proxy %s: %w", cidr, err)
+		}
+	}
+
+	return &clientIpResolver{
+		trustedProxies: trustedProxies,
+	}, nil
+}
+
+// ClientIp returns the ip of the client which sent the request. The forwarding headers are only taken into account if
+// the request was received from a trusted proxy. As everyone can send these headers, we walk the X-Forwarded-For list
+// from the right and stop at the first entry which was not added by one of our trusted proxies.
+func (r *clientIpResolver) ClientIp(request *http.Request) net.IP {
+	remoteIp := parseRemoteAddr(request.RemoteAddr)
+
+	if remoteIp == nil || !r.isTrusted(remoteIp) {
+		return remoteIp
+	}
+
+	if forwardedFor := request.Header.Values(HeaderForwardedFor); len(forwardedFor) > 0 {
+		return r.clientIpFromForwardedFor(remoteIp, forwardedFor)
+	}
+
+	if realIp := net.ParseIP(strings.TrimSpace(request.Header.Get(HeaderRealIp))); realIp != nil {
+		return realIp
+	}
+
+	return remoteIp
+}
+
+func (r *clientIpResolver) clientIpFromForwardedFor(remoteIp net.IP, headers []string) net.IP {
+	entries := strings.Split(strings.Join(headers, ","), ",")
+	clientIp := remoteIp
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(entries[i]))
+
+		if ip == nil {
+			// whatever is left of a malformed entry can not be trusted
+			return clientIp
+		}
+
+		clientIp = ip
+
+		if !r.isTrusted(ip) {
+			return clientIp
+		}
+	}
+
+	return clientIp
+}
+
+func (r *clientIpResolver) isTrusted(ip net.IP) bool {
+	return r.trustedProxies.Contains(ip)
+}
+
+func parseRemoteAddr(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return net.ParseIP(host)
+}
@@ -0,0 +1,88 @@
+package ipread_test
+
+import (
+	"github.com/applike/gosoline/pkg/ipread"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestClientIpResolver_ClientIp(t *testing.T) {
+	resolver, err := ipread.NewClientIpResolverWithSettings(&ipread.ClientIpSettings{
+		TrustedProxies: []string{"10.0.0.0/8", "130.176.0.0/16"},
+	})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		remoteAddr string
+		headers    map[string][]string
+		expected   string
+	}{
+		"no proxy": {
+			remoteAddr: "1.2.3.4:1234",
+			expected:   "1.2.3.4",
+		},
+		"untrusted remote with spoofed headers": {
+			remoteAddr: "1.2.3.4:1234",
+			headers: map[string][]string{
+				ipread.HeaderForwardedFor: {"5.6.7.8"},
+				ipread.HeaderRealIp:       {"5.6.7.8"},
+			},
+			expected: "1.2.3.4",
+		},
+		"trusted proxy chain": {
+			remoteAddr: "10.0.1.2:1234",
+			headers: map[string][]string{
+				ipread.HeaderForwardedFor: {"6.6.6.6, 1.2.3.4, 130.176.1.1"},
+			},
+			expected: "1.2.3.4",
+		},
+		"multiple forwarded for headers": {
+			remoteAddr: "10.0.1.2:1234",
+			headers: map[string][]string{
+				ipread.HeaderForwardedFor: {"6.6.6.6", "1.2.3.4"},
+			},
+			expected: "1.2.3.4",
+		},
+		"malformed entry": {
+			remoteAddr: "10.0.1.2:1234",
+			headers: map[string][]string{
+				ipread.HeaderForwardedFor: {"1.2.3.4, garbage, 130.176.1.1"},
+			},
+			expected: "130.176.1.1",
+		},
+		"real ip": {
+			remoteAddr: "10.0.1.2:1234",
+			headers: map[string][]string{
+				ipread.HeaderRealIp: {"2001:db8::1"},
+			},
+			expected: "2001:db8::1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := &http.Request{
+				RemoteAddr: test.remoteAddr,
+				Header:     http.Header{},
+			}
+
+			for key, values := range test.headers {
+				for _, value := range values {
+					request.Header.Add(key, value)
+				}
+			}
+
+			assert.Equal(t, net.ParseIP(test.expected), resolver.ClientIp(request))
+		})
+	}
+}
+
+func TestNewClientIpResolverWithSettings_InvalidCidr(t *testing.T) {
+	_, err := ipread.NewClientIpResolverWithSettings(&ipread.ClientIpSettings{
+		TrustedProxies: []string{"10.0.0.0"},
+	})
+
+	assert.EqualError(t, err, "can not parse trusted proxy 10.0.0.0: invalid CIDR address: 10.0.0.0")
+}
@@ -1,9 +1,11 @@
 package ipread
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/healthcheck"
 	"github.com/applike/gosoline/pkg/mon"
 	"net"
 )
@@ -13,6 +15,8 @@ var (
 	ErrIpNotFound    = errors.New("ip not found")
 )
 
+var healthcheckIp = net.ParseIP("8.8.8.8")
+
 type GeoCity struct {
 	City        string `json:"city"`
 	CountryCode string `json:"countryCode"`
@@ -27,6 +31,7 @@ type ReaderSettings struct {
 //go:generate mockery -name Reader
 type Reader interface {
 	City(ipString string) (*GeoCity, error)
+	Country(ip net.IP) (string, error)
 }
 
 type reader struct {
@@ -55,6 +60,8 @@ func NewReader(config cfg.Config, logger mon.Logger, name string) (*reader, erro
 		provider: provider,
 	}
 
+	healthcheck.Register(key, reader)
+
 	return reader, nil
 }
 
@@ -78,3 +85,41 @@ func (r reader) City(ipString string) (*GeoCity, error) {
 		TimeZone:    record.Location.TimeZone,
 	}, nil
 }
+
+// Country returns the iso code of the country of the ip. Providers which implement CountryProvider (like the
+// maxmind-country provider) are queried directly, all other providers are asked for the full city record.
+func (r reader) Country(ip net.IP) (string, error) {
+	if ip == nil {
+		return "", ErrIpParseFailed
+	}
+
+	if provider, ok := r.provider.(CountryProvider); ok {
+		record, err := provider.Country(ip)
+
+		if err != nil {
+			return "", err
+		}
+
+		return record.Country.IsoCode, nil
+	}
+
+	record, err := r.provider.City(ip)
+
+	if err != nil {
+		return "", err
+	}
+
+	return record.Country.IsoCode, nil
+}
+
+// Check reports the reader as healthy if its provider can answer a country lookup. An unknown ip is fine, as it
+// only means the database has no record for it.
+func (r reader) Check(_ context.Context) error {
+	_, err := r.Country(healthcheckIp)
+
+	if err != nil && !errors.Is(err, ErrIpNotFound) {
+		return fmt.Errorf("the ip database can not answer lookups: %w", err)
+	}
+
+	return nil
+}
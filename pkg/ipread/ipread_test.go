@@ -0,0 +1,57 @@
+package ipread_test
+
+import (
+	"context"
+	configMocks "github.com/applike/gosoline/pkg/cfg/mocks"
+	"github.com/applike/gosoline/pkg/healthcheck"
+	"github.com/applike/gosoline/pkg/ipread"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net"
+	"testing"
+)
+
+func TestReader_Country(t *testing.T) {
+	config := new(configMocks.Config)
+	config.On("UnmarshalKey", "ipread.country_test", mock.AnythingOfType("*ipread.ReaderSettings")).Run(func(args mock.Arguments) {
+		args.Get(1).(*ipread.ReaderSettings).Provider = "memory"
+	})
+
+	provider := ipread.ProvideMemoryProvider("country_test")
+	provider.AddCountry("1.2.3.4", "DE")
+	provider.AddRecord("5.6.7.8", ipread.MemoryRecord{
+		CountryIso: "FR",
+		CityName:   "Paris",
+	})
+
+	reader, err := ipread.NewReader(config, monMocks.NewLoggerMockedAll(), "country_test")
+	assert.NoError(t, err)
+
+	country, err := reader.Country(net.ParseIP("1.2.3.4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "DE", country)
+
+	country, err = reader.Country(net.ParseIP("5.6.7.8"))
+	assert.NoError(t, err)
+	assert.Equal(t, "FR", country, "the country should be taken from the city record")
+
+	_, err = reader.Country(net.ParseIP("9.9.9.9"))
+	assert.Equal(t, ipread.ErrIpNotFound, err)
+
+	_, err = reader.Country(nil)
+	assert.Equal(t, ipread.ErrIpParseFailed, err)
+}
+
+func TestReader_Healthcheck(t *testing.T) {
+	config := new(configMocks.Config)
+	config.On("UnmarshalKey", "ipread.healthcheck_test", mock.AnythingOfType("*ipread.ReaderSettings")).Run(func(args mock.Arguments) {
+		args.Get(1).(*ipread.ReaderSettings).Provider = "memory"
+	})
+
+	_, err := ipread.NewReader(config, monMocks.NewLoggerMockedAll(), "healthcheck_test")
+	assert.NoError(t, err)
+
+	result := healthcheck.DefaultRegistry().Check(context.Background(), 0)
+	assert.Equal(t, healthcheck.CheckResult{Healthy: true}, result.Checks["ipread.healthcheck_test"], "an empty database should still answer lookups")
+}
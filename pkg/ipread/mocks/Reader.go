@@ -5,6 +5,8 @@ package mocks
 import (
 	ipread "github.com/applike/gosoline/pkg/ipread"
 	mock "github.com/stretchr/testify/mock"
+
+	net "net"
 )
 
 // Reader is an autogenerated mock type for the Reader type
@@ -34,3 +36,24 @@ func (_m *Reader) City(ipString string) (*ipread.GeoCity, error) {
 
 	return r0, r1
 }
+
+// Country provides a mock function with given fields: ip
+func (_m *Reader) Country(ip net.IP) (string, error) {
+	ret := _m.Called(ip)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(net.IP) string); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(net.IP) error); ok {
+		r1 = rf(ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
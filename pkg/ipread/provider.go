@@ -11,9 +11,16 @@ type Provider interface {
 	City(ipAddress net.IP) (*geoip2.City, error)
 }
 
+// CountryProvider is implemented by providers which can look up the country of an ip without the full city record.
+type CountryProvider interface {
+	Country(ipAddress net.IP) (*geoip2.Country, error)
+}
+
 type ProviderFactory func(config cfg.Config, logger mon.Logger, name string) (Provider, error)
 
 var providers = map[string]ProviderFactory{
-	"maxmind": NewMaxmindProvider,
-	"memory":  NewMemoryProvider,
+	"maxmind":         NewMaxmindProvider,
+	"maxmind-country": NewMaxmindCountryProvider,
+	"maxmind-s3":      NewMaxmindS3Provider,
+	"memory":          NewMemoryProvider,
 }
@@ -0,0 +1,139 @@
+package ipread
+
+import (
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/hashicorp/go-multierror"
+	"github.com/oschwald/geoip2-golang"
+	"net"
+)
+
+func init() {
+	// registered here as the factory looks up the providers of the chain in the providers map itself
+	providers["chain"] = NewChainProviderFromConfig
+}
+
+type chainProvider struct {
+	providers []Provider
+}
+
+// NewChainProvider queries the providers in order until one of them returns a record for the ip. A provider which
+// doesn't know the ip, either by returning ErrIpNotFound or an empty record like maxmind does, falls back to the next
+// one, as does a provider failing with an error. If no provider knows the ip, ErrIpNotFound is returned, otherwise
+// the errors of all failed providers.
+func NewChainProvider(providers ...Provider) Provider {
+	return &chainProvider{
+		providers: providers,
+	}
+}
+
+// NewChainProviderFromConfig creates the providers listed in ipread.<name>.chain in their order and chains them. All
+// of them are created with the name of the reader, so their settings are read from the same ipread.<name> key.
+func NewChainProviderFromConfig(config cfg.Config, logger mon.Logger, name string) (Provider, error) {
+	key := fmt.Sprintf("ipread.%s.chain", name)
+	providerNames := config.GetStringSlice(key)
+
+	if len(providerNames) == 0 {
+		return nil, fmt.Errorf("there are no providers configured in %s", key)
+	}
+
+	chain := make([]Provider, len(providerNames))
+
+	for i, providerName := range providerNames {
+		factory, ok := providers[providerName]
+
+		if !ok || providerName == "chain" {
+			return nil, fmt.Errorf("provider %s not found", providerName)
+		}
+
+		provider, err := factory(config, logger, name)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create provider %s of the chain: %w", providerName, err)
+		}
+
+		chain[i] = provider
+	}
+
+	return NewChainProvider(chain...), nil
+}
+
+func (p *chainProvider) City(ipAddress net.IP) (*geoip2.City, error) {
+	var result error
+
+	for i, provider := range p.providers {
+		record, err := provider.City(ipAddress)
+
+		if errors.Is(err, ErrIpNotFound) || (err == nil && isEmptyCity(record)) {
+			continue
+		}
+
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("provider %d of the chain failed: %w", i, err))
+			continue
+		}
+
+		return record, nil
+	}
+
+	if result == nil {
+		return nil, ErrIpNotFound
+	}
+
+	return nil, result
+}
+
+// Country asks providers implementing CountryProvider for the country only and all other ones for the city record.
+func (p *chainProvider) Country(ipAddress net.IP) (*geoip2.Country, error) {
+	var result error
+
+	for i, provider := range p.providers {
+		isoCode, err := providerCountry(provider, ipAddress)
+
+		if errors.Is(err, ErrIpNotFound) || (err == nil && isoCode == "") {
+			continue
+		}
+
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("provider %d of the chain failed: %w", i, err))
+			continue
+		}
+
+		country := &geoip2.Country{}
+		country.Country.IsoCode = isoCode
+
+		return country, nil
+	}
+
+	if result == nil {
+		return nil, ErrIpNotFound
+	}
+
+	return nil, result
+}
+
+func providerCountry(provider Provider, ipAddress net.IP) (string, error) {
+	if countryProvider, ok := provider.(CountryProvider); ok {
+		record, err := countryProvider.Country(ipAddress)
+
+		if err != nil || record == nil {
+			return "", err
+		}
+
+		return record.Country.IsoCode, nil
+	}
+
+	record, err := provider.City(ipAddress)
+
+	if err != nil || record == nil {
+		return "", err
+	}
+
+	return record.Country.IsoCode, nil
+}
+
+func isEmptyCity(record *geoip2.City) bool {
+	return record == nil || (record.Country.IsoCode == "" && len(record.City.Names) == 0)
+}
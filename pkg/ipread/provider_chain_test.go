@@ -0,0 +1,83 @@
+package ipread_test
+
+import (
+	"fmt"
+	configMocks "github.com/applike/gosoline/pkg/cfg/mocks"
+	"github.com/applike/gosoline/pkg/ipread"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/oschwald/geoip2-golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net"
+	"testing"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) City(_ net.IP) (*geoip2.City, error) {
+	return nil, fmt.Errorf("database not available")
+}
+
+type emptyProvider struct{}
+
+func (emptyProvider) City(_ net.IP) (*geoip2.City, error) {
+	return &geoip2.City{}, nil
+}
+
+func TestChainProvider_City(t *testing.T) {
+	first := ipread.ProvideMemoryProvider("chain_test_first")
+	first.AddRecord("1.2.3.4", ipread.MemoryRecord{
+		CountryIso: "DE",
+		CityName:   "Hamburg",
+	})
+
+	second := ipread.ProvideMemoryProvider("chain_test_second")
+	second.AddRecord("1.2.3.4", ipread.MemoryRecord{
+		CountryIso: "FR",
+		CityName:   "Paris",
+	})
+	second.AddRecord("5.6.7.8", ipread.MemoryRecord{
+		CountryIso: "FR",
+		CityName:   "Lyon",
+	})
+
+	chain := ipread.NewChainProvider(emptyProvider{}, failingProvider{}, first, second)
+
+	record, err := chain.City(net.ParseIP("1.2.3.4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Hamburg", record.City.Names["en"])
+
+	record, err = chain.City(net.ParseIP("5.6.7.8"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Lyon", record.City.Names["en"])
+
+	_, err = chain.City(net.ParseIP("9.9.9.9"))
+	assert.EqualError(t, err, "1 error occurred:\n\t* provider 1 of the chain failed: database not available\n\n")
+
+	_, err = ipread.NewChainProvider(emptyProvider{}, first).City(net.ParseIP("9.9.9.9"))
+	assert.Equal(t, ipread.ErrIpNotFound, err)
+}
+
+func TestChainProvider_FromConfig(t *testing.T) {
+	config := new(configMocks.Config)
+	config.On("UnmarshalKey", "ipread.chain_test", mock.AnythingOfType("*ipread.ReaderSettings")).Run(func(args mock.Arguments) {
+		args.Get(1).(*ipread.ReaderSettings).Provider = "chain"
+	})
+	config.On("GetStringSlice", "ipread.chain_test.chain").Return([]string{"memory"}).Once()
+	config.On("GetStringSlice", "ipread.chain_test.chain").Return([]string{"memory", "chain"}).Once()
+
+	provider := ipread.ProvideMemoryProvider("chain_test")
+	provider.AddCountry("1.2.3.4", "DE")
+
+	reader, err := ipread.NewReader(config, monMocks.NewLoggerMockedAll(), "chain_test")
+	assert.NoError(t, err)
+
+	country, err := reader.Country(net.ParseIP("1.2.3.4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "DE", country)
+
+	_, err = ipread.NewReader(config, monMocks.NewLoggerMockedAll(), "chain_test")
+	assert.EqualError(t, err, "can not create ip reader provider: provider chain not found")
+
+	config.AssertExpectations(t)
+}
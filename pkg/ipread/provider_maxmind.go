@@ -2,11 +2,22 @@ package ipread
 
 import (
 	"fmt"
+	"github.com/applike/gosoline/pkg/blob"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/oschwald/geoip2-golang"
+	"io/ioutil"
+	"net"
 )
 
+type MaxmindS3Settings struct {
+	Bucket string `cfg:"bucket" validate:"required"`
+	Key    string `cfg:"key" validate:"required"`
+}
+
 func NewMaxmindProvider(config cfg.Config, _ mon.Logger, name string) (Provider, error) {
 	key := fmt.Sprintf("ipread.%s.maxmind.database", name)
 	database := config.GetString(key)
@@ -18,3 +29,65 @@ func NewMaxmindProvider(config cfg.Config, _ mon.Logger, name string) (Provider,
 
 	return geoIpReader, nil
 }
+
+type maxmindCountryProvider struct {
+	*geoip2.Reader
+}
+
+// NewMaxmindCountryProvider uses the smaller GeoLite2-Country database. It only supports country lookups.
+func NewMaxmindCountryProvider(config cfg.Config, logger mon.Logger, name string) (Provider, error) {
+	provider, err := NewMaxmindProvider(config, logger, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return maxmindCountryProvider{
+		Reader: provider.(*geoip2.Reader),
+	}, nil
+}
+
+func (p maxmindCountryProvider) City(_ net.IP) (*geoip2.City, error) {
+	return nil, fmt.Errorf("the maxmind-country provider does not support city lookups")
+}
+
+// NewMaxmindS3Provider downloads the database configured in ipread.<name>.maxmind.s3 once and reads it from memory.
+func NewMaxmindS3Provider(config cfg.Config, logger mon.Logger, name string) (Provider, error) {
+	key := fmt.Sprintf("ipread.%s.maxmind.s3", name)
+	settings := &MaxmindS3Settings{}
+	config.UnmarshalKey(key, settings)
+
+	client := blob.ProvideS3Client(config)
+
+	return NewMaxmindS3ProviderWithInterfaces(logger, client, settings)
+}
+
+func NewMaxmindS3ProviderWithInterfaces(logger mon.Logger, client s3iface.S3API, settings *MaxmindS3Settings) (Provider, error) {
+	location := fmt.Sprintf("s3://%s/%s", settings.Bucket, settings.Key)
+
+	out, err := client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(settings.Bucket),
+		Key:    aws.String(settings.Key),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not download geo db from %s: %w", location, err)
+	}
+
+	defer out.Body.Close()
+	database, err := ioutil.ReadAll(out.Body)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not download geo db from %s: %w", location, err)
+	}
+
+	geoIpReader, err := geoip2.FromBytes(database)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open geo db from %s: %w", location, err)
+	}
+
+	logger.Infof("loaded geo db with %d bytes from %s", len(database), location)
+
+	return geoIpReader, nil
+}
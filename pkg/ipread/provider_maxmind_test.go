@@ -0,0 +1,41 @@
+package ipread_test
+
+import (
+	"bytes"
+	"fmt"
+	blobMocks "github.com/applike/gosoline/pkg/blob/mocks"
+	"github.com/applike/gosoline/pkg/ipread"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestNewMaxmindS3ProviderWithInterfaces(t *testing.T) {
+	settings := &ipread.MaxmindS3Settings{
+		Bucket: "geo",
+		Key:    "GeoLite2-City.mmdb",
+	}
+	input := &s3.GetObjectInput{
+		Bucket: aws.String("geo"),
+		Key:    aws.String("GeoLite2-City.mmdb"),
+	}
+
+	client := new(blobMocks.S3API)
+	client.On("GetObject", input).Return(nil, fmt.Errorf("access denied")).Once()
+
+	_, err := ipread.NewMaxmindS3ProviderWithInterfaces(monMocks.NewLoggerMockedAll(), client, settings)
+	assert.EqualError(t, err, "could not download geo db from s3://geo/GeoLite2-City.mmdb: access denied")
+
+	client.On("GetObject", input).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte("not a maxmind database"))),
+	}, nil).Once()
+
+	_, err = ipread.NewMaxmindS3ProviderWithInterfaces(monMocks.NewLoggerMockedAll(), client, settings)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not open geo db from s3://geo/GeoLite2-City.mmdb")
+
+	client.AssertExpectations(t)
+}
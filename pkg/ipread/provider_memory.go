@@ -14,7 +14,8 @@ type MemoryRecord struct {
 }
 
 type memoryProvider struct {
-	records map[string]*geoip2.City
+	records   map[string]*geoip2.City
+	countries map[string]string
 }
 
 var memoryProviderContainer = make(map[string]*memoryProvider)
@@ -25,7 +26,8 @@ func ProvideMemoryProvider(name string) *memoryProvider {
 	}
 
 	memoryProviderContainer[name] = &memoryProvider{
-		records: make(map[string]*geoip2.City),
+		records:   make(map[string]*geoip2.City),
+		countries: make(map[string]string),
 	}
 
 	return memoryProviderContainer[name]
@@ -45,6 +47,30 @@ func (p memoryProvider) City(ipAddress net.IP) (*geoip2.City, error) {
 	return p.records[ipString], nil
 }
 
+func (p memoryProvider) Country(ipAddress net.IP) (*geoip2.Country, error) {
+	ipString := ipAddress.String()
+	country := &geoip2.Country{}
+
+	if countryIso, ok := p.countries[ipString]; ok {
+		country.Country.IsoCode = countryIso
+
+		return country, nil
+	}
+
+	if record, ok := p.records[ipString]; ok {
+		country.Country.IsoCode = record.Country.IsoCode
+
+		return country, nil
+	}
+
+	return nil, ErrIpNotFound
+}
+
+// AddCountry stubs only the country of an ip, which is enough for Reader.Country.
+func (p memoryProvider) AddCountry(ipString string, countryIso string) {
+	p.countries[ipString] = countryIso
+}
+
 func (p memoryProvider) AddRecord(ipString string, record MemoryRecord) {
 	p.records[ipString] = &geoip2.City{
 		City: struct {
@@ -119,6 +119,19 @@ func (k *kernel) AddFactory(factory MultiModuleFactory) {
 	k.multiFactories = append(k.multiFactories, factory)
 }
 
+// flushLogger writes the entries the logger buffered, see mon.WithBufferedOutput.
+func (k *kernel) flushLogger() {
+	flusher, ok := k.logger.(interface{ Flush() error })
+
+	if !ok {
+		return
+	}
+
+	if err := flusher.Flush(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "can not flush the logger: %v\n", err)
+	}
+}
+
 func (k *kernel) Running() <-chan struct{} {
 	return k.running
 }
@@ -127,6 +140,7 @@ func (k *kernel) Run() {
 	// do not allow config changes anymore
 	k.started.Poison()
 
+	defer k.flushLogger()
 	defer k.logger.Info("leaving kernel")
 	k.logger.Info("starting kernel")
 
@@ -425,6 +439,7 @@ func (k *kernel) waitStopped() {
 				}
 			}
 
+			k.flushLogger()
 			k.forceExit(1)
 		case <-done.Channel():
 			return
@@ -13,12 +13,16 @@ type MapXStructSettings struct {
 	DefaultTag string
 	Casters    []MapStructCaster
 	Decoders   []MapStructDecoder
+	// Parsers create values of the given types from strings. Fields of these types are treated as single values,
+	// even if the type is a slice or a struct like net.IP or url.URL.
+	Parsers map[reflect.Type]MapStructParser
 }
 
 type MapXStruct struct {
 	target   interface{}
 	casters  []MapStructCaster
 	decoders []MapStructDecoder
+	parsers  map[reflect.Type]MapStructParser
 	settings *MapXStructSettings
 }
 
@@ -29,10 +33,17 @@ func NewMapStruct(source interface{}, settings *MapXStructSettings) (*MapXStruct
 		return nil, fmt.Errorf("the target value has to be a pointer")
 	}
 
+	parsers := make(map[reflect.Type]MapStructParser, len(settings.Parsers))
+
+	for targetType, parser := range settings.Parsers {
+		parsers[targetType] = parser
+	}
+
 	return &MapXStruct{
 		target:   source,
 		casters:  append([]MapStructCaster{}, settings.Casters...),
 		decoders: append([]MapStructDecoder{}, settings.Decoders...),
+		parsers:  parsers,
 		settings: settings,
 	}, nil
 }
@@ -79,6 +90,22 @@ func (m *MapXStruct) doReadZeroAndDefaultValues(target interface{}) (*MapX, *Map
 			continue
 		}
 
+		// parsed values are kept as raw strings until they are written
+		if m.isParsed(targetField.Type) {
+			values.Set(cfg, nil)
+
+			if val, ok = targetField.Tag.Lookup(m.settings.DefaultTag); !ok {
+				continue
+			}
+
+			if _, err = m.parse(targetField.Type, val); err != nil {
+				return nil, nil, fmt.Errorf("can not read default from field %s: %w", cfg, err)
+			}
+
+			defaults.Set(cfg, val)
+			continue
+		}
+
 		if targetField.Type.Kind() == reflect.Struct && targetField.Type != reflect.TypeOf(time.Time{}) {
 			v, d, err := m.doReadZeroAndDefaultValues(targetValue.Interface())
 
@@ -244,6 +271,11 @@ func (m *MapXStruct) doReadStruct(path string, mapValues *MapX, target interface
 
 		fieldPath = fmt.Sprintf("%s.%s", path, cfg)
 
+		if m.isParsed(fieldValue.Type()) {
+			m.doReadParsed(fieldPath, mapValues, fieldValue)
+			continue
+		}
+
 		if fieldValue.Kind() == reflect.Map {
 			target = fieldValue.Interface()
 
@@ -279,6 +311,23 @@ func (m *MapXStruct) doReadStruct(path string, mapValues *MapX, target interface
 	return nil
 }
 
+// doReadParsed reads values of parsed types as strings if possible, so they can be parsed again when written.
+func (m *MapXStruct) doReadParsed(path string, mapValues *MapX, fieldValue reflect.Value) {
+	switch fieldValue.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		if fieldValue.IsNil() {
+			return
+		}
+	}
+
+	if stringer, ok := fieldValue.Interface().(fmt.Stringer); ok {
+		mapValues.Set(path, stringer.String())
+		return
+	}
+
+	mapValues.Set(path, fieldValue.Interface())
+}
+
 func (m *MapXStruct) Write(values *MapX) error {
 	return m.doWrite(m.target, values)
 }
@@ -330,6 +379,15 @@ func (m *MapXStruct) doWrite(target interface{}, sourceValues *MapX) error {
 
 		sourceValue = sourceValues.Get(cfg).Data()
 
+		if m.isParsed(targetValue.Type()) {
+			if sourceValue, err = m.decodeAndCastValue(targetValue.Type(), sourceValue); err != nil {
+				return fmt.Errorf("can not decode and cast value for key %s: %w", cfg, err)
+			}
+
+			targetValue.Set(reflect.ValueOf(sourceValue))
+			continue
+		}
+
 		if targetValue.Kind() == reflect.Map {
 			if err = m.doWriteMap(cfg, targetValue, sourceValues); err != nil {
 				return err
@@ -485,10 +543,18 @@ func (m *MapXStruct) doWriteStruct(cfg string, targetValue reflect.Value, source
 func (m *MapXStruct) decodeAndCastValue(targetType reflect.Type, sourceValue interface{}) (interface{}, error) {
 	var err error
 
-	if sourceValue, err = m.cast(targetType, sourceValue); err != nil {
-		return nil, fmt.Errorf("provided value %v doesn't match target type %v", sourceValue, targetType)
+	if m.isParsed(targetType) {
+		return m.decodeAndParseValue(targetType, sourceValue)
+	}
+
+	var casted interface{}
+
+	if casted, err = m.cast(targetType, sourceValue); err != nil {
+		return nil, fmt.Errorf("provided value %v doesn't match target type %v: %w", sourceValue, targetType, err)
 	}
 
+	sourceValue = casted
+
 	for _, decoder := range m.decoders {
 		if sourceValue, err = decoder(targetType, sourceValue); err != nil {
 			return nil, fmt.Errorf("can not decode value %v", sourceValue)
@@ -504,6 +570,49 @@ func (m *MapXStruct) decodeAndCastValue(targetType reflect.Type, sourceValue int
 	return sourceValue, nil
 }
 
+// decodeAndParseValue decodes the raw string before parsing it, so the decoders can replace placeholders in it.
+func (m *MapXStruct) decodeAndParseValue(targetType reflect.Type, sourceValue interface{}) (interface{}, error) {
+	var err error
+
+	for _, decoder := range m.decoders {
+		if sourceValue, err = decoder(targetType, sourceValue); err != nil {
+			return nil, fmt.Errorf("can not decode value %v", sourceValue)
+		}
+	}
+
+	return m.parse(targetType, sourceValue)
+}
+
+func (m *MapXStruct) isParsed(targetType reflect.Type) bool {
+	_, ok := m.parsers[targetType]
+
+	return ok
+}
+
+func (m *MapXStruct) parse(targetType reflect.Type, value interface{}) (interface{}, error) {
+	if value == nil {
+		return reflect.Zero(targetType).Interface(), nil
+	}
+
+	if reflect.TypeOf(value) == targetType {
+		return value, nil
+	}
+
+	raw, ok := value.(string)
+
+	if !ok {
+		return nil, fmt.Errorf("value %v of type %T can't be parsed as %v", value, value, targetType)
+	}
+
+	parsed, err := m.parsers[targetType](raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not parse %q as %v: %w", raw, targetType, err)
+	}
+
+	return parsed, nil
+}
+
 func (m *MapXStruct) cast(targetType reflect.Type, value interface{}) (interface{}, error) {
 	for _, caster := range m.casters {
 		casted, err := caster(targetType, value)
@@ -517,6 +626,10 @@ func (m *MapXStruct) cast(targetType reflect.Type, value interface{}) (interface
 		}
 	}
 
+	if m.isParsed(targetType) {
+		return m.parse(targetType, value)
+	}
+
 	switch targetType.Kind() {
 	case reflect.Bool:
 		return cast.ToBoolE(value)
@@ -1,8 +1,14 @@
 package mapx
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/spf13/cast"
+	"net"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,6 +16,35 @@ type MapStructDecoder func(targetType reflect.Type, val interface{}) (interface{
 
 type MapStructCaster func(targetType reflect.Type, value interface{}) (interface{}, error)
 
+// MapStructParser creates a value of the type it is registered for in MapXStructSettings.Parsers from a string.
+type MapStructParser func(raw string) (interface{}, error)
+
+// MapStructIpParser parses net.IP values like 127.0.0.1 or ::1.
+func MapStructIpParser(raw string) (interface{}, error) {
+	ip := net.ParseIP(strings.TrimSpace(raw))
+
+	if ip == nil {
+		return nil, fmt.Errorf("%q is not a valid ip address", raw)
+	}
+
+	return ip, nil
+}
+
+// MapStructUrlParser parses absolute *url.URL values like https://example.com/path.
+func MapStructUrlParser(raw string) (interface{}, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !parsed.IsAbs() {
+		return nil, fmt.Errorf("%q is not an absolute url", raw)
+	}
+
+	return parsed, nil
+}
+
 func MapStructDurationCaster(targetType reflect.Type, value interface{}) (interface{}, error) {
 	if targetType != reflect.TypeOf(time.Duration(0)) {
 		return nil, nil
@@ -25,3 +60,56 @@ func MapStructTimeCaster(targetType reflect.Type, value interface{}) (interface{
 
 	return cast.ToTimeE(value)
 }
+
+// MapStructNumberCaster casts numbers into integer, float and json.Number targets, including named types like
+// type Port uint16, without a detour over float64. Values which don't fit into the target, like a fraction for an
+// integer or 2^63 for an int64, are rejected instead of being truncated. Values which aren't numbers or numeric
+// strings are left to the following casters.
+func MapStructNumberCaster(targetType reflect.Type, value interface{}) (interface{}, error) {
+	str, ok := numberString(value)
+
+	if !ok {
+		return nil, nil
+	}
+
+	if targetType == reflect.TypeOf(json.Number("")) {
+		return json.Number(str), nil
+	}
+
+	var casted interface{}
+	var err error
+
+	switch targetType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		casted, err = strconv.ParseInt(str, 0, targetType.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		casted, err = strconv.ParseUint(str, 0, targetType.Bits())
+	case reflect.Float32, reflect.Float64:
+		casted, err = strconv.ParseFloat(str, targetType.Bits())
+	default:
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not cast %s to %s: %w", str, targetType, err)
+	}
+
+	return reflect.ValueOf(casted).Convert(targetType).Interface(), nil
+}
+
+func numberString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), true
+	case string:
+		return strings.TrimSpace(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), true
+	}
+
+	return "", false
+}
@@ -1,9 +1,13 @@
 package mapx_test
 
 import (
+	"encoding/json"
 	"github.com/applike/gosoline/pkg/mapx"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
+	"net"
+	"net/url"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -692,3 +696,113 @@ func setupMapStructIO(t *testing.T, source interface{}) *mapx.MapXStruct {
 
 	return ms
 }
+
+func TestMapStructNumberCaster(t *testing.T) {
+	type port uint16
+
+	casted, err := mapx.MapStructNumberCaster(reflect.TypeOf(int64(0)), json.Number("9007199254740993"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), casted)
+
+	casted, err = mapx.MapStructNumberCaster(reflect.TypeOf(port(0)), "8080")
+	assert.NoError(t, err)
+	assert.Equal(t, port(8080), casted)
+
+	casted, err = mapx.MapStructNumberCaster(reflect.TypeOf(json.Number("")), 1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, json.Number("1.5"), casted)
+
+	casted, err = mapx.MapStructNumberCaster(reflect.TypeOf(""), 1)
+	assert.NoError(t, err)
+	assert.Nil(t, casted, "other targets should be left to the following casters")
+
+	_, err = mapx.MapStructNumberCaster(reflect.TypeOf(0), 1.5)
+	assert.Error(t, err, "fractions can't be cast to an int")
+
+	_, err = mapx.MapStructNumberCaster(reflect.TypeOf(uint8(0)), 256)
+	assert.Error(t, err, "256 doesn't fit into an uint8")
+}
+
+func TestMapStructIpParser(t *testing.T) {
+	ip, err := mapx.MapStructIpParser(" 10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("10.0.0.1"), ip)
+
+	ip, err = mapx.MapStructIpParser("::1")
+	assert.NoError(t, err)
+	assert.Equal(t, net.IPv6loopback, ip)
+
+	_, err = mapx.MapStructIpParser("localhost")
+	assert.EqualError(t, err, `"localhost" is not a valid ip address`)
+}
+
+func TestMapStructUrlParser(t *testing.T) {
+	parsed, err := mapx.MapStructUrlParser("https://example.com/path")
+	assert.NoError(t, err)
+	assert.Equal(t, &url.URL{Scheme: "https", Host: "example.com", Path: "/path"}, parsed)
+
+	_, err = mapx.MapStructUrlParser("example.com/path")
+	assert.EqualError(t, err, `"example.com/path" is not an absolute url`)
+
+	_, err = mapx.MapStructUrlParser("https://example.com/%zz")
+	assert.Error(t, err)
+}
+
+func TestMapStructTimeCaster(t *testing.T) {
+	casted, err := mapx.MapStructTimeCaster(reflect.TypeOf(time.Time{}), "2020-04-21")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.April, 21, 0, 0, 0, 0, time.UTC), casted)
+
+	casted, err = mapx.MapStructTimeCaster(reflect.TypeOf(""), "2020-04-21")
+	assert.NoError(t, err)
+	assert.Nil(t, casted)
+
+	_, err = mapx.MapStructTimeCaster(reflect.TypeOf(time.Time{}), "yesterday")
+	assert.Error(t, err)
+}
+
+func TestMapStructIO_Parsers(t *testing.T) {
+	type target struct {
+		Ip        net.IP   `cfg:"ip"`
+		DefaultIp net.IP   `cfg:"default_ip" default:"127.0.0.1"`
+		Url       *url.URL `cfg:"url"`
+	}
+
+	source := &target{}
+	ms, err := mapx.NewMapStruct(source, &mapx.MapXStructSettings{
+		FieldTag:   "cfg",
+		DefaultTag: "default",
+		Parsers: map[reflect.Type]mapx.MapStructParser{
+			reflect.TypeOf(net.IP{}):   mapx.MapStructIpParser,
+			reflect.TypeOf(&url.URL{}): mapx.MapStructUrlParser,
+		},
+	})
+	assert.NoError(t, err)
+
+	zero, defaults, err := ms.ReadZeroAndDefaultValues()
+	assert.NoError(t, err)
+
+	values := mapx.NewMapX()
+	values.Merge(".", zero)
+	values.Merge(".", defaults)
+	values.Set("ip", "10.0.0.1")
+	values.Set("url", "https://example.com")
+
+	err = ms.Write(values)
+	assert.NoError(t, err)
+
+	expected := &target{
+		Ip:        net.ParseIP("10.0.0.1"),
+		DefaultIp: net.ParseIP("127.0.0.1"),
+		Url:       &url.URL{Scheme: "https", Host: "example.com"},
+	}
+	assert.Equal(t, expected, source)
+
+	read, err := ms.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"ip":         "10.0.0.1",
+		"default_ip": "127.0.0.1",
+		"url":        "https://example.com",
+	}, read.Msi())
+}
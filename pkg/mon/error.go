@@ -1,6 +1,9 @@
 package mon
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 func WrapErrorAndLog(logger Logger, err error, msg string, args ...interface{}) error {
 	errMsg := fmt.Sprintf(msg, args...)
@@ -8,3 +11,22 @@ func WrapErrorAndLog(logger Logger, err error, msg string, args ...interface{})
 
 	return fmt.Errorf("%s: %w", errMsg, err)
 }
+
+// errorChain lists the type and message of the error and of every error wrapped by it, so entries can be grouped by
+// the type of the error instead of its message.
+func errorChain(err error) []interface{} {
+	chain := make([]interface{}, 0)
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		chain = append(chain, map[string]interface{}{
+			"type":    errorType(current),
+			"message": current.Error(),
+		})
+	}
+
+	return chain
+}
+
+func errorType(err error) string {
+	return fmt.Sprintf("%T", err)
+}
@@ -3,9 +3,20 @@ package mon
 import (
 	"fmt"
 	"github.com/fatih/color"
+	"io"
+	"os"
 	"strings"
 )
 
+type consoleColor int
+
+const (
+	// consoleColorDefault keeps the colors of formatterConsole, which depend on stdout being a terminal
+	consoleColorDefault consoleColor = iota
+	consoleColorOn
+	consoleColorOff
+)
+
 func formatterConsole(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error) {
 	fieldString := getFieldsAsString(data.Fields)
 	contextString := getFieldsAsString(data.ContextFields)
@@ -43,3 +54,79 @@ func getFieldsAsString(fields map[string]interface{}) string {
 
 	return strings.Join(fieldParts, ", ")
 }
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+var ansiLevelColors = map[string]string{
+	Trace: ansiDim,
+	Debug: ansiCyan,
+	Info:  ansiGreen,
+	Warn:  ansiYellow,
+	Error: ansiRed,
+}
+
+// formatterConsoleColor is used instead of formatterConsole by loggers with WithColor writing to a terminal.
+func formatterConsoleColor(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error) {
+	return formatConsoleAnsi(timestamp, level, msg, err, data, true), nil
+}
+
+// formatterConsolePlain is used instead of formatterConsole by loggers with WithColor not writing to a terminal.
+func formatterConsolePlain(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error) {
+	return formatConsoleAnsi(timestamp, level, msg, err, data, false), nil
+}
+
+func formatConsoleAnsi(timestamp string, level string, msg string, err error, data *Metadata, colored bool) []byte {
+	paint := func(code string, s string) string {
+		if !colored || code == "" || s == "" {
+			return s
+		}
+
+		return code + s + ansiReset
+	}
+
+	errStr := ""
+	if err != nil {
+		errStr = fmt.Sprintf("ERR: %s", err.Error())
+	}
+
+	output := fmt.Sprintf("%s %s %s %-50s %s %s %s",
+		paint(ansiDim, timestamp),
+		paint(ansiGreen, fmt.Sprintf("%-7s", data.Channel)),
+		paint(ansiLevelColors[level], fmt.Sprintf("%-7v", level)),
+		msg,
+		paint(ansiGreen, getFieldsAsString(data.ContextFields)),
+		paint(ansiBlue, getFieldsAsString(data.Fields)),
+		paint(ansiRed, errStr),
+	)
+
+	output = strings.TrimSpace(output)
+	serialized := []byte(output)
+
+	return append(serialized, '\n')
+}
+
+// isTerminal reports whether the writer is a file connected to a terminal. Any other writer, like a buffer or a
+// rotating file writer, is never a terminal.
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -29,6 +29,10 @@ func formatterGelf(timestamp string, level string, msg string, err error, data *
 	gelf["level_name"] = level
 	gelf["_pid"] = os.Getpid()
 
+	if host, ok := data.Tags["host"]; ok {
+		gelf["host"] = host
+	}
+
 	serialized, err := json.Marshal(gelf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal fields to JSON, %v", err)
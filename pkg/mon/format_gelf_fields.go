@@ -33,6 +33,10 @@ func formatterGelfFields(timestamp string, level string, msg string, err error,
 	gelf["level_name"] = level
 	gelf["_pid"] = os.Getpid()
 
+	if host, ok := data.Tags["host"]; ok {
+		gelf["host"] = host
+	}
+
 	serialized, err := json.Marshal(gelf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal log message to JSON, %v", err)
@@ -0,0 +1,135 @@
+package mon
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon/logpb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"reflect"
+	"time"
+)
+
+// integers with a bigger absolute value can't be represented exactly by the double of a protobuf value
+const protobufMaxExactInt = 1 << 53
+
+// formatterProtobuf writes every entry as logpb.LogEntry prefixed with its size as varint, so a reader can split the
+// stream into entries like parseDelimitedFrom of the java implementation does. Integers which don't fit into a double without
+// losing precision are written as strings.
+func formatterProtobuf(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error) {
+	entry := &logpb.LogEntry{
+		Timestamp: timestamp,
+		Level:     int32(levels[level]),
+		LevelName: level,
+		Channel:   data.Channel,
+		Message:   msg,
+		Fields:    protobufStruct(data.Fields),
+		Context:   protobufStruct(data.ContextFields),
+	}
+
+	if err != nil {
+		entry.Err = err.Error()
+	}
+
+	serialized, marshalErr := proto.Marshal(entry)
+
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to marshal log entry to protobuf, %v", marshalErr)
+	}
+
+	frame := make([]byte, 0, protowire.SizeVarint(uint64(len(serialized)))+len(serialized))
+	frame = protowire.AppendVarint(frame, uint64(len(serialized)))
+
+	return append(frame, serialized...), nil
+}
+
+func protobufStruct(fields map[string]interface{}) *structpb.Struct {
+	result := &structpb.Struct{
+		Fields: make(map[string]*structpb.Value, len(fields)),
+	}
+
+	for key, value := range fields {
+		result.Fields[key] = protobufValue(value)
+	}
+
+	return result
+}
+
+// protobufValue converts the values returned by prepareForLog, which are maps, slices and scalars. Anything else is
+// written as string.
+func protobufValue(v interface{}) *structpb.Value {
+	switch t := v.(type) {
+	case nil:
+		return &structpb.Value{Kind: &structpb.Value_NullValue{}}
+	case bool:
+		return &structpb.Value{Kind: &structpb.Value_BoolValue{BoolValue: t}}
+	case string:
+		return protobufString(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return protobufInt(i)
+		}
+
+		f, err := t.Float64()
+
+		if err != nil {
+			return protobufString(t.String())
+		}
+
+		return protobufNumber(f)
+	case time.Time:
+		return protobufString(t.Format(time.RFC3339Nano))
+	case RawJson:
+		return protobufString(t.String())
+	case map[string]interface{}:
+		return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: protobufStruct(t)}}
+	case []interface{}:
+		list := &structpb.ListValue{
+			Values: make([]*structpb.Value, len(t)),
+		}
+
+		for i, elem := range t {
+			list.Values[i] = protobufValue(elem)
+		}
+
+		return &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: list}}
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return protobufInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+
+		if u > protobufMaxExactInt {
+			return protobufString(fmt.Sprint(u))
+		}
+
+		return protobufNumber(float64(u))
+	case reflect.Float32, reflect.Float64:
+		return protobufNumber(rv.Float())
+	case reflect.String:
+		return protobufString(rv.String())
+	}
+
+	return protobufString(fmt.Sprint(v))
+}
+
+func protobufInt(i int64) *structpb.Value {
+	if i > protobufMaxExactInt || i < -protobufMaxExactInt {
+		return protobufString(fmt.Sprint(i))
+	}
+
+	return protobufNumber(float64(i))
+}
+
+func protobufNumber(f float64) *structpb.Value {
+	return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: f}}
+}
+
+func protobufString(s string) *structpb.Value {
+	return &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: s}}
+}
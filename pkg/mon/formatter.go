@@ -0,0 +1,43 @@
+package mon
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Formatter serializes a single log entry. The returned bytes are written as they are, so they should
+// contain a trailing newline if the format is line based.
+type Formatter func(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error)
+
+var formatterLck = &sync.RWMutex{}
+var formatters = map[string]Formatter{}
+
+func init() {
+	RegisterFormatter(FormatConsole, formatterConsole)
+	RegisterFormatter(FormatGelf, formatterGelf)
+	RegisterFormatter(FormatGelfFields, formatterGelfFields)
+	RegisterFormatter(FormatJson, formatterJson)
+	RegisterFormatter(FormatProtobuf, formatterProtobuf)
+}
+
+// RegisterFormatter makes a formatter available for WithFormat under the given name.
+// Registering a name twice replaces the former formatter.
+func RegisterFormatter(name string, formatter Formatter) {
+	if formatter == nil {
+		panic(fmt.Errorf("can not register nil formatter %s", name))
+	}
+
+	formatterLck.Lock()
+	defer formatterLck.Unlock()
+
+	formatters[name] = formatter
+}
+
+func getFormatter(name string) (Formatter, bool) {
+	formatterLck.RLock()
+	defer formatterLck.RUnlock()
+
+	formatter, ok := formatters[name]
+
+	return formatter, ok
+}
@@ -0,0 +1,24 @@
+package mon
+
+import "sync"
+
+var globalFieldsLck = &sync.RWMutex{}
+var globalFields = Fields{}
+
+// SetGlobalFields sets fields like the service name or version which every logger created afterwards by NewLogger or
+// NewLoggerWithInterfaces starts with, e.g. also the logger of the default error handler of the cli package. Loggers
+// created before keep their fields. It is safe for concurrent use, but meant to be called once on startup before any
+// logger is created. Calling it again replaces the former global fields.
+func SetGlobalFields(fields Fields) {
+	globalFieldsLck.Lock()
+	defer globalFieldsLck.Unlock()
+
+	globalFields = mergeFields(Fields{}, fields)
+}
+
+func getGlobalFields() Fields {
+	globalFieldsLck.RLock()
+	defer globalFieldsLck.RUnlock()
+
+	return mergeFields(Fields{}, globalFields)
+}
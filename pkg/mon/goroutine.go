@@ -0,0 +1,33 @@
+package mon
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+var goroutinePrefix = []byte("goroutine ")
+
+// GetGoroutineId parses the id of the current goroutine from the header of its stack trace.
+// Go doesn't expose the id on purpose, so this is a best effort debug aid: the id is only
+// meaningful to correlate log lines within a single process and ids get reused. Returns 0
+// if the id can't be determined.
+func GetGoroutineId() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	// the stack starts with "goroutine 123 [running]:"
+	buf = bytes.TrimPrefix(buf, goroutinePrefix)
+
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+
+	id, err := strconv.ParseUint(string(buf), 10, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
@@ -1,14 +1,20 @@
 package mon
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"github.com/jonboulle/clockwork"
 	"io"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -37,8 +43,26 @@ const (
 	FormatGelf       = "gelf"
 	FormatGelfFields = "gelf_fields"
 	FormatJson       = "json"
+	FormatProtobuf   = "protobuf"
 )
 
+// ReservedKeyPolicy decides what happens to fields colliding with the keys of the entry itself, see WithReservedKeyPolicy
+type ReservedKeyPolicy string
+
+const (
+	// ReservedKeyPolicyPrefix renames colliding fields with the prefix "fields."
+	ReservedKeyPolicyPrefix ReservedKeyPolicy = "prefix"
+	// ReservedKeyPolicyIgnore drops colliding fields silently
+	ReservedKeyPolicyIgnore ReservedKeyPolicy = "ignore"
+	// ReservedKeyPolicyError drops colliding fields and logs an error about them
+	ReservedKeyPolicyError ReservedKeyPolicy = "error"
+)
+
+const reservedKeyPrefix = "fields."
+
+// the keys the formatters use for the entry itself, the gelf formatter writes the fields next to them
+var reservedKeys = []string{"channel", "context", "err", "fields", "level", "level_name", "message", "pid", "timestamp"}
+
 type Tags map[string]interface{}
 type ConfigValues map[string]interface{}
 type Fields map[string]interface{}
@@ -52,18 +76,13 @@ type Metadata struct {
 	Tags          Tags
 }
 
-type formatter func(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error)
-
-var formatters = map[string]formatter{
-	FormatConsole:    formatterConsole,
-	FormatGelf:       formatterGelf,
-	FormatGelfFields: formatterGelfFields,
-	FormatJson:       formatterJson,
-}
+var unknownFormatWarning = &sync.Once{}
 
 type GosoLog interface {
 	Logger
+	Flush() error
 	Option(options ...LoggerOption) error
+	Stats() LoggerStats
 }
 
 //go:generate mockery -name Logger
@@ -78,23 +97,194 @@ type Logger interface {
 	Warnf(format string, args ...interface{})
 	Errorf(err error, format string, args ...interface{})
 
+	DebugTemplate(msg string, fields Fields)
+	InfoTemplate(msg string, fields Fields)
+	WarnTemplate(msg string, fields Fields)
+
 	WithChannel(channel string) Logger
 	WithContext(ctx context.Context) Logger
 	WithFields(fields Fields) Logger
 }
 
+type loggerOutput struct {
+	lck      sync.Mutex
+	writer   io.Writer
+	terminal bool
+	buffered *entryBuffer
+	stop     chan struct{}
+}
+
+// entryBuffer keeps whole entries, so every entry is still passed to the writer with its own Write. Writers like the
+// gelf udp writer or the rotating file writer rely on this and would otherwise get half or several entries at once.
+type entryBuffer struct {
+	size    int
+	used    int
+	entries [][]byte
+}
+
+func newLoggerOutput(writer io.Writer) *loggerOutput {
+	return &loggerOutput{
+		writer:   writer,
+		terminal: isTerminal(writer),
+	}
+}
+
+func (o *loggerOutput) swap(writer io.Writer) {
+	o.lck.Lock()
+	defer o.lck.Unlock()
+
+	if o.buffered != nil {
+		_ = o.flushBuffer()
+	}
+
+	o.writer = writer
+	o.terminal = isTerminal(writer)
+}
+
+// buffer collects the entries in a buffer of the given size, which is written once it is full, every flushInterval
+// and by flush. A flushInterval of 0 disables the periodic flush.
+func (o *loggerOutput) buffer(size int, flushInterval time.Duration) {
+	o.lck.Lock()
+	defer o.lck.Unlock()
+
+	if o.buffered != nil {
+		_ = o.flushBuffer()
+	}
+
+	if o.stop != nil {
+		close(o.stop)
+		o.stop = nil
+	}
+
+	o.buffered = &entryBuffer{
+		size:    size,
+		entries: make([][]byte, 0),
+	}
+
+	if flushInterval <= 0 {
+		return
+	}
+
+	o.stop = make(chan struct{})
+	go o.flushPeriodically(flushInterval, o.stop)
+}
+
+func (o *loggerOutput) flushPeriodically(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := o.flush(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to flush the log, %v\n", err)
+			}
+		}
+	}
+}
+
+func (o *loggerOutput) flush() error {
+	o.lck.Lock()
+	defer o.lck.Unlock()
+
+	return o.flushBuffer()
+}
+
+// flushBuffer writes the buffered entries one by one. If a write fails, the entries not written yet are kept for the
+// next flush. The lock has to be held by the caller.
+func (o *loggerOutput) flushBuffer() error {
+	if o.buffered == nil {
+		return nil
+	}
+
+	for len(o.buffered.entries) > 0 {
+		entry := o.buffered.entries[0]
+
+		if _, err := o.writer.Write(entry); err != nil {
+			return err
+		}
+
+		o.buffered.entries = o.buffered.entries[1:]
+		o.buffered.used -= len(entry)
+	}
+
+	o.buffered.entries = o.buffered.entries[:0]
+
+	return nil
+}
+
+func (o *loggerOutput) isTerminal() bool {
+	o.lck.Lock()
+	defer o.lck.Unlock()
+
+	return o.terminal
+}
+
+// write adds the entry to the buffer, if there is one, and flushes the buffer if flush is set. The buffer is flushed
+// before an entry which doesn't fit anymore and an entry bigger than the whole buffer is written right away.
+func (o *loggerOutput) write(buffer []byte, flush bool) error {
+	o.lck.Lock()
+	defer o.lck.Unlock()
+
+	if o.buffered == nil {
+		_, err := o.writer.Write(buffer)
+
+		return err
+	}
+
+	if o.buffered.used+len(buffer) > o.buffered.size {
+		if err := o.flushBuffer(); err != nil {
+			return err
+		}
+	}
+
+	if len(buffer) > o.buffered.size {
+		_, err := o.writer.Write(buffer)
+
+		return err
+	}
+
+	o.buffered.entries = append(o.buffered.entries, buffer)
+	o.buffered.used += len(buffer)
+
+	if !flush {
+		return nil
+	}
+
+	return o.flushBuffer()
+}
+
 type logger struct {
 	clock       clockwork.Clock
-	output      io.Writer
-	outputLck   *sync.Mutex
+	random      *random
+	output      *loggerOutput
+	stats       *loggerStats
 	ctxResolver []ContextFieldsResolver
 	hooks       []LoggerHook
 
-	level           int
-	format          string
-	timestampFormat string
-
-	data Metadata
+	level             int
+	channelLevels     map[string]int
+	format            string
+	levelFormats      map[string]string
+	timestampFormat   string
+	timestampLocation *time.Location
+	goroutineId       bool
+	errorChain        bool
+	channelExplicit   bool
+	redactedKeys      map[string]bool
+	consoleColor      consoleColor
+	logBuffer         *logBuffer
+	omitEmpty         bool
+	dedup             *logDedup
+
+	reservedKeyPolicy ReservedKeyPolicy
+	flattenSeparator  string
+	limits            fieldLimits
+
+	defaultFields map[string]interface{}
+	data          Metadata
 }
 
 func NewLogger() *logger {
@@ -104,17 +294,24 @@ func NewLogger() *logger {
 func NewLoggerWithInterfaces(clock clockwork.Clock, out io.Writer) *logger {
 	logger := &logger{
 		clock:           clock,
-		output:          out,
-		outputLck:       &sync.Mutex{},
+		random:          newTimeSeededRandom(),
+		output:          newLoggerOutput(out),
+		stats:           newLoggerStats(),
 		ctxResolver:     make([]ContextFieldsResolver, 0),
 		hooks:           make([]LoggerHook, 0),
 		level:           levelPriority(Info),
+		channelLevels:   make(map[string]int),
 		format:          FormatConsole,
+		levelFormats:    make(map[string]string),
 		timestampFormat: "15:04:05.000",
+
+		reservedKeyPolicy: ReservedKeyPolicyPrefix,
+		limits:            defaultFieldLimits,
+
 		data: Metadata{
 			Channel:       ChannelDefault,
 			ContextFields: make(Fields),
-			Fields:        make(Fields),
+			Fields:        getGlobalFields(),
 			Tags:          make(Tags),
 		},
 	}
@@ -122,20 +319,50 @@ func NewLoggerWithInterfaces(clock clockwork.Clock, out io.Writer) *logger {
 	return logger
 }
 
+// copy creates a logger for the With* methods. The output is shared with the copy, so swapping
+// the writer of a logger with WithWriter also redirects all loggers derived from it (and vice versa).
 func (l *logger) copy() *logger {
 	return &logger{
-		clock:           l.clock,
-		outputLck:       l.outputLck,
-		output:          l.output,
-		ctxResolver:     l.ctxResolver,
-		hooks:           l.hooks,
-		level:           l.level,
-		format:          l.format,
-		timestampFormat: l.timestampFormat,
-		data:            l.data,
+		clock:             l.clock,
+		random:            l.random,
+		output:            l.output,
+		stats:             l.stats,
+		ctxResolver:       l.ctxResolver,
+		hooks:             l.hooks,
+		level:             l.level,
+		channelLevels:     l.channelLevels,
+		format:            l.format,
+		levelFormats:      l.levelFormats,
+		timestampFormat:   l.timestampFormat,
+		timestampLocation: l.timestampLocation,
+		goroutineId:       l.goroutineId,
+		errorChain:        l.errorChain,
+		channelExplicit:   l.channelExplicit,
+		redactedKeys:      l.redactedKeys,
+		consoleColor:      l.consoleColor,
+		logBuffer:         l.logBuffer,
+		omitEmpty:         l.omitEmpty,
+		dedup:             l.dedup,
+
+		reservedKeyPolicy: l.reservedKeyPolicy,
+		flattenSeparator:  l.flattenSeparator,
+		limits:            l.limits,
+
+		defaultFields: l.defaultFields,
+		data:          l.data,
 	}
 }
 
+// Flush writes the entries buffered by WithBufferedOutput and the summary of an entry repeated at the moment if
+// WithDeduplication is used. It should be called before the process exits.
+func (l *logger) Flush() error {
+	if l.dedup != nil {
+		l.dedup.flush()
+	}
+
+	return l.output.flush()
+}
+
 func (l *logger) Option(options ...LoggerOption) error {
 	for _, opt := range options {
 		if err := opt(l); err != nil {
@@ -146,36 +373,81 @@ func (l *logger) Option(options ...LoggerOption) error {
 	return nil
 }
 
+// Stats returns the number of log calls per level of this logger and all loggers derived from it.
+func (l *logger) Stats() LoggerStats {
+	return l.stats.snapshot()
+}
+
 func (l *logger) WithChannel(channel string) Logger {
 	cpy := l.copy()
 	cpy.data.Channel = channel
+	cpy.channelExplicit = true
 
 	return cpy
 }
 
+// WithContext resolves the context fields, like the trace id, once and keeps them for all entries of the returned
+// logger. Binding a logger to the context it is already bound to returns it as is, so calling WithContext for every
+// entry of a request doesn't resolve the fields again. A different context resolves them anew.
 func (l *logger) WithContext(ctx context.Context) Logger {
-	if ctx == nil {
+	if ctx == nil || l.isBoundTo(ctx) {
 		return l
 	}
 
 	cpy := l.copy()
 	cpy.data.Context = ctx
 
+	if channel, ok := ChannelFromContext(ctx); ok && !l.channelExplicit {
+		cpy.data.Channel = channel
+	}
+
+	cpy.logBuffer = logBufferFromContext(ctx)
+
 	for _, r := range l.ctxResolver {
 		newContextFields := r(ctx)
-		cpy.data.ContextFields = mergeMapStringInterface(cpy.data.ContextFields, newContextFields)
+		cpy.data.ContextFields = l.limits.mergeMapStringInterface(cpy.data.ContextFields, newContextFields)
 	}
 
 	return cpy
 }
 
+func (l *logger) isBoundTo(ctx context.Context) bool {
+	if l.data.Context == nil {
+		return false
+	}
+
+	// comparing contexts of a non comparable type would panic
+	if !reflect.TypeOf(ctx).Comparable() || !reflect.TypeOf(l.data.Context).Comparable() {
+		return false
+	}
+
+	return l.data.Context == ctx
+}
+
 func (l *logger) WithFields(fields Fields) Logger {
 	cpy := l.copy()
-	cpy.data.Fields = mergeMapStringInterface(l.data.Fields, fields)
+	cpy.data.Fields = l.limits.mergeFields(l.data.Fields, fields)
 
 	return cpy
 }
 
+// InfoTemplate logs a constant message and keeps the variable parts in the fields instead of formatting them into
+// the message. Tools grouping entries by their message (like log based metrics or error trackers) then see a
+// single message instead of one per distinct set of arguments.
+func (l *logger) InfoTemplate(msg string, fields Fields) {
+	l.log(Info, msg, nil, fields)
+}
+
+// DebugTemplate is the same as InfoTemplate for the debug level
+func (l *logger) DebugTemplate(msg string, fields Fields) {
+	l.log(Debug, msg, nil, fields)
+}
+
+// WarnTemplate is the same as InfoTemplate for the warn level
+func (l *logger) WarnTemplate(msg string, fields Fields) {
+	l.log(Warn, msg, nil, fields)
+}
+
 func (l *logger) Info(args ...interface{}) {
 	l.log(Info, fmt.Sprint(args...), nil, Fields{})
 }
@@ -185,7 +457,9 @@ func (l *logger) Infof(msg string, args ...interface{}) {
 }
 
 func (l *logger) Debug(args ...interface{}) {
-	if l.level > levels[Debug] {
+	if l.minLevel() > levels[Debug] {
+		// skips formatting the message, but the call still counts for the stats
+		l.stats.incLogged(Debug)
 		return
 	}
 
@@ -193,7 +467,9 @@ func (l *logger) Debug(args ...interface{}) {
 }
 
 func (l *logger) Debugf(msg string, args ...interface{}) {
-	if l.level > levels[Debug] {
+	if l.minLevel() > levels[Debug] {
+		// skips formatting the message, but the call still counts for the stats
+		l.stats.incLogged(Debug)
 		return
 	}
 
@@ -217,20 +493,54 @@ func (l *logger) Errorf(err error, msg string, args ...interface{}) {
 }
 
 func (l *logger) logError(level string, err error, msg string) {
-	l.log(level, msg, err, Fields{
+	fields := Fields{
 		"stacktrace": GetStackTrace(1),
-	})
+	}
+
+	if l.errorChain && err != nil {
+		fields["error_type"] = errorType(err)
+		fields["error_chain"] = errorChain(err)
+	}
+
+	l.log(level, msg, err, fields)
 }
 
 func (l *logger) log(level string, msg string, logErr error, fields Fields) {
 	levelNo := levels[level]
+	l.stats.incLogged(level)
+
+	if levelNo < l.minLevel() {
+		return
+	}
+
+	l.stats.incEmitted(level)
 
-	if levelNo < l.level {
+	if l.dedup != nil && !l.dedup.admit(l, level, msg, logErr, fields) {
 		return
 	}
 
+	l.emit(level, msg, logErr, fields)
+}
+
+func (l *logger) emit(level string, msg string, logErr error, fields Fields) {
 	cpyData := l.data
-	cpyData.Fields = mergeMapStringInterface(cpyData.Fields, fields)
+	cpyData.Fields = l.entryFields(fields)
+	cpyData.ContextFields = l.resolveElapsedTime(cpyData.ContextFields)
+
+	if l.goroutineId {
+		cpyData.Fields["goroutine"] = GetGoroutineId()
+	}
+
+	l.redactMetadata(&cpyData)
+
+	if l.flattenSeparator != "" {
+		cpyData.Fields = flattenFields(cpyData.Fields, l.flattenSeparator)
+		cpyData.ContextFields = flattenFields(cpyData.ContextFields, l.flattenSeparator)
+	}
+
+	if err := l.applyReservedKeyPolicy(cpyData.Fields); err != nil {
+		l.err(err)
+	}
 
 	for _, h := range l.hooks {
 		if err := h.Fire(level, msg, logErr, &cpyData); err != nil {
@@ -238,63 +548,322 @@ func (l *logger) log(level string, msg string, logErr error, fields Fields) {
 		}
 	}
 
-	timestamp := l.clock.Now().Format(l.timestampFormat)
-	buffer, err := formatters[l.format](timestamp, level, msg, logErr, &cpyData)
+	if l.omitEmpty {
+		cpyData.Fields = omitEmptyFields(cpyData.Fields)
+		cpyData.ContextFields = omitEmptyFields(cpyData.ContextFields)
+	}
+
+	timestamp := l.formatTimestamp()
+	buffer, err := l.formatter(level)(timestamp, level, msg, logErr, &cpyData)
 
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
 	}
 
-	l.write(buffer)
+	if l.logBuffer != nil && l.logBuffer.add(l.output, level, buffer) {
+		return
+	}
+
+	l.write(level, buffer)
+}
+
+// resolveElapsedTime replaces the start time added by ContextStartTimeFieldsResolver with the milliseconds elapsed
+// since then. The context fields are shared with the other entries of the logger, so they are copied before.
+func (l *logger) resolveElapsedTime(contextFields Fields) Fields {
+	var resolved Fields
+
+	for key, value := range contextFields {
+		start, ok := value.(elapsedSince)
+
+		if !ok {
+			continue
+		}
+
+		if resolved == nil {
+			resolved = l.limits.mergeMapStringInterface(contextFields, nil)
+		}
+
+		resolved[key] = l.clock.Now().Sub(time.Time(start)).Milliseconds()
+	}
+
+	if resolved == nil {
+		return contextFields
+	}
+
+	return resolved
+}
+
+// applyReservedKeyPolicy handles fields colliding with the keys formatters use for the entry itself. The fields are
+// a copy owned by the current log call, so they are changed in place.
+func (l *logger) applyReservedKeyPolicy(fields Fields) error {
+	var collisions []string
+
+	for _, key := range reservedKeys {
+		value, ok := fields[key]
+
+		if !ok {
+			continue
+		}
+
+		delete(fields, key)
+
+		switch l.reservedKeyPolicy {
+		case ReservedKeyPolicyIgnore:
+		case ReservedKeyPolicyError:
+			collisions = append(collisions, key)
+		default:
+			fields[reservedKeyPrefix+key] = value
+		}
+	}
+
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("fields with reserved keys have been dropped: %s", strings.Join(collisions, ", "))
+}
+
+// flattenFields turns nested maps and slices of prepared fields into top level fields, e.g. {user:{id:5}} into
+// {user.id:5} and {items:[a]} into {items.0:a}. Empty maps and slices are kept, so flattening twice changes nothing.
+func flattenFields(fields map[string]interface{}, separator string) map[string]interface{} {
+	flat := make(map[string]interface{}, len(fields))
+
+	for key, value := range fields {
+		flattenValue(flat, key, value, separator)
+	}
+
+	return flat
+}
+
+func flattenValue(flat map[string]interface{}, key string, value interface{}, separator string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			flat[key] = v
+			return
+		}
+
+		for elemKey, elem := range v {
+			flattenValue(flat, key+separator+elemKey, elem, separator)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			flat[key] = v
+			return
+		}
+
+		for i, elem := range v {
+			flattenValue(flat, key+separator+strconv.Itoa(i), elem, separator)
+		}
+	default:
+		flat[key] = value
+	}
+}
+
+// minLevel returns the level configured for the channel of the logger or the global level if there is none.
+func (l *logger) minLevel() int {
+	if level, ok := l.channelLevels[l.data.Channel]; ok {
+		return level
+	}
+
+	return l.level
+}
+
+func (l *logger) formatTimestamp() string {
+	return l.formatTime(l.clock.Now())
+}
+
+func (l *logger) formatTime(t time.Time) string {
+	if l.timestampLocation != nil {
+		t = t.In(l.timestampLocation)
+	}
+
+	return t.Format(l.timestampFormat)
 }
 
 func (l *logger) err(err error) {
-	timestamp := l.clock.Now().Format(l.timestampFormat)
-	buffer, err := formatters[l.format](timestamp, Error, err.Error(), err, &l.data)
+	timestamp := l.formatTimestamp()
+	cpyData := l.data
+	cpyData.Fields = l.entryFields(nil)
+	l.redactMetadata(&cpyData)
+
+	buffer, err := l.formatter(Error)(timestamp, Error, err.Error(), err, &cpyData)
 
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
 	}
 
-	l.write(buffer)
+	l.write(Error, buffer)
 }
 
-func (l *logger) write(buffer []byte) {
-	l.outputLck.Lock()
-	defer l.outputLck.Unlock()
+// formatter returns the formatter configured for the level or the default one. WithFormat only accepts registered
+// formats, but if an unknown one slips through anyway we rather fall back to the console format than panic on every
+// log call.
+func (l *logger) formatter(level string) Formatter {
+	format := l.format
+
+	if levelFormat, ok := l.levelFormats[level]; ok {
+		format = levelFormat
+	}
+
+	if format == FormatConsole && l.consoleColor != consoleColorDefault {
+		if l.consoleColor == consoleColorOn && l.output.isTerminal() {
+			return formatterConsoleColor
+		}
+
+		return formatterConsolePlain
+	}
+
+	if formatter, ok := getFormatter(format); ok {
+		return formatter
+	}
+
+	unknownFormatWarning.Do(func() {
+		_, _ = fmt.Fprintf(os.Stderr, "unknown logger format %s, falling back to %s\n", format, FormatConsole)
+	})
+
+	return formatterConsole
+}
 
-	_, err := l.output.Write(buffer)
+// write flushes a buffered output right away for errors, so they are not lost if the process crashes afterwards.
+func (l *logger) write(level string, buffer []byte) {
+	err := l.output.write(buffer, levels[level] >= levels[Error])
 
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
 	}
 }
 
+// entryFields merges the fields written with an entry: default fields are overridden by tags, tags by the fields of
+// the logger and those by the fields of the entry itself.
+func (l *logger) entryFields(fields Fields) map[string]interface{} {
+	if len(l.defaultFields) == 0 && len(l.data.Tags) == 0 {
+		return l.limits.mergeMapStringInterface(l.data.Fields, fields)
+	}
+
+	layered := l.limits.mergeFields(l.limits.mergeFields(l.defaultFields, l.data.Tags), l.data.Fields)
+
+	return l.limits.mergeMapStringInterface(layered, fields)
+}
+
+// fieldLimits bound the size of the field values prepared for the log. Every logger has its own, see
+// WithMaxByteStringLength and WithMaxSliceLength. Values prepared without a logger, e.g. by the hooks, use the
+// default ones.
+type fieldLimits struct {
+	maxByteStringLength int
+	maxSliceLength      int
+}
+
+var defaultFieldLimits = fieldLimits{
+	maxByteStringLength: 256,
+}
+
 func mergeMapStringInterface(receiver map[string]interface{}, input map[string]interface{}) map[string]interface{} {
+	return defaultFieldLimits.mergeMapStringInterface(receiver, input)
+}
+
+func mergeFields(receiver map[string]interface{}, input map[string]interface{}) map[string]interface{} {
+	return defaultFieldLimits.mergeFields(receiver, input)
+}
+
+func (f fieldLimits) mergeMapStringInterface(receiver map[string]interface{}, input map[string]interface{}) map[string]interface{} {
 	newMap := make(map[string]interface{}, len(receiver)+len(input))
 
 	for k, v := range receiver {
-		newMap[k] = prepareForLog(v)
+		newMap[k] = f.prepareForLog(v)
 	}
 
 	for k, v := range input {
-		newMap[k] = prepareForLog(v)
+		newMap[k] = f.prepareForLog(v)
 	}
 
 	return newMap
 }
 
-func prepareForLog(v interface{}) interface{} {
+// mergeFields works like mergeMapStringInterface, but keeps lazy field values unevaluated. They are only
+// evaluated by prepareForLog once an entry is actually written.
+func (f fieldLimits) mergeFields(receiver map[string]interface{}, input map[string]interface{}) map[string]interface{} {
+	newMap := make(map[string]interface{}, len(receiver)+len(input))
+
+	for _, m := range []map[string]interface{}{receiver, input} {
+		for k, v := range m {
+			if lazy, ok := v.(func() interface{}); ok {
+				newMap[k] = lazy
+				continue
+			}
+
+			newMap[k] = f.prepareForLog(v)
+		}
+	}
+
+	return newMap
+}
+
+// sliceTruncation is the last element of a slice shortened by prepareForLog. It is a type of its own, so an already
+// shortened slice isn't shortened again when its fields are prepared a second time.
+type sliceTruncation string
+
+func (f fieldLimits) prepareForLog(v interface{}) interface{} {
 	switch t := v.(type) {
+	case func() interface{}:
+		// lazy field values are only computed if the entry gets written
+		return f.prepareForLog(t())
 	case error:
 		// Otherwise errors are ignored by `encoding/json`
 		return t.Error()
-	case time.Time:
+	case time.Time, json.Number, elapsedSince, RawJson:
 		return v
+	case []byte:
+		// otherwise every byte is logged as a separate element of an array
+		if len(t) <= f.maxByteStringLength && utf8.Valid(t) {
+			return string(t)
+		}
+
+		return base64.StdEncoding.EncodeToString(t)
+	case json.Marshaler, fmt.Stringer:
+		// types knowing how to represent themselves should not be taken apart by the reflection below
+		if prepared, ok := prepareMarshalerForLog(v); ok {
+			return prepared
+		}
+
+		return f.prepareStructuredForLog(v)
+	default:
+		return f.prepareStructuredForLog(v)
+	}
+}
+
+func prepareMarshalerForLog(v interface{}) (interface{}, bool) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, true
+	}
+
+	if marshaler, ok := v.(json.Marshaler); ok {
+		if encoded, err := marshaler.MarshalJSON(); err == nil {
+			var decoded interface{}
+
+			// numbers are kept as json.Number, as a float64 can't represent big ids exactly
+			decoder := json.NewDecoder(bytes.NewReader(encoded))
+			decoder.UseNumber()
+
+			if err := decoder.Decode(&decoded); err == nil {
+				return decoded, true
+			}
+		}
+	}
+
+	if stringer, ok := v.(fmt.Stringer); ok {
+		return stringer.String(), true
+	}
+
+	return nil, false
+}
+
+func (f fieldLimits) prepareStructuredForLog(v interface{}) interface{} {
+	switch t := v.(type) {
 	case map[string]interface{}:
 		// perform a deep copy of any maps contained in this map element
 		// to ensure we own the object completely
-		return mergeMapStringInterface(t, nil)
+		return f.mergeMapStringInterface(t, nil)
 
 	default:
 		// same as before, but handle the case of the map mapping to something
@@ -309,7 +878,7 @@ func prepareForLog(v interface{}) interface{} {
 			for iter.Next() {
 				keyValue := iter.Key()
 				elemValue := iter.Value()
-				newMap[fmt.Sprint(keyValue.Interface())] = prepareForLog(elemValue.Interface())
+				newMap[fmt.Sprint(keyValue.Interface())] = f.prepareForLog(elemValue.Interface())
 			}
 
 			return newMap
@@ -319,7 +888,7 @@ func prepareForLog(v interface{}) interface{} {
 				return nil
 			}
 
-			return prepareForLog(rv.Elem().Interface())
+			return f.prepareForLog(rv.Elem().Interface())
 
 		case reflect.Struct:
 			rvt := rv.Type()
@@ -330,7 +899,7 @@ func prepareForLog(v interface{}) interface{} {
 				if !field.CanInterface() {
 					continue
 				}
-				newMap[rvt.Field(i).Name] = prepareForLog(field.Interface())
+				newMap[rvt.Field(i).Name] = f.prepareForLog(field.Interface())
 			}
 
 			return newMap
@@ -340,16 +909,38 @@ func prepareForLog(v interface{}) interface{} {
 				return nil
 			}
 
-			newArray := make([]interface{}, rv.Len())
+			length := rv.Len()
+
+			if isTruncatedSlice(rv) || f.maxSliceLength <= 0 || length <= f.maxSliceLength {
+				newArray := make([]interface{}, length)
+
+				for i := range newArray {
+					newArray[i] = f.prepareForLog(rv.Index(i).Interface())
+				}
+
+				return newArray
+			}
+
+			newArray := make([]interface{}, f.maxSliceLength, f.maxSliceLength+1)
 
 			for i := range newArray {
-				newArray[i] = prepareForLog(rv.Index(i).Interface())
+				newArray[i] = f.prepareForLog(rv.Index(i).Interface())
 			}
 
-			return newArray
+			return append(newArray, sliceTruncation(fmt.Sprintf("…(%d more)", length-f.maxSliceLength)))
 
 		default:
 			return v
 		}
 	}
 }
+
+func isTruncatedSlice(rv reflect.Value) bool {
+	if rv.Len() == 0 {
+		return false
+	}
+
+	_, ok := rv.Index(rv.Len() - 1).Interface().(sliceTruncation)
+
+	return ok
+}
@@ -4,7 +4,11 @@ import "context"
 
 type key int
 
-const contextFieldsKey key = 0
+const (
+	contextFieldsKey key = iota
+	contextLoggerKey
+	contextChannelKey
+)
 
 type ContextFieldsResolver func(ctx context.Context) map[string]interface{}
 
@@ -47,3 +51,43 @@ func ContextLoggerFieldsResolver(ctx context.Context) map[string]interface{} {
 
 	return contextFields
 }
+
+// ContextWithLogger returns a new Context carrying the logger. Store a logger which is already bound to the
+// context with WithContext, so the context fields don't have to be resolved again by every user of the logger.
+func ContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextLoggerKey, logger)
+}
+
+// LoggerFromContext returns the logger stored with ContextWithLogger or a noop logger if there is none. If the context
+// carries a channel set by ContextWithChannel, the logger is bound to the context to pick it up.
+func LoggerFromContext(ctx context.Context) Logger {
+	if ctx == nil {
+		return NewNoopLogger()
+	}
+
+	logger, ok := ctx.Value(contextLoggerKey).(Logger)
+
+	if !ok {
+		return NewNoopLogger()
+	}
+
+	if _, ok := ChannelFromContext(ctx); ok {
+		return logger.WithContext(ctx)
+	}
+
+	return logger
+}
+
+// ContextWithChannel returns a new Context carrying the channel. Loggers bound to the context with WithContext write
+// to this channel unless it was set explicitly with WithChannel, so the channel can be set once at the boundary of a
+// request and is inherited by all loggers handling it.
+func ContextWithChannel(ctx context.Context, channel string) context.Context {
+	return context.WithValue(ctx, contextChannelKey, channel)
+}
+
+// ChannelFromContext returns the channel stored with ContextWithChannel.
+func ChannelFromContext(ctx context.Context) (string, bool) {
+	channel, ok := ctx.Value(contextChannelKey).(string)
+
+	return channel, ok
+}
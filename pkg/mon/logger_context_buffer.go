@@ -0,0 +1,101 @@
+package mon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// maxLogBufferEntries limits the memory the buffer of a single long running request can take. If it is full, the
+// oldest entries are dropped.
+const maxLogBufferEntries = 1000
+
+type contextLogBufferKey struct{}
+
+type bufferedEntry struct {
+	output *loggerOutput
+	buffer []byte
+}
+
+type logBuffer struct {
+	lck          sync.Mutex
+	flushOnError bool
+	entries      []bufferedEntry
+}
+
+// ContextWithLogBuffer returns a new Context carrying a log buffer, e.g. for a request. Loggers bound to the context
+// with WithContext keep their trace, debug and info entries in the buffer instead of writing them, until the fate of
+// the entries is decided with FlushBuffered or DiscardBuffered at the end of the request. Warnings and errors are
+// written right away. With flushOnError, an error writes the buffered entries before itself, so the entries leading
+// to the error are visible.
+func ContextWithLogBuffer(ctx context.Context, flushOnError bool) context.Context {
+	return context.WithValue(ctx, contextLogBufferKey{}, &logBuffer{
+		flushOnError: flushOnError,
+	})
+}
+
+// FlushBuffered writes the entries buffered in the log buffer of the context in the order they were logged.
+func FlushBuffered(ctx context.Context) {
+	if buffer := logBufferFromContext(ctx); buffer != nil {
+		buffer.flush()
+	}
+}
+
+// DiscardBuffered drops the entries buffered in the log buffer of the context.
+func DiscardBuffered(ctx context.Context) {
+	if buffer := logBufferFromContext(ctx); buffer != nil {
+		buffer.discard()
+	}
+}
+
+func logBufferFromContext(ctx context.Context) *logBuffer {
+	buffer, _ := ctx.Value(contextLogBufferKey{}).(*logBuffer)
+
+	return buffer
+}
+
+// add keeps the entry if its level is buffered and returns whether it did so.
+func (b *logBuffer) add(output *loggerOutput, level string, buffer []byte) bool {
+	if levels[level] >= levels[Error] && b.flushOnError {
+		b.flush()
+	}
+
+	if levels[level] >= levels[Warn] {
+		return false
+	}
+
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	if len(b.entries) >= maxLogBufferEntries {
+		b.entries = b.entries[1:]
+	}
+
+	b.entries = append(b.entries, bufferedEntry{
+		output: output,
+		buffer: buffer,
+	})
+
+	return true
+}
+
+func (b *logBuffer) flush() {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	for _, entry := range b.entries {
+		if err := entry.output.write(entry.buffer, false); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		}
+	}
+
+	b.entries = nil
+}
+
+func (b *logBuffer) discard() {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	b.entries = nil
+}
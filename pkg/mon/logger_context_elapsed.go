@@ -0,0 +1,37 @@
+package mon
+
+import (
+	"context"
+	"time"
+)
+
+type contextStartTimeKey struct{}
+
+// elapsedSince is the value of the elapsed_ms context field until an entry is written. As the context fields are
+// resolved once per context, the logger converts it to the milliseconds since the start with its clock on every entry.
+type elapsedSince time.Time
+
+// ContextWithStartTime returns a new Context carrying the current time as start time, e.g. of a request.
+// Together with ContextStartTimeFieldsResolver every entry logged with the context contains the field elapsed_ms.
+func ContextWithStartTime(ctx context.Context) context.Context {
+	return ContextWithStartTimeAt(ctx, time.Now())
+}
+
+// ContextWithStartTimeAt works like ContextWithStartTime, but with a given start time, e.g. from a fake clock.
+func ContextWithStartTimeAt(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, contextStartTimeKey{}, start)
+}
+
+// ContextStartTimeFieldsResolver adds the field elapsed_ms with the milliseconds between the start time stored with
+// ContextWithStartTime and the time an entry is logged, measured with the clock of the logger.
+func ContextStartTimeFieldsResolver(ctx context.Context) map[string]interface{} {
+	start, ok := ctx.Value(contextStartTimeKey{}).(time.Time)
+
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		"elapsed_ms": elapsedSince(start),
+	}
+}
@@ -41,6 +41,11 @@ func (l *ContextEnforcingLogger) Debugf(msg string, args ...interface{}) {
 	l.logger.Debugf(msg, args...)
 }
 
+func (l *ContextEnforcingLogger) DebugTemplate(msg string, fields Fields) {
+	l.checkContext(Debug)
+	l.logger.DebugTemplate(msg, fields)
+}
+
 func (l *ContextEnforcingLogger) Error(err error, msg string) {
 	l.checkContext(Error)
 	l.logger.Error(err, msg)
@@ -61,6 +66,11 @@ func (l *ContextEnforcingLogger) Infof(msg string, args ...interface{}) {
 	l.logger.Infof(msg, args...)
 }
 
+func (l *ContextEnforcingLogger) InfoTemplate(msg string, fields Fields) {
+	l.checkContext(Info)
+	l.logger.InfoTemplate(msg, fields)
+}
+
 func (l *ContextEnforcingLogger) Warn(args ...interface{}) {
 	l.checkContext(Warn)
 	l.logger.Warn(args...)
@@ -71,6 +81,11 @@ func (l *ContextEnforcingLogger) Warnf(msg string, args ...interface{}) {
 	l.logger.Warnf(msg, args...)
 }
 
+func (l *ContextEnforcingLogger) WarnTemplate(msg string, fields Fields) {
+	l.checkContext(Warn)
+	l.logger.WarnTemplate(msg, fields)
+}
+
 func (l *ContextEnforcingLogger) WithChannel(channel string) Logger {
 	return &ContextEnforcingLogger{
 		logger:             l.logger.WithChannel(channel),
@@ -0,0 +1,165 @@
+package mon
+
+import (
+	"github.com/jonboulle/clockwork"
+	"sync"
+	"time"
+)
+
+// logDedup collapses identical entries logged in a row, see WithDeduplication. It is shared with every logger created
+// from the one it was configured for.
+type logDedup struct {
+	lck      sync.Mutex
+	window   time.Duration
+	pending  *dedupEntry
+	sweeping bool
+}
+
+// dedupEntry is the entry repeated at the moment. The fields and logger of the last occurrence are kept to write the
+// summary.
+type dedupEntry struct {
+	logger      *logger
+	key         string
+	level       string
+	msg         string
+	err         error
+	fields      Fields
+	first       time.Time
+	last        time.Time
+	occurrences int
+}
+
+func newLogDedup(window time.Duration) *logDedup {
+	return &logDedup{
+		window: window,
+	}
+}
+
+// admit returns whether the entry has to be written. A repetition of the pending entry within the window is only
+// counted, any other entry closes the pending one and writes its summary before being written itself.
+func (d *logDedup) admit(l *logger, level string, msg string, err error, fields Fields) bool {
+	now := l.clock.Now()
+	key := dedupKey(level, msg, err)
+
+	d.lck.Lock()
+
+	if pending := d.pending; pending != nil && pending.key == key && now.Sub(pending.first) < d.window {
+		pending.logger = l
+		pending.fields = fields
+		pending.last = now
+		pending.occurrences++
+
+		d.lck.Unlock()
+
+		return false
+	}
+
+	closed := d.close()
+	entry := &dedupEntry{
+		logger:      l,
+		key:         key,
+		level:       level,
+		msg:         msg,
+		err:         err,
+		fields:      fields,
+		first:       now,
+		last:        now,
+		occurrences: 1,
+	}
+	d.pending = entry
+
+	if !d.sweeping {
+		d.sweeping = true
+		go d.sweep(l.clock)
+	}
+
+	d.lck.Unlock()
+
+	closed.write()
+
+	return true
+}
+
+// sweep writes the summary of the pending entry once its window closed. Entries replacing the pending one in the
+// meantime are picked up by the same sweep, so there is at most one running at a time. It ends with the pending entry.
+func (d *logDedup) sweep(clock clockwork.Clock) {
+	wait := d.window
+
+	for {
+		<-clock.After(wait)
+
+		d.lck.Lock()
+
+		if d.pending == nil {
+			d.sweeping = false
+			d.lck.Unlock()
+
+			return
+		}
+
+		if wait = d.pending.first.Add(d.window).Sub(clock.Now()); wait > 0 {
+			d.lck.Unlock()
+			continue
+		}
+
+		closed := d.close()
+		d.sweeping = false
+		d.lck.Unlock()
+
+		closed.write()
+
+		return
+	}
+}
+
+func (d *logDedup) flush() {
+	d.lck.Lock()
+	closed := d.close()
+	d.lck.Unlock()
+
+	closed.write()
+}
+
+// close ends the pending entry and returns it if it has been repeated, so a summary has to be written. It has to be
+// called with the lock held.
+func (d *logDedup) close() *dedupEntry {
+	pending := d.pending
+
+	if pending == nil {
+		return nil
+	}
+
+	d.pending = nil
+
+	if pending.occurrences < 2 {
+		return nil
+	}
+
+	return pending
+}
+
+// write logs the summary of a repeated entry. It is written like the entry itself with the number of occurrences and
+// the timestamps of the first and last one.
+func (e *dedupEntry) write() {
+	if e == nil {
+		return
+	}
+
+	fields := e.logger.limits.mergeFields(e.fields, Fields{
+		"occurrences":      e.occurrences,
+		"first_occurrence": e.logger.formatTime(e.first),
+		"last_occurrence":  e.logger.formatTime(e.last),
+	})
+
+	e.logger.emit(e.level, e.msg, e.err, fields)
+}
+
+func dedupKey(level string, msg string, err error) string {
+	key := level + "\x00" + msg
+
+	if err != nil {
+		key += "\x00" + err.Error()
+	}
+
+	return key
+}
@@ -0,0 +1,23 @@
+package mon
+
+import "context"
+
+const (
+	ChannelEvents = "events"
+	FieldEvent    = "event"
+)
+
+// Event logs a domain event like user.created at info level on the events channel of the logger, bound to the
+// context. The name is written unchanged as message and as field event, which always wins over an event key in the
+// payload, so consumers can rely on it to route and count the events. The payload is added as fields.
+func Event(ctx context.Context, logger Logger, name string, payload Fields) {
+	fields := make(Fields, len(payload)+1)
+
+	for key, value := range payload {
+		fields[key] = value
+	}
+
+	fields[FieldEvent] = name
+
+	logger.WithChannel(ChannelEvents).WithContext(ctx).InfoTemplate(name, fields)
+}
@@ -0,0 +1,107 @@
+package mon
+
+import (
+	"fmt"
+	"github.com/jonboulle/clockwork"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+)
+
+const payloadSamplingResolution = 1000000
+
+type PayloadSamplingSettings struct {
+	// Field holds the payload, "payload" if empty
+	Field string
+	// Rate is the share of entries, between 0 and 1, whose payload is written to the side channel
+	Rate float64
+	// KeyField is a field or context field like request_id. If an entry has it, the decision depends on its value
+	// only, so either all entries of a request are sampled or none.
+	KeyField string
+}
+
+// PayloadSamplingHook keeps large payloads out of the regular log output. It removes the payload field from every
+// entry and writes a sample of the entries including their payload as json to a separate writer. Entries with a
+// sampled payload are marked with <field>_sampled: true. Hooks added after this one don't see the payload either.
+type PayloadSamplingHook struct {
+	clock    clockwork.Clock
+	random   *random
+	lck      sync.Mutex
+	writer   io.Writer
+	settings PayloadSamplingSettings
+}
+
+func NewPayloadSamplingHook(writer io.Writer, settings PayloadSamplingSettings) *PayloadSamplingHook {
+	return NewPayloadSamplingHookWithInterfaces(clockwork.NewRealClock(), writer, settings)
+}
+
+func NewPayloadSamplingHookWithInterfaces(clock clockwork.Clock, writer io.Writer, settings PayloadSamplingSettings) *PayloadSamplingHook {
+	if settings.Field == "" {
+		settings.Field = "payload"
+	}
+
+	return &PayloadSamplingHook{
+		clock:    clock,
+		random:   newTimeSeededRandom(),
+		writer:   writer,
+		settings: settings,
+	}
+}
+
+func (h *PayloadSamplingHook) Fire(level string, msg string, err error, data *Metadata) error {
+	if _, ok := data.Fields[h.settings.Field]; !ok {
+		return nil
+	}
+
+	sampled := h.isSampled(data)
+
+	if sampled {
+		timestamp := h.clock.Now().Format(time.RFC3339Nano)
+		entry, formatErr := formatterJson(timestamp, level, msg, err, data)
+
+		if formatErr != nil {
+			return formatErr
+		}
+
+		h.lck.Lock()
+		_, writeErr := h.writer.Write(entry)
+		h.lck.Unlock()
+
+		if writeErr != nil {
+			return fmt.Errorf("can not write sampled payload: %w", writeErr)
+		}
+	}
+
+	// the fields of the metadata are a copy owned by the current log call, so they can be changed in place
+	delete(data.Fields, h.settings.Field)
+
+	if sampled {
+		data.Fields[h.settings.Field+"_sampled"] = true
+	}
+
+	return nil
+}
+
+// setRandom makes the hook use the random source of the logger it was added to, see WithRandSource
+func (h *PayloadSamplingHook) setRandom(random *random) {
+	h.random = random
+}
+
+func (h *PayloadSamplingHook) isSampled(data *Metadata) bool {
+	key, ok := data.Fields[h.settings.KeyField]
+
+	if !ok {
+		key, ok = data.ContextFields[h.settings.KeyField]
+	}
+
+	if h.settings.KeyField == "" || !ok {
+		return h.random.Float64() < h.settings.Rate
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(fmt.Sprint(key)))
+
+	// the low bits of fnv spread better than the high ones for keys differing in their last characters only
+	return float64(hash.Sum64()%payloadSamplingResolution)/payloadSamplingResolution < h.settings.Rate
+}
@@ -0,0 +1,122 @@
+package mon_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getPayloadSamplingLogger(t *testing.T, settings mon.PayloadSamplingSettings) (mon.GosoLog, *bytes.Buffer, *bytes.Buffer) {
+	clock := clockwork.NewFakeClockAt(time.Date(1984, 4, 4, 0, 0, 0, 0, time.UTC))
+	side := bytes.NewBuffer([]byte{})
+	hook := mon.NewPayloadSamplingHookWithInterfaces(clock, side, settings)
+
+	logger, out := getLogger()
+	err := logger.Option(mon.WithHook(hook))
+	assert.NoError(t, err)
+
+	return logger, out, side
+}
+
+func TestPayloadSamplingHook(t *testing.T) {
+	logger, out, side := getPayloadSamplingLogger(t, mon.PayloadSamplingSettings{
+		Rate: 1,
+	})
+
+	logger.WithFields(mon.Fields{
+		"payload": "large body",
+	}).Info("request")
+	logger.Info("no payload")
+
+	assert.JSONEq(t, `{"channel":"default","context":{},"fields":{"payload":"large body"},"level":2,"level_name":"info","message":"request","timestamp":"1984-04-04T00:00:00Z"}`, side.String())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"fields":{"payload_sampled":true}`)
+	assert.Contains(t, lines[1], `"fields":{}`)
+}
+
+func TestPayloadSamplingHook_NotSampled(t *testing.T) {
+	logger, out, side := getPayloadSamplingLogger(t, mon.PayloadSamplingSettings{
+		Field: "body",
+		Rate:  0,
+	})
+
+	logger.WithFields(mon.Fields{
+		"body": "large body",
+	}).Info("request")
+
+	assert.Empty(t, side.String())
+	assert.Contains(t, out.String(), `"fields":{}`, "the payload should be removed from the regular output")
+}
+
+func TestPayloadSamplingHook_KeyField(t *testing.T) {
+	logger, _, side := getPayloadSamplingLogger(t, mon.PayloadSamplingSettings{
+		Rate:     0.5,
+		KeyField: "request_id",
+	})
+
+	sampledRequests := 0
+
+	for i := 0; i < 100; i++ {
+		side.Reset()
+
+		requestLogger := logger.WithFields(mon.Fields{
+			"request_id": fmt.Sprintf("request-%d", i),
+		})
+
+		for j := 0; j < 3; j++ {
+			requestLogger.WithFields(mon.Fields{
+				"payload": j,
+			}).Info("line")
+		}
+
+		lines := strings.Count(side.String(), "\n")
+		assert.Contains(t, []int{0, 3}, lines, "all lines of a request should be sampled together or not at all")
+
+		if lines == 3 {
+			sampledRequests++
+		}
+	}
+
+	assert.True(t, sampledRequests > 0 && sampledRequests < 100, "some requests should be sampled, but not all")
+}
+
+func TestPayloadSamplingHook_WithRandSource(t *testing.T) {
+	logger, _, side := getPayloadSamplingLogger(t, mon.PayloadSamplingSettings{
+		Rate: 0.5,
+	})
+
+	err := logger.Option(mon.WithRandSource(rand.NewSource(1)))
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		logger.WithFields(mon.Fields{
+			"payload": i,
+		}).Info("line")
+	}
+
+	sampled := make([]int, 0)
+
+	for _, line := range strings.Split(strings.TrimSpace(side.String()), "\n") {
+		entry := struct {
+			Fields struct {
+				Payload int `json:"payload"`
+			} `json:"fields"`
+		}{}
+
+		err = json.Unmarshal([]byte(line), &entry)
+		assert.NoError(t, err)
+
+		sampled = append(sampled, entry.Fields.Payload)
+	}
+
+	assert.Equal(t, []int{3, 4, 6, 7, 8, 9}, sampled, "the same seed should always sample the same lines")
+}
@@ -0,0 +1,87 @@
+package mon
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jonboulle/clockwork"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RingBufferHook keeps the last n log entries in memory, formatted as json regardless of the format of the logger.
+// Use it together with its ServeHTTP method to inspect the recent logs of a running service. As the logs might
+// contain sensitive data, make sure to put the handler behind some kind of authentication.
+type RingBufferHook struct {
+	clock   clockwork.Clock
+	lck     sync.Mutex
+	entries [][]byte
+	next    int
+	count   int
+}
+
+func NewRingBufferHook(size int) *RingBufferHook {
+	return NewRingBufferHookWithInterfaces(clockwork.NewRealClock(), size)
+}
+
+func NewRingBufferHookWithInterfaces(clock clockwork.Clock, size int) *RingBufferHook {
+	if size < 1 {
+		size = 1
+	}
+
+	return &RingBufferHook{
+		clock:   clock,
+		entries: make([][]byte, size),
+	}
+}
+
+func (h *RingBufferHook) Fire(level string, msg string, err error, data *Metadata) error {
+	timestamp := h.clock.Now().Format(time.RFC3339Nano)
+	entry, formatErr := formatterJson(timestamp, level, msg, err, data)
+
+	if formatErr != nil {
+		return formatErr
+	}
+
+	entry = bytes.TrimRight(entry, "\n")
+
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+
+	if h.count < len(h.entries) {
+		h.count++
+	}
+
+	return nil
+}
+
+// Entries returns the buffered log entries, the newest one first
+func (h *RingBufferHook) Entries() []json.RawMessage {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	entries := make([]json.RawMessage, h.count)
+
+	for i := 0; i < h.count; i++ {
+		idx := (h.next - 1 - i + len(h.entries)) % len(h.entries)
+		entries[i] = h.entries[idx]
+	}
+
+	return entries
+}
+
+func (h *RingBufferHook) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	body, err := json.Marshal(h.Entries())
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,36 @@
+package mon_test
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRingBufferHook(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(1984, 4, 4, 0, 0, 0, 0, time.UTC))
+	hook := mon.NewRingBufferHookWithInterfaces(clock, 2)
+
+	logger, _ := getLogger()
+	err := logger.Option(mon.WithFormat(mon.FormatConsole), mon.WithHook(hook))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		logger.Infof("msg %d", i)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/logs", nil)
+	rec := httptest.NewRecorder()
+	hook.ServeHTTP(rec, req)
+
+	expected := `[
+		{"channel":"default","context":{},"fields":{},"level":2,"level_name":"info","message":"msg 2","timestamp":"1984-04-04T00:00:00Z"},
+		{"channel":"default","context":{},"fields":{},"level":2,"level_name":"info","message":"msg 1","timestamp":"1984-04-04T00:00:00Z"}
+	]`
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, expected, rec.Body.String(), "the two newest entries should be returned newest first")
+}
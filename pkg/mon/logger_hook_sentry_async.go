@@ -0,0 +1,87 @@
+package mon
+
+import (
+	"errors"
+	"fmt"
+	"github.com/getsentry/sentry-go"
+	pkgErrors "github.com/pkg/errors"
+)
+
+// SentryQueueSize is the number of entries waiting to be reported by WithSentry. Entries logged while the queue is
+// full are dropped and counted as SentryDropped in the LoggerStats.
+const SentryQueueSize = 100
+
+type asyncSentryEvent struct {
+	exception error
+	tags      map[string]string
+	extra     map[string]interface{}
+}
+
+type asyncSentryHook struct {
+	sentry   Sentry
+	minLevel int
+	stats    *loggerStats
+	queue    chan asyncSentryEvent
+}
+
+func newAsyncSentryHook(client Sentry, minLevel string, stats *loggerStats) *asyncSentryHook {
+	hook := &asyncSentryHook{
+		sentry:   client,
+		minLevel: levelPriority(minLevel),
+		stats:    stats,
+		queue:    make(chan asyncSentryEvent, SentryQueueSize),
+	}
+
+	go hook.run()
+
+	return hook
+}
+
+// Fire queues the entry to be reported in the background, so a slow or unavailable sentry doesn't delay the logging
+func (h *asyncSentryHook) Fire(level string, msg string, err error, data *Metadata) error {
+	if levelPriority(level) < h.minLevel {
+		return nil
+	}
+
+	exception := errors.New(msg)
+
+	if err != nil {
+		exception = pkgErrors.Cause(err)
+	}
+
+	stringTags := make(map[string]string, len(data.Tags)+2)
+	for k, v := range data.Tags {
+		stringTags[k] = fmt.Sprint(v)
+	}
+
+	stringTags["channel"] = data.Channel
+	stringTags["level"] = level
+
+	extra := mergeMapStringInterface(data.Fields, data.ContextFields)
+	extra["message"] = msg
+
+	event := asyncSentryEvent{
+		exception: exception,
+		tags:      stringTags,
+		extra:     extra,
+	}
+
+	select {
+	case h.queue <- event:
+	default:
+		h.stats.incSentryDropped()
+	}
+
+	return nil
+}
+
+// run reports the queued entries one after another for as long as the process lives
+func (h *asyncSentryHook) run() {
+	for event := range h.queue {
+		scope := sentry.NewScope()
+		scope.SetTags(event.tags)
+		scope.SetExtras(event.extra)
+
+		h.sentry.CaptureException(event.exception, nil, scope)
+	}
+}
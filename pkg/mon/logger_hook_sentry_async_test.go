@@ -0,0 +1,83 @@
+package mon_test
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestLogger_WithSentry(t *testing.T) {
+	captured := make(chan error, 2)
+
+	sentry := new(mocks.Sentry)
+	sentry.On("CaptureException", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		captured <- args.Get(0).(error)
+	}).Return(nil)
+
+	logger, _ := getLogger()
+	err := logger.Option(mon.WithSentry(sentry, mon.Warn))
+	assert.NoError(t, err)
+
+	logger.Info("not reported")
+	logger.Warn("reported warning")
+	logger.Error(fmt.Errorf("the cause"), "reported error")
+
+	messages := make([]string, 0, 2)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-captured:
+			messages = append(messages, err.Error())
+		case <-time.After(time.Second):
+			assert.Fail(t, "sentry should have been called")
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"reported warning", "the cause"}, messages)
+
+	sentry.AssertNumberOfCalls(t, "CaptureException", 2)
+}
+
+func TestLogger_WithSentry_QueueFull(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	sentry := new(mocks.Sentry)
+	sentry.On("CaptureException", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		if args.Get(0).(error).Error() == "blocking" {
+			close(started)
+			<-release
+		}
+	}).Return(nil)
+
+	logger, _ := getLogger()
+	err := logger.Option(mon.WithSentry(sentry, mon.Warn))
+	assert.NoError(t, err)
+
+	logger.Warn("blocking")
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "sentry should have been called")
+	}
+
+	for i := 0; i < mon.SentryQueueSize+2; i++ {
+		logger.Warn("queued")
+	}
+
+	assert.Equal(t, uint64(2), logger.Stats().SentryDropped, "the entries exceeding the queue should be dropped")
+
+	close(release)
+}
+
+func TestLogger_WithSentry_UnknownLevel(t *testing.T) {
+	logger, _ := getLogger()
+	err := logger.Option(mon.WithSentry(new(mocks.Sentry), "fatal"))
+
+	assert.EqualError(t, err, "unknown log level for sentry: fatal")
+}
@@ -0,0 +1,25 @@
+package mon
+
+import "context"
+
+type noopLogger struct{}
+
+// NewNoopLogger returns a logger which discards everything written to it
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+func (l noopLogger) Debug(_ ...interface{})                     {}
+func (l noopLogger) Info(_ ...interface{})                      {}
+func (l noopLogger) Warn(_ ...interface{})                      {}
+func (l noopLogger) Error(_ error, _ string)                    {}
+func (l noopLogger) Debugf(_ string, _ ...interface{})          {}
+func (l noopLogger) Infof(_ string, _ ...interface{})           {}
+func (l noopLogger) Warnf(_ string, _ ...interface{})           {}
+func (l noopLogger) Errorf(_ error, _ string, _ ...interface{}) {}
+func (l noopLogger) DebugTemplate(_ string, _ Fields)           {}
+func (l noopLogger) InfoTemplate(_ string, _ Fields)            {}
+func (l noopLogger) WarnTemplate(_ string, _ Fields)            {}
+func (l noopLogger) WithChannel(_ string) Logger                { return l }
+func (l noopLogger) WithContext(_ context.Context) Logger       { return l }
+func (l noopLogger) WithFields(_ Fields) Logger                 { return l }
@@ -2,11 +2,58 @@ package mon
 
 import (
 	"fmt"
+	"github.com/jonboulle/clockwork"
 	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
 )
 
 type LoggerOption func(logger *logger) error
 
+// WithBufferedOutput collects the entries in a buffer of size bytes instead of writing every entry on its own. The
+// buffer is written once it is full, every flushInterval (0 disables this) and with Flush, which should be called on
+// shutdown. Errors are written right away together with the buffered entries before them. The buffer is shared with
+// every logger created from this one. The buffer keeps whole entries and passes each of them to the writer with its
+// own Write, so message oriented writers like the gelf udp writer still get one entry per Write.
+func WithBufferedOutput(size int, flushInterval time.Duration) LoggerOption {
+	return func(logger *logger) error {
+		if size <= 0 {
+			return fmt.Errorf("the size of the output buffer has to be positive")
+		}
+
+		logger.output.buffer(size, flushInterval)
+
+		return nil
+	}
+}
+
+// WithClock replaces the clock used for the timestamps of the log entries, e.g. to freeze the time in tests
+// for loggers which were not created with NewLoggerWithInterfaces.
+func WithClock(clock clockwork.Clock) LoggerOption {
+	return func(logger *logger) error {
+		logger.clock = clock
+
+		return nil
+	}
+}
+
+// WithColor colorizes the console format with ansi codes: the level by its severity, the timestamp dimmed. Colors are
+// only written while the writer of the logger is a terminal and never if the environment variable NO_COLOR is set, so
+// the output stays clean if it is redirected to a file or a pipe.
+func WithColor() LoggerOption {
+	return func(logger *logger) error {
+		logger.consoleColor = consoleColorOn
+
+		if noColor, ok := os.LookupEnv("NO_COLOR"); ok && noColor != "" {
+			logger.consoleColor = consoleColorOff
+		}
+
+		return nil
+	}
+}
+
 func WithContextFieldsResolver(resolver ...ContextFieldsResolver) LoggerOption {
 	return func(logger *logger) error {
 		logger.ctxResolver = append(logger.ctxResolver, resolver...)
@@ -15,9 +62,51 @@ func WithContextFieldsResolver(resolver ...ContextFieldsResolver) LoggerOption {
 	}
 }
 
+// WithDefaultFields adds fields which are written with every log entry. Like a tag, a default field only acts as a
+// default: fields with the same key added at runtime using WithFields take precedence, as do tags and fields already
+// added to the logger. Unlike a tag, it isn't passed to the hooks as tag, so it doesn't become a tag of a sentry
+// event or the gelf host. Use tags for the few values to filter and group by, like the environment, and default
+// fields for everything else.
+func WithDefaultFields(fields Fields) LoggerOption {
+	return func(logger *logger) error {
+		// the map is shared with child loggers, so we replace it instead of writing to it
+		logger.defaultFields = logger.limits.mergeFields(fields, logger.defaultFields)
+
+		return nil
+	}
+}
+
+// WithDeduplication collapses identical entries logged in a row, e.g. by a retry loop. Entries are identical if
+// their level, message and error message are equal. The first entry is written right away, its repetitions within
+// the window are only counted. Once the window closes or a different entry is logged, a single entry with the fields
+// of the last repetition and the fields occurrences, first_occurrence and last_occurrence is written. Flush writes
+// the pending summary as well. The deduplication is shared with every logger created from this one.
+func WithDeduplication(window time.Duration) LoggerOption {
+	return func(logger *logger) error {
+		if window <= 0 {
+			return fmt.Errorf("the deduplication window has to be positive")
+		}
+
+		logger.dedup = newLogDedup(window)
+
+		return nil
+	}
+}
+
+// WithErrorChain adds the fields error_type and error_chain to the entries of Error and Errorf. error_type is the
+// concrete type of the error, error_chain lists the type and message of the error and of every error wrapped by it.
+// The err field keeps the message of the error.
+func WithErrorChain() LoggerOption {
+	return func(logger *logger) error {
+		logger.errorChain = true
+
+		return nil
+	}
+}
+
 func WithFormat(format string) LoggerOption {
 	return func(logger *logger) error {
-		if _, ok := formatters[format]; !ok {
+		if _, ok := getFormatter(format); !ok {
 			return fmt.Errorf("unknown logger format: %s", format)
 		}
 
@@ -27,8 +116,64 @@ func WithFormat(format string) LoggerOption {
 	}
 }
 
+// WithFlattenFields writes nested fields and context fields as top level fields joined by the separator, e.g.
+// {user:{id:5}} as {"user.id":5} and {items:[a,b]} as {"items.0":a,"items.1":b}, for outputs not supporting nested
+// objects.
+func WithFlattenFields(separator string) LoggerOption {
+	return func(logger *logger) error {
+		if separator == "" {
+			return fmt.Errorf("the separator to flatten fields can't be empty")
+		}
+
+		logger.flattenSeparator = separator
+
+		return nil
+	}
+}
+
+// WithHostFields adds the hostname as tag "host" and, if the environment variables POD_NAME and POD_NAMESPACE are set
+// like with the kubernetes downward api, the tags "pod" and "namespace". The values are resolved once. The gelf
+// formatters use the host tag as the host of the message.
+func WithHostFields() LoggerOption {
+	return func(logger *logger) error {
+		hostname, err := os.Hostname()
+
+		if err != nil {
+			return fmt.Errorf("can not resolve the hostname: %w", err)
+		}
+
+		tags := map[string]interface{}{
+			"host": hostname,
+		}
+
+		if pod, ok := os.LookupEnv("POD_NAME"); ok && pod != "" {
+			tags["pod"] = pod
+		}
+
+		if namespace, ok := os.LookupEnv("POD_NAMESPACE"); ok && namespace != "" {
+			tags["namespace"] = namespace
+		}
+
+		return WithTags(tags)(logger)
+	}
+}
+
+// WithGoroutineId adds the id of the logging goroutine as field "goroutine" to every log line.
+// This is a debug aid only, see GetGoroutineId for its limitations.
+func WithGoroutineId() LoggerOption {
+	return func(logger *logger) error {
+		logger.goroutineId = true
+
+		return nil
+	}
+}
+
 func WithHook(hook LoggerHook) LoggerOption {
 	return func(logger *logger) error {
+		if aware, ok := hook.(randomAware); ok {
+			aware.setRandom(logger.random)
+		}
+
 		logger.hooks = append(logger.hooks, hook)
 
 		return nil
@@ -43,21 +188,191 @@ func WithLevel(level string) LoggerOption {
 	}
 }
 
+// WithLevelFormat uses the format for entries of the level instead of the one set by WithFormat, e.g. to write
+// errors with more details than the other entries.
+func WithLevelFormat(level string, format string) LoggerOption {
+	return func(logger *logger) error {
+		if _, ok := levels[level]; !ok {
+			return fmt.Errorf("unknown log level %s for format %s", level, format)
+		}
+
+		if _, ok := getFormatter(format); !ok {
+			return fmt.Errorf("unknown logger format: %s", format)
+		}
+
+		// the map is shared with child loggers, so we replace it instead of writing to it
+		levelFormats := make(map[string]string, len(logger.levelFormats)+1)
+
+		for l, f := range logger.levelFormats {
+			levelFormats[l] = f
+		}
+
+		levelFormats[level] = format
+		logger.levelFormats = levelFormats
+
+		return nil
+	}
+}
+
+// WithMaxByteStringLength sets the length up to which byte slices of valid utf-8 are logged as string, longer or binary
+// ones are logged base64 encoded like encoding/json does. A length of 0 always encodes them, the default is 256. It
+// applies to the fields added after the option, so it should be set when creating the logger.
+func WithMaxByteStringLength(length int) LoggerOption {
+	return func(logger *logger) error {
+		if length < 0 {
+			return fmt.Errorf("the max byte string length can not be negative")
+		}
+
+		logger.limits.maxByteStringLength = length
+
+		return nil
+	}
+}
+
+// WithMaxSliceLength sets the number of elements up to which slices and arrays in fields are logged completely. Longer
+// ones are logged with their first elements followed by an element like "…(99000 more)". A length of 0, the default,
+// logs all elements. Like WithMaxByteStringLength, it applies to the fields added after the option.
+func WithMaxSliceLength(length int) LoggerOption {
+	return func(logger *logger) error {
+		if length < 0 {
+			return fmt.Errorf("the max slice length can not be negative")
+		}
+
+		logger.limits.maxSliceLength = length
+
+		return nil
+	}
+}
+
+// WithMinLevelForChannel overrides the level set by WithLevel for a single channel. This works for the
+// ChannelDefault channel, too: raising it to warn silences the default channel while the channels of
+// libraries keep logging at the global level.
+func WithMinLevelForChannel(channel string, level string) LoggerOption {
+	return func(logger *logger) error {
+		if _, ok := levels[level]; !ok {
+			return fmt.Errorf("unknown log level %s for channel %s", level, channel)
+		}
+
+		// the map is shared with child loggers, so we replace it instead of writing to it
+		channelLevels := make(map[string]int, len(logger.channelLevels)+1)
+
+		for c, l := range logger.channelLevels {
+			channelLevels[c] = l
+		}
+
+		channelLevels[channel] = levels[level]
+		logger.channelLevels = channelLevels
+
+		return nil
+	}
+}
+
+// WithOmitEmpty drops fields and context fields with a nil value, an empty string, an empty slice or an empty map
+// from the written entries. Nested maps are cleaned as well, so maps containing nothing but empty values vanish. The
+// hooks still get all fields, as only the output is affected.
+func WithOmitEmpty() LoggerOption {
+	return func(logger *logger) error {
+		logger.omitEmpty = true
+
+		return nil
+	}
+}
+
+// WithOutput is the same as WithWriter
 func WithOutput(output io.Writer) LoggerOption {
+	return WithWriter(output)
+}
+
+// WithWriter replaces the writer of the logger. The swap waits for in-flight writes to finish and
+// applies to every logger created from this one via WithChannel, WithContext or WithFields, too.
+func WithWriter(writer io.Writer) LoggerOption {
 	return func(logger *logger) error {
-		logger.output = output
+		logger.output.swap(writer)
 
 		return nil
 	}
 }
 
-func WithTags(tags map[string]interface{}) LoggerOption {
+// WithRandSource replaces the source of the random decisions of the hooks, like the sampling of the
+// PayloadSamplingHook, e.g. to get the same decisions in every test run like WithClock does for the time. By default
+// the source is seeded with the current time.
+//
+//	logger.Option(mon.WithRandSource(rand.NewSource(1)))
+func WithRandSource(source rand.Source) LoggerOption {
 	return func(logger *logger) error {
-		for k, v := range tags {
-			logger.data.Fields[k] = v
-			logger.data.Tags[k] = v
+		logger.random = newRandom(source)
+
+		for _, hook := range logger.hooks {
+			if aware, ok := hook.(randomAware); ok {
+				aware.setRandom(logger.random)
+			}
+		}
+
+		return nil
+	}
+}
+
+// WithRedactedFields replaces the values of the given keys with [redacted] in the fields, context fields and tags of
+// every entry, including nested maps. Keys are matched case insensitive. The redaction happens before the hooks and
+// the formatter get the entry, so it applies to every format.
+func WithRedactedFields(keys ...string) LoggerOption {
+	return func(logger *logger) error {
+		// the map is shared with child loggers, so we replace it instead of writing to it
+		redactedKeys := make(map[string]bool, len(logger.redactedKeys)+len(keys))
+
+		for key := range logger.redactedKeys {
+			redactedKeys[key] = true
+		}
+
+		for _, key := range keys {
+			redactedKeys[strings.ToLower(key)] = true
+		}
+
+		logger.redactedKeys = redactedKeys
+
+		return nil
+	}
+}
+
+// WithReservedKeyPolicy decides what happens to fields with the same name as a key formatters use for the entry
+// itself, like message or level. By default they are renamed with the prefix "fields.".
+func WithReservedKeyPolicy(policy ReservedKeyPolicy) LoggerOption {
+	return func(logger *logger) error {
+		switch policy {
+		case ReservedKeyPolicyPrefix, ReservedKeyPolicyIgnore, ReservedKeyPolicyError:
+			logger.reservedKeyPolicy = policy
+
+			return nil
 		}
 
+		return fmt.Errorf("unknown reserved key policy: %s", policy)
+	}
+}
+
+// WithSentry reports every log entry at or above minLevel to the given sentry client, including the error,
+// the stacktrace of error logs, the channel, the tags and all fields. Reporting happens asynchronously by a single
+// goroutine. Up to SentryQueueSize entries wait for it, further ones are dropped and counted in the LoggerStats.
+func WithSentry(client Sentry, minLevel string) LoggerOption {
+	return func(logger *logger) error {
+		if _, ok := levels[minLevel]; !ok {
+			return fmt.Errorf("unknown log level for sentry: %s", minLevel)
+		}
+
+		logger.hooks = append(logger.hooks, newAsyncSentryHook(client, minLevel, logger.stats))
+
+		return nil
+	}
+}
+
+// WithTags adds tags which are passed to the hooks and written as fields with every log entry. A tag only acts
+// as a default: fields with the same key added at runtime using WithFields take precedence. A tag added later on
+// replaces an earlier one with the same key. See WithDefaultFields for fields which shouldn't be passed to the hooks
+// as tags.
+func WithTags(tags map[string]interface{}) LoggerOption {
+	return func(logger *logger) error {
+		// the map is shared with child loggers, so we replace it instead of writing to it
+		logger.data.Tags = logger.limits.mergeMapStringInterface(logger.data.Tags, tags)
+
 		return nil
 	}
 }
@@ -69,3 +384,16 @@ func WithTimestampFormat(format string) LoggerOption {
 		return nil
 	}
 }
+
+// WithTimestampLocation converts the time of the clock into the given location before formatting the timestamp.
+// Without it, the timestamp is in the location of the clock, which is the local zone of the host for the real clock.
+// UTC is strongly recommended to get the same timestamps on every host:
+//
+//	logger.Option(mon.WithTimestampLocation(time.UTC))
+func WithTimestampLocation(location *time.Location) LoggerOption {
+	return func(logger *logger) error {
+		logger.timestampLocation = location
+
+		return nil
+	}
+}
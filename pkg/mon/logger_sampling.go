@@ -70,6 +70,14 @@ func (l *SamplingLogger) Debugf(msg string, args ...interface{}) {
 	l.Logger.Debugf(msg, args...)
 }
 
+func (l *SamplingLogger) DebugTemplate(msg string, fields Fields) {
+	if !l.shouldLog(msg) {
+		return
+	}
+
+	l.Logger.DebugTemplate(msg, fields)
+}
+
 func (l *SamplingLogger) Error(err error, msg string) {
 	if !l.shouldLog(msg) {
 		return
@@ -102,6 +110,14 @@ func (l *SamplingLogger) Infof(msg string, args ...interface{}) {
 	l.Logger.Infof(msg, args...)
 }
 
+func (l *SamplingLogger) InfoTemplate(msg string, fields Fields) {
+	if !l.shouldLog(msg) {
+		return
+	}
+
+	l.Logger.InfoTemplate(msg, fields)
+}
+
 func (l *SamplingLogger) Warn(args ...interface{}) {
 	if !l.shouldLog(fmt.Sprint(args...)) {
 		return
@@ -118,6 +134,14 @@ func (l *SamplingLogger) Warnf(msg string, args ...interface{}) {
 	l.Logger.Warnf(msg, args...)
 }
 
+func (l *SamplingLogger) WarnTemplate(msg string, fields Fields) {
+	if !l.shouldLog(msg) {
+		return
+	}
+
+	l.Logger.WarnTemplate(msg, fields)
+}
+
 func (l *SamplingLogger) shouldLog(msg string) bool {
 	value, ok := l.logs.Load(msg)
 
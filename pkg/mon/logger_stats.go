@@ -0,0 +1,68 @@
+package mon
+
+import "sync/atomic"
+
+// LoggerStats contains the number of log calls per level since the logger was created. Logged counts every call,
+// including the ones suppressed by the level of the logger or its channel, Emitted only the ones which were written.
+// SentryDropped counts the entries which weren't reported to sentry because its queue was full, see WithSentry.
+type LoggerStats struct {
+	Logged        map[string]uint64 `json:"logged"`
+	Emitted       map[string]uint64 `json:"emitted"`
+	SentryDropped uint64            `json:"sentryDropped"`
+}
+
+// loggerStats is shared by a logger and all loggers derived from it. The maps are never written after the
+// creation, only the counters they point to, so they can be read without a lock.
+type loggerStats struct {
+	logged        map[string]*uint64
+	emitted       map[string]*uint64
+	sentryDropped uint64
+}
+
+func newLoggerStats() *loggerStats {
+	stats := &loggerStats{
+		logged:  make(map[string]*uint64, len(levels)),
+		emitted: make(map[string]*uint64, len(levels)),
+	}
+
+	for level := range levels {
+		stats.logged[level] = new(uint64)
+		stats.emitted[level] = new(uint64)
+	}
+
+	return stats
+}
+
+func (s *loggerStats) incLogged(level string) {
+	if counter, ok := s.logged[level]; ok {
+		atomic.AddUint64(counter, 1)
+	}
+}
+
+func (s *loggerStats) incEmitted(level string) {
+	if counter, ok := s.emitted[level]; ok {
+		atomic.AddUint64(counter, 1)
+	}
+}
+
+func (s *loggerStats) incSentryDropped() {
+	atomic.AddUint64(&s.sentryDropped, 1)
+}
+
+func (s *loggerStats) snapshot() LoggerStats {
+	stats := LoggerStats{
+		Logged:        make(map[string]uint64, len(s.logged)),
+		Emitted:       make(map[string]uint64, len(s.emitted)),
+		SentryDropped: atomic.LoadUint64(&s.sentryDropped),
+	}
+
+	for level, counter := range s.logged {
+		stats.Logged[level] = atomic.LoadUint64(counter)
+	}
+
+	for level, counter := range s.emitted {
+		stats.Emitted[level] = atomic.LoadUint64(counter)
+	}
+
+	return stats
+}
@@ -0,0 +1,75 @@
+package mon
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"sync"
+)
+
+type stdLogWriter struct {
+	lck     sync.Mutex
+	logger  Logger
+	level   string
+	partial []byte
+}
+
+// StdLogWriter returns a writer logging every line written to it as an entry of the logger at the given level, so
+// libraries writing their output to an io.Writer end up in the log like everything else. An incomplete line is kept
+// until a later write completes it. Empty lines are skipped. The error level logs every line as its own error, an
+// unknown level is logged as info.
+func StdLogWriter(logger Logger, level string) io.Writer {
+	return &stdLogWriter{
+		logger: logger,
+		level:  level,
+	}
+}
+
+// NewStdLogger returns a *log.Logger for libraries accepting one, which logs its lines on the channel at the given
+// level, see StdLogWriter. It adds neither a prefix nor a timestamp, as the logger writes its own.
+func NewStdLogger(logger Logger, channel string, level string) *log.Logger {
+	return log.New(StdLogWriter(logger.WithChannel(channel), level), "", 0)
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	w.partial = append(w.partial, p...)
+
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+
+		if i < 0 {
+			break
+		}
+
+		line := strings.TrimRight(string(w.partial[:i]), "\r")
+		w.partial = w.partial[i+1:]
+
+		if line != "" {
+			w.log(line)
+		}
+	}
+
+	if len(w.partial) == 0 {
+		w.partial = nil
+	}
+
+	return len(p), nil
+}
+
+func (w *stdLogWriter) log(line string) {
+	switch w.level {
+	case Trace, Debug:
+		w.logger.Debug(line)
+	case Warn:
+		w.logger.Warn(line)
+	case Error:
+		w.logger.Error(errors.New(line), line)
+	default:
+		w.logger.Info(line)
+	}
+}
@@ -0,0 +1,43 @@
+package mon_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestStdLogWriter(t *testing.T) {
+	logger, out := getLogger()
+	writer := mon.StdLogWriter(logger, mon.Warn)
+
+	n, err := fmt.Fprint(writer, "first line\nsecond ")
+	assert.NoError(t, err)
+	assert.Equal(t, 18, n)
+
+	_, err = fmt.Fprint(writer, "line\r\n\n")
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.JSONEq(t, `{"channel":"default","context":{},"fields":{},"level":3,"level_name":"warn","message":"first line","timestamp":"1984-04-04T00:00:00Z"}`, lines[0])
+	assert.JSONEq(t, `{"channel":"default","context":{},"fields":{},"level":3,"level_name":"warn","message":"second line","timestamp":"1984-04-04T00:00:00Z"}`, lines[1])
+}
+
+func TestNewStdLogger(t *testing.T) {
+	logger, out := getLogger()
+	stdLogger := mon.NewStdLogger(logger, "driver", mon.Error)
+
+	stdLogger.Printf("connection %d lost", 3)
+
+	entry := map[string]interface{}{}
+	err := json.Unmarshal(out.Bytes(), &entry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "driver", entry["channel"])
+	assert.Equal(t, "error", entry["level_name"])
+	assert.Equal(t, "connection 3 lost", entry["message"])
+	assert.Equal(t, "connection 3 lost", entry["err"])
+}
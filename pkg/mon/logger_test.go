@@ -3,10 +3,22 @@ package mon_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/mon/logpb"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -135,6 +147,205 @@ func TestLogger_mergeMapStringInterface(t *testing.T) {
 	}, parsed)
 }
 
+type testEnum int
+
+func (e testEnum) String() string {
+	switch e {
+	case 1:
+		return "first"
+	case 2:
+		return "second"
+	default:
+		return "unknown"
+	}
+}
+
+type testMarshaler struct {
+	internal string
+}
+
+func (m testMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"external": m.internal})
+}
+
+func (m testMarshaler) String() string {
+	return "should not be used"
+}
+
+type testEnumHolder struct {
+	Enum    testEnum
+	Enums   []testEnum
+	ByEnum  map[testEnum]testEnum
+	Pointer *testEnum
+}
+
+func TestLogger_prepareForLog_StringerAndMarshaler(t *testing.T) {
+	logger, out := getLogger()
+	second := testEnum(2)
+
+	logger.WithFields(mon.Fields{
+		"enum":      testEnum(1),
+		"nilEnum":   (*testEnum)(nil),
+		"marshaler": testMarshaler{internal: "value"},
+		"nested": testEnumHolder{
+			Enum:    1,
+			Enums:   []testEnum{1, 2, 3},
+			ByEnum:  map[testEnum]testEnum{1: 2},
+			Pointer: &second,
+		},
+	}).Info("msg")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err := json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"enum":      "first",
+		"nilEnum":   nil,
+		"marshaler": map[string]interface{}{"external": "value"},
+		"nested": map[string]interface{}{
+			"Enum":    "first",
+			"Enums":   []interface{}{"first", "second", "unknown"},
+			"ByEnum":  map[string]interface{}{"first": "second"},
+			"Pointer": "second",
+		},
+	}, parsed.Fields)
+}
+
+func TestLogger_prepareForLog_Bytes(t *testing.T) {
+	logger, out := getLogger()
+
+	logger.WithFields(mon.Fields{
+		"text":   []byte("some text"),
+		"binary": []byte{0xff, 0x00, 0xfe},
+		"long":   bytes.Repeat([]byte("a"), 257),
+	}).Info("msg")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err := json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"text":   "some text",
+		"binary": "/wD+",
+		"long":   base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("a"), 257)),
+	}, parsed.Fields)
+}
+
+func TestLogger_WithMaxByteStringLength(t *testing.T) {
+	logger, out := getLogger()
+
+	err := logger.Option(mon.WithMaxByteStringLength(-1))
+	assert.EqualError(t, err, "the max byte string length can not be negative")
+
+	err = logger.Option(mon.WithMaxByteStringLength(4))
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{
+		"short": []byte("text"),
+		"long":  []byte("longer text"),
+	}).Info("msg")
+
+	other, otherOut := getLogger()
+	other.WithFields(mon.Fields{
+		"long": []byte("longer text"),
+	}).Info("msg")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"short": "text",
+		"long":  base64.StdEncoding.EncodeToString([]byte("longer text")),
+	}, parsed.Fields)
+
+	err = json.Unmarshal(otherOut.Bytes(), &parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, "longer text", parsed.Fields["long"], "the length should only apply to the logger it was set for")
+}
+
+func TestLogger_prepareForLog_MaxSliceLength(t *testing.T) {
+	logger, out := getLogger()
+
+	err := logger.Option(mon.WithMaxSliceLength(-1))
+	assert.EqualError(t, err, "the max slice length can not be negative")
+
+	err = logger.Option(mon.WithMaxSliceLength(3))
+	assert.NoError(t, err)
+
+	large := make([]int, 100000)
+	for i := range large {
+		large[i] = i
+	}
+
+	logger.WithFields(mon.Fields{
+		"large": large,
+		"short": [3]string{"a", "b", "c"},
+	}).Info("msg")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"large": []interface{}{float64(0), float64(1), float64(2), "…(99997 more)"},
+		"short": []interface{}{"a", "b", "c"},
+	}, parsed.Fields, "the fields are prepared twice, but the large slice should only be shortened once")
+}
+
+type testSnowflake struct {
+	Id int64
+}
+
+type testSnowflakeMarshaler struct {
+	id int64
+}
+
+func (m testSnowflakeMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int64{"id": m.id})
+}
+
+func TestLogger_prepareForLog_Int64Precision(t *testing.T) {
+	logger, out := getLogger()
+	id := int64(1234567890123456789)
+
+	logger.WithFields(mon.Fields{
+		"struct":    testSnowflake{Id: id},
+		"pointer":   &testSnowflake{Id: id},
+		"slice":     []testSnowflake{{Id: id}},
+		"marshaler": testSnowflakeMarshaler{id: id},
+	}).Info("msg")
+
+	assert.Equal(t, 4, strings.Count(out.String(), "1234567890123456789"), "every id should be logged exactly")
+
+	parsed := struct {
+		Fields struct {
+			Struct    testSnowflake   `json:"struct"`
+			Pointer   testSnowflake   `json:"pointer"`
+			Slice     []testSnowflake `json:"slice"`
+			Marshaler struct {
+				Id int64 `json:"id"`
+			} `json:"marshaler"`
+		} `json:"fields"`
+	}{}
+	err := json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, id, parsed.Fields.Struct.Id)
+	assert.Equal(t, id, parsed.Fields.Pointer.Id)
+	assert.Equal(t, id, parsed.Fields.Slice[0].Id)
+	assert.Equal(t, id, parsed.Fields.Marshaler.Id)
+}
+
 func TestLogger_WithChannel(t *testing.T) {
 	gosoLog, out := getLogger()
 
@@ -253,3 +464,1126 @@ func getLogger() (mon.GosoLog, *bytes.Buffer) {
 
 	return client, out
 }
+
+type testingTRecorder struct {
+	lines []string
+}
+
+func (t *testingTRecorder) Helper() {}
+
+func (t *testingTRecorder) Log(args ...interface{}) {
+	t.lines = append(t.lines, fmt.Sprint(args...))
+}
+
+func TestTestLogger(t *testing.T) {
+	recorder := &testingTRecorder{}
+
+	logger := mon.NewTestLogger(recorder)
+	err := logger.Option(mon.WithFormat(mon.FormatJson), mon.WithTimestampFormat("static"))
+	assert.NoError(t, err)
+
+	logger.Debug("msg")
+
+	assert.Len(t, recorder.lines, 1)
+	assert.JSONEq(t, `{"fields":{},"context":{},"channel":"default","level":1,"level_name":"debug","message":"msg","timestamp":"static"}`, recorder.lines[0])
+}
+
+func TestNoopLogger(t *testing.T) {
+	logger := mon.NewNoopLogger()
+
+	assert.NotPanics(t, func() {
+		logger.WithChannel("channel").WithFields(mon.Fields{"a": 1}).WithContext(context.Background()).Error(fmt.Errorf("err"), "msg")
+	})
+}
+
+func TestLogger_WithGoroutineId(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithGoroutineId())
+	assert.NoError(t, err)
+
+	logger.Info("msg")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(mon.GetGoroutineId()), parsed.Fields["goroutine"])
+	assert.NotEqual(t, 0.0, parsed.Fields["goroutine"])
+}
+
+type chainTestError struct {
+	err error
+}
+
+func (e *chainTestError) Error() string {
+	return fmt.Sprintf("chain test: %s", e.err)
+}
+
+func (e *chainTestError) Unwrap() error {
+	return e.err
+}
+
+func TestLogger_WithErrorChain(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithErrorChain())
+	assert.NoError(t, err)
+
+	logErr := fmt.Errorf("request failed: %w", &chainTestError{err: io.EOF})
+	logger.Error(logErr, "msg")
+
+	parsed := struct {
+		Err    string                 `json:"err"`
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "request failed: chain test: EOF", parsed.Err)
+	assert.Equal(t, "*fmt.wrapError", parsed.Fields["error_type"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "*fmt.wrapError", "message": "request failed: chain test: EOF"},
+		map[string]interface{}{"type": "*mon_test.chainTestError", "message": "chain test: EOF"},
+		map[string]interface{}{"type": "*errors.errorString", "message": "EOF"},
+	}, parsed.Fields["error_chain"])
+}
+
+func TestLogger_RegisterFormatter(t *testing.T) {
+	mon.RegisterFormatter("custom", func(timestamp string, level string, msg string, err error, data *mon.Metadata) ([]byte, error) {
+		return []byte(fmt.Sprintf("%s|%s|%s|%s\n", timestamp, level, data.Channel, msg)), nil
+	})
+
+	logger, out := getLogger()
+	err := logger.Option(mon.WithFormat("custom"))
+	assert.NoError(t, err)
+
+	logger.Warn("msg")
+
+	assert.Equal(t, "1984-04-04T00:00:00Z|warn|default|msg\n", out.String())
+}
+
+func TestLogger_WithFormat_Unknown(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithFormat("unknown"))
+
+	assert.EqualError(t, err, "unknown logger format: unknown")
+
+	logger.Info("msg")
+	assert.Contains(t, out.String(), `"message":"msg"`, "the former format should still be used")
+}
+
+func TestLogger_WithWriter(t *testing.T) {
+	logger, out := getLogger()
+	child := logger.WithChannel("child")
+
+	swapped := bytes.NewBuffer([]byte{})
+	err := logger.Option(mon.WithWriter(swapped))
+	assert.NoError(t, err)
+
+	logger.Info("parent")
+	child.Info("child")
+
+	assert.Empty(t, out.String())
+	assert.Contains(t, swapped.String(), `"message":"parent"`)
+	assert.Contains(t, swapped.String(), `"message":"child"`)
+}
+
+func TestLogger_WithColor_NoTerminal(t *testing.T) {
+	file, err := ioutil.TempFile("", "gosoline-logger-color")
+	assert.NoError(t, err)
+
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	out := bytes.NewBuffer([]byte{})
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), out)
+	err = logger.Option(mon.WithFormat(mon.FormatConsole), mon.WithColor())
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{"foo": "bar"}).Error(fmt.Errorf("boom"), "to a buffer")
+
+	assert.NotContains(t, out.String(), "\x1b[", "a buffer is no terminal")
+	assert.Contains(t, out.String(), "default error   to a buffer")
+	assert.Contains(t, out.String(), "ERR: boom")
+
+	err = logger.Option(mon.WithWriter(file))
+	assert.NoError(t, err)
+
+	logger.Warn("to a file")
+
+	written, err := ioutil.ReadFile(file.Name())
+	assert.NoError(t, err)
+	assert.NotContains(t, string(written), "\x1b[", "a regular file is no terminal")
+	assert.Contains(t, string(written), "default warn    to a file")
+}
+
+func TestLogger_WithFormat_Protobuf(t *testing.T) {
+	out := bytes.NewBuffer([]byte{})
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), out)
+	err := logger.Option(mon.WithFormat(mon.FormatProtobuf), mon.WithTimestampFormat(time.RFC3339))
+	assert.NoError(t, err)
+
+	logger.WithChannel("proto").WithFields(mon.Fields{
+		"id":     int64(1234567890123456789),
+		"count":  3,
+		"nested": map[string]interface{}{"tags": []string{"a", "b"}},
+	}).Info("first")
+	logger.Error(fmt.Errorf("boom"), "second")
+
+	entries := make([]*logpb.LogEntry, 0)
+	stream := out.Bytes()
+
+	for len(stream) > 0 {
+		size, n := protowire.ConsumeVarint(stream)
+		assert.True(t, n > 0, "every entry should be prefixed with its size")
+
+		entry := &logpb.LogEntry{}
+		err = proto.Unmarshal(stream[n:n+int(size)], entry)
+		assert.NoError(t, err)
+
+		entries = append(entries, entry)
+		stream = stream[n+int(size):]
+	}
+
+	assert.Len(t, entries, 2)
+
+	first := entries[0]
+	assert.Equal(t, "1984-04-04T00:00:00Z", first.Timestamp)
+	assert.Equal(t, "info", first.LevelName)
+	assert.Equal(t, int32(2), first.Level)
+	assert.Equal(t, "proto", first.Channel)
+	assert.Equal(t, "first", first.Message)
+	assert.Equal(t, "1234567890123456789", first.Fields.Fields["id"].GetStringValue(), "big ints should be written exactly")
+	assert.Equal(t, float64(3), first.Fields.Fields["count"].GetNumberValue())
+
+	tags := first.Fields.Fields["nested"].GetStructValue().Fields["tags"].GetListValue().Values
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "b", tags[1].GetStringValue())
+
+	second := entries[1]
+	assert.Equal(t, "error", second.LevelName)
+	assert.Equal(t, "boom", second.Err)
+	assert.Contains(t, second.Fields.Fields, "stacktrace")
+}
+
+func TestLogger_WithTags_FieldsTakePrecedence(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithTags(map[string]interface{}{
+		"env":     "config",
+		"service": "config",
+	}))
+	assert.NoError(t, err)
+
+	child := logger.WithFields(mon.Fields{
+		"env": "runtime",
+	})
+
+	err = logger.Option(mon.WithTags(map[string]interface{}{
+		"region": "parent",
+	}))
+	assert.NoError(t, err)
+
+	child.Info("child")
+	logger.Info("parent")
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	parsed := make([]struct {
+		Fields map[string]interface{} `json:"fields"`
+	}, 2)
+
+	for i, line := range lines {
+		err = json.Unmarshal(line, &parsed[i])
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]interface{}{"env": "runtime", "service": "config"}, parsed[0].Fields, "tags added to the parent later on should not leak into the child")
+	assert.Equal(t, map[string]interface{}{"env": "config", "region": "parent", "service": "config"}, parsed[1].Fields)
+}
+
+func TestLogger_WithTags_Replace(t *testing.T) {
+	hook := new(monMocks.LoggerHook)
+	hook.On("Fire", mon.Info, "replaced", nil, mock.MatchedBy(func(data *mon.Metadata) bool {
+		return len(data.Tags) == 1 && data.Tags["env"] == "second"
+	})).Return(nil).Once()
+
+	logger, out := getLogger()
+	err := logger.Option(
+		mon.WithHook(hook),
+		mon.WithTags(map[string]interface{}{
+			"env": "first",
+		}),
+		mon.WithTags(map[string]interface{}{
+			"env": "second",
+		}),
+	)
+	assert.NoError(t, err)
+
+	logger.Info("replaced")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"env": "second"}, parsed.Fields, "the field should be the tag added last, like the tag passed to the hooks")
+
+	hook.AssertExpectations(t)
+}
+
+func TestLogger_WithDefaultFields(t *testing.T) {
+	hook := new(monMocks.LoggerHook)
+	hook.On("Fire", mon.Info, "child", nil, mock.MatchedBy(func(data *mon.Metadata) bool {
+		return len(data.Tags) == 1 && data.Tags["env"] == "config"
+	})).Return(nil).Once()
+	hook.On("Fire", mon.Info, "template", nil, mock.Anything).Return(nil).Once()
+
+	logger, out := getLogger()
+	err := logger.Option(
+		mon.WithHook(hook),
+		mon.WithTags(map[string]interface{}{
+			"env": "config",
+		}),
+		mon.WithDefaultFields(mon.Fields{
+			"env":     "default",
+			"service": "default",
+			"version": "default",
+		}),
+	)
+	assert.NoError(t, err)
+
+	child := logger.WithFields(mon.Fields{
+		"service": "runtime",
+	})
+
+	child.Info("child")
+	child.InfoTemplate("template", mon.Fields{
+		"version": "template",
+	})
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	parsed := make([]struct {
+		Fields map[string]interface{} `json:"fields"`
+	}, 2)
+
+	for i, line := range lines {
+		err = json.Unmarshal(line, &parsed[i])
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]interface{}{"env": "config", "service": "runtime", "version": "default"}, parsed[0].Fields, "fields should override default fields, which don't override the tags")
+	assert.Equal(t, map[string]interface{}{"env": "config", "service": "runtime", "version": "template"}, parsed[1].Fields, "template fields should override default fields")
+
+	hook.AssertExpectations(t)
+}
+
+func TestLogger_WithFields_Lazy(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithLevel(mon.Info))
+	assert.NoError(t, err)
+
+	calls := 0
+	child := logger.WithFields(mon.Fields{
+		"expensive": func() interface{} {
+			calls++
+
+			return testEnum(calls)
+		},
+	})
+
+	child.Debug("dropped")
+	assert.Equal(t, 0, calls, "the lazy field should not be evaluated for dropped entries")
+	assert.Empty(t, out.String())
+
+	child.Info("written")
+	assert.Equal(t, 1, calls)
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"expensive": "first"}, parsed.Fields)
+}
+
+func TestLogger_InfoTemplate(t *testing.T) {
+	logger, out := getLogger()
+	fields := mon.Fields{
+		"user": 42,
+	}
+
+	logger.WithFields(mon.Fields{"service": "api"}).InfoTemplate("user logged in", fields)
+
+	expected := `{"fields":{"service":"api","user":42},"context":{},"channel":"default","level":2,"level_name":"info","message":"user logged in","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+	assert.Equal(t, mon.Fields{"user": 42}, fields, "the passed fields should not be modified")
+}
+
+func TestLogger_WithMinLevelForChannel_Default(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithMinLevelForChannel(mon.ChannelDefault, mon.Warn))
+	assert.NoError(t, err)
+
+	library := logger.WithChannel("library")
+
+	logger.Info("default info")
+	library.Info("library info")
+	logger.Warn("default warn")
+
+	assert.NotContains(t, out.String(), "default info")
+	assert.Contains(t, out.String(), "library info", "channels without a level should fall back to the global level")
+	assert.Contains(t, out.String(), "default warn")
+
+	err = logger.Option(mon.WithMinLevelForChannel("library", "unknown"))
+	assert.EqualError(t, err, "unknown log level unknown for channel library")
+}
+
+func TestLogger_WithMinLevelForChannel_Debug(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithLevel(mon.Info), mon.WithMinLevelForChannel("sql", mon.Debug))
+	assert.NoError(t, err)
+
+	sql := logger.WithChannel("sql")
+
+	sql.Debug("sql debug")
+	sql.Debugf("sql %s", "debugf")
+	sql.DebugTemplate("sql debug template", mon.Fields{})
+	logger.Debug("default debug")
+	logger.Debugf("default %s", "debugf")
+
+	assert.Contains(t, out.String(), "sql debug")
+	assert.Contains(t, out.String(), "sql debugf", "a channel should enable debug entries under a global info level")
+	assert.Contains(t, out.String(), "sql debug template")
+	assert.NotContains(t, out.String(), "default debug")
+}
+
+func TestLogger_WithClock(t *testing.T) {
+	out := bytes.NewBuffer([]byte{})
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 2, 2, 12, 0, 0, 0, time.UTC))
+
+	hook := new(monMocks.LoggerHook)
+	hook.On("Fire", mon.Info, "failing hook", nil, mock.Anything).Return(fmt.Errorf("hook error"))
+
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewRealClock(), out)
+	err := logger.Option(
+		mon.WithFormat(mon.FormatJson),
+		mon.WithTimestampFormat(time.RFC3339),
+		mon.WithClock(clock),
+		mon.WithHook(hook),
+	)
+	assert.NoError(t, err)
+
+	logger.Info("failing hook")
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2, "the error of the hook and the entry itself should be logged")
+
+	for _, line := range lines {
+		parsed := struct {
+			Timestamp string `json:"timestamp"`
+		}{}
+
+		err = json.Unmarshal(line, &parsed)
+		assert.NoError(t, err)
+		assert.Equal(t, "2020-02-02T12:00:00Z", parsed.Timestamp)
+	}
+
+	hook.AssertExpectations(t)
+}
+
+func TestLogger_WithTimestampLocation(t *testing.T) {
+	out := bytes.NewBuffer([]byte{})
+	berlin := time.FixedZone("CET", 3600)
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 2, 2, 13, 0, 0, 0, berlin))
+
+	hook := new(monMocks.LoggerHook)
+	hook.On("Fire", mon.Info, "failing hook", nil, mock.Anything).Return(fmt.Errorf("hook error"))
+
+	logger := mon.NewLoggerWithInterfaces(clock, out)
+	err := logger.Option(
+		mon.WithFormat(mon.FormatJson),
+		mon.WithTimestampFormat(time.RFC3339),
+		mon.WithTimestampLocation(time.UTC),
+		mon.WithHook(hook),
+	)
+	assert.NoError(t, err)
+
+	logger.Info("failing hook")
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2, "the error of the hook and the entry itself should be logged")
+
+	for _, line := range lines {
+		parsed := struct {
+			Timestamp string `json:"timestamp"`
+		}{}
+
+		err = json.Unmarshal(line, &parsed)
+		assert.NoError(t, err)
+		assert.Equal(t, "2020-02-02T12:00:00Z", parsed.Timestamp)
+	}
+
+	hook.AssertExpectations(t)
+}
+
+func TestLogger_ContextStartTimeFieldsResolver(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	out := bytes.NewBuffer([]byte{})
+
+	logger := mon.NewLoggerWithInterfaces(clock, out)
+	err := logger.Option(mon.WithFormat(mon.FormatJson), mon.WithContextFieldsResolver(mon.ContextStartTimeFieldsResolver))
+	assert.NoError(t, err)
+
+	ctx := mon.ContextWithStartTimeAt(context.Background(), clock.Now())
+	ctxLogger := logger.WithContext(ctx)
+
+	clock.Advance(150 * time.Millisecond)
+	ctxLogger.Info("first")
+
+	clock.Advance(time.Second)
+	ctxLogger.Info("second")
+
+	logger.WithContext(context.Background()).Info("without start time")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+
+	expected := []string{
+		`{"elapsed_ms":150}`,
+		`{"elapsed_ms":1150}`,
+		`{}`,
+	}
+
+	for i, line := range lines {
+		entry := struct {
+			Context json.RawMessage `json:"context"`
+		}{}
+
+		err = json.Unmarshal([]byte(line), &entry)
+		assert.NoError(t, err)
+		assert.JSONEq(t, expected[i], string(entry.Context))
+	}
+}
+
+func TestLogger_WithReservedKeyPolicy_Prefix(t *testing.T) {
+	logger, out := getLogger()
+
+	logger.WithFields(mon.Fields{
+		"message": "from the fields",
+	}).Info("msg")
+
+	expected := `{"fields":{"fields.message":"from the fields"},"context":{},"channel": "default", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+}
+
+func TestLogger_WithReservedKeyPolicy_Ignore(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithReservedKeyPolicy(mon.ReservedKeyPolicyIgnore))
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{
+		"level": "user",
+		"other": "value",
+	}).Info("msg")
+
+	expected := `{"fields":{"other":"value"},"context":{},"channel": "default", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+}
+
+func TestLogger_WithReservedKeyPolicy_Error(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithReservedKeyPolicy(mon.ReservedKeyPolicyError))
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{
+		"timestamp": "user",
+	}).Info("msg")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"err":"fields with reserved keys have been dropped: timestamp"`)
+	assert.JSONEq(t, `{"fields":{},"context":{},"channel": "default", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`, lines[1])
+
+	err = logger.Option(mon.WithReservedKeyPolicy("unknown"))
+	assert.Error(t, err)
+}
+
+func TestLogger_WithFlattenFields(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithFlattenFields("."))
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{
+		"user": mon.Fields{
+			"id":    5,
+			"roles": []string{"admin", "dev"},
+		},
+		"empty": map[string]string{},
+		"flat":  "value",
+	}).Info("msg")
+
+	expected := `{"fields":{"user.id":5,"user.roles.0":"admin","user.roles.1":"dev","empty":{},"flat":"value"},"context":{},"channel": "default", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+
+	out.Reset()
+	logger.WithFields(mon.Fields{
+		"user.id":      5,
+		"user.roles.0": "admin",
+	}).Info("msg")
+
+	expected = `{"fields":{"user.id":5,"user.roles.0":"admin"},"context":{},"channel": "default", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String(), "flattening flat fields should not change them")
+
+	err = logger.Option(mon.WithFlattenFields(""))
+	assert.Error(t, err)
+}
+
+type traceIdKey struct{}
+
+func TestLogger_WithContext_ResolvesOncePerContext(t *testing.T) {
+	resolved := 0
+
+	logger, out := getLogger()
+	err := logger.Option(mon.WithContextFieldsResolver(func(ctx context.Context) map[string]interface{} {
+		resolved++
+
+		return map[string]interface{}{
+			"trace_id": ctx.Value(traceIdKey{}),
+		}
+	}))
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), traceIdKey{}, "a")
+	ctxLogger := logger.WithContext(ctx)
+	ctxLogger.Info("first")
+	ctxLogger.WithContext(ctx).Info("second")
+	ctxLogger.WithFields(mon.Fields{"b": 1}).WithContext(ctx).Info("third")
+
+	assert.Equal(t, 1, resolved, "the context fields should only be resolved once for the same context")
+
+	out.Reset()
+	otherCtx := context.WithValue(context.Background(), traceIdKey{}, "b")
+	ctxLogger.WithContext(otherCtx).Info("other")
+
+	assert.Equal(t, 2, resolved, "the context fields should be resolved again for a different context")
+	assert.Contains(t, out.String(), `"context":{"trace_id":"b"}`)
+}
+
+func TestLogger_WithLevelFormat(t *testing.T) {
+	mon.RegisterFormatter("test-level-format", func(timestamp string, level string, msg string, err error, data *mon.Metadata) ([]byte, error) {
+		return []byte(fmt.Sprintf("%s %s: %v\n", level, msg, err)), nil
+	})
+
+	logger, out := getLogger()
+	err := logger.Option(mon.WithLevelFormat(mon.Error, "test-level-format"))
+	assert.NoError(t, err)
+
+	channelLogger := logger.WithChannel("other")
+	channelLogger.Info("compact")
+	channelLogger.Error(fmt.Errorf("boom"), "detailed")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.JSONEq(t, `{"fields":{},"context":{},"channel": "other", "level":2,"level_name":"info","message":"compact","timestamp":"1984-04-04T00:00:00Z"}`, lines[0])
+	assert.Equal(t, "error detailed: boom", lines[1])
+
+	err = logger.Option(mon.WithLevelFormat("unknown", mon.FormatJson))
+	assert.Error(t, err)
+
+	err = logger.Option(mon.WithLevelFormat(mon.Error, "unknown"))
+	assert.Error(t, err)
+}
+
+func TestLogger_Stats(t *testing.T) {
+	logger, _ := getLogger()
+	err := logger.Option(mon.WithMinLevelForChannel("quiet", mon.Error))
+	assert.NoError(t, err)
+
+	logger.Debug("suppressed by the level")
+	logger.Info("emitted")
+	logger.WithChannel("quiet").Warn("suppressed by the channel")
+	logger.WithFields(mon.Fields{"a": 1}).Error(fmt.Errorf("boom"), "emitted")
+
+	expected := mon.LoggerStats{
+		Logged: map[string]uint64{
+			mon.Trace: 0,
+			mon.Debug: 1,
+			mon.Info:  1,
+			mon.Warn:  1,
+			mon.Error: 1,
+		},
+		Emitted: map[string]uint64{
+			mon.Trace: 0,
+			mon.Debug: 0,
+			mon.Info:  1,
+			mon.Warn:  0,
+			mon.Error: 1,
+		},
+	}
+
+	assert.Equal(t, expected, logger.Stats())
+}
+
+func TestLogger_WithHostFields(t *testing.T) {
+	hostname, err := os.Hostname()
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.Setenv("POD_NAME", "pod-1"))
+	assert.NoError(t, os.Setenv("POD_NAMESPACE", "ns"))
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("POD_NAME"))
+		assert.NoError(t, os.Unsetenv("POD_NAMESPACE"))
+	}()
+
+	logger, out := getLogger()
+	err = logger.Option(mon.WithHostFields())
+	assert.NoError(t, err)
+
+	logger.Info("msg")
+
+	expected := fmt.Sprintf(`{"fields":{"host":"%s","pod":"pod-1","namespace":"ns"},"context":{},"channel": "default", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`, hostname)
+	assert.JSONEq(t, expected, out.String())
+
+	out.Reset()
+	err = logger.Option(mon.WithFormat(mon.FormatGelf))
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{"host": "request.host"}).Info("msg")
+
+	gelf := make(map[string]interface{})
+	err = json.Unmarshal(out.Bytes(), &gelf)
+	assert.NoError(t, err)
+	assert.Equal(t, hostname, gelf["host"], "the gelf host should be the one of the host fields")
+	assert.Equal(t, "request.host", gelf["_host"])
+}
+
+func TestSetGlobalFields(t *testing.T) {
+	mon.SetGlobalFields(mon.Fields{
+		"service": "api",
+	})
+	defer mon.SetGlobalFields(nil)
+
+	logger, out := getLogger()
+	logger.WithFields(mon.Fields{
+		"version": "1.2.3",
+	}).Info("msg")
+
+	assert.Contains(t, out.String(), `"fields":{"service":"api","version":"1.2.3"}`)
+
+	mon.SetGlobalFields(nil)
+	out.Reset()
+
+	logger.Info("msg")
+	assert.Contains(t, out.String(), `"fields":{"service":"api"}`, "loggers should keep the global fields they were created with")
+}
+
+func TestRecoverLog(t *testing.T) {
+	logger, out := getLogger()
+
+	assert.NotPanics(t, func() {
+		defer mon.RecoverLog(logger, false)
+
+		panic("recover me")
+	})
+
+	entry := make(map[string]interface{})
+	err := json.Unmarshal(out.Bytes(), &entry)
+	assert.NoError(t, err)
+
+	fields := entry["fields"].(map[string]interface{})
+	assert.Equal(t, "recover me", entry["err"])
+	assert.Equal(t, true, fields["recovered"])
+	assert.Contains(t, fields["stacktrace"], "TestRecoverLog", "the stacktrace should contain the panicking function")
+
+	out.Reset()
+
+	assert.PanicsWithError(t, "pass me on", func() {
+		defer mon.RecoverLog(logger, true)
+
+		panic(fmt.Errorf("pass me on"))
+	})
+
+	assert.Contains(t, out.String(), `"recovered":false`)
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	logger, out := getLogger()
+
+	mon.LoggerFromContext(context.Background()).Info("dropped")
+	assert.Empty(t, out.String(), "the noop logger should be returned without a logger in the context")
+
+	ctx := mon.ContextWithLogger(context.Background(), logger.WithChannel("ctx"))
+	mon.LoggerFromContext(ctx).WithFields(mon.Fields{"a": 1}).Info("msg")
+
+	expected := `{"fields":{"a":1},"context":{},"channel": "ctx", "level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+}
+
+func TestContextWithChannel(t *testing.T) {
+	logger, out := getLogger()
+
+	ctx := mon.ContextWithChannel(context.Background(), "request")
+	ctx = mon.ContextWithLogger(ctx, logger)
+
+	mon.LoggerFromContext(ctx).Info("from context")
+	logger.WithContext(ctx).WithFields(mon.Fields{"a": 1}).Info("derived")
+	logger.WithChannel("explicit").WithContext(ctx).Info("explicit before")
+	logger.WithContext(ctx).WithChannel("explicit").Info("explicit after")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	channels := make([]string, len(lines))
+
+	for i, line := range lines {
+		parsed := struct {
+			Channel string `json:"channel"`
+		}{}
+
+		err := json.Unmarshal([]byte(line), &parsed)
+		assert.NoError(t, err)
+
+		channels[i] = parsed.Channel
+	}
+
+	assert.Equal(t, []string{"request", "request", "explicit", "explicit"}, channels)
+}
+
+func TestLogger_WithRedactedFields(t *testing.T) {
+	formats := []string{mon.FormatConsole, mon.FormatGelf, mon.FormatGelfFields, mon.FormatJson, mon.FormatProtobuf}
+
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			out := bytes.NewBuffer([]byte{})
+			logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), out)
+
+			err := logger.Option(
+				mon.WithFormat(format),
+				mon.WithRedactedFields("Password"),
+				mon.WithTags(mon.Tags{"password": "tag-secret"}),
+				mon.WithContextFieldsResolver(func(ctx context.Context) map[string]interface{} {
+					return map[string]interface{}{"password": "context-secret"}
+				}),
+			)
+			assert.NoError(t, err)
+
+			logger.WithContext(context.Background()).WithFields(mon.Fields{
+				"user": map[string]interface{}{
+					"name":     "admin",
+					"password": "nested-secret",
+				},
+				"PASSWORD": "field-secret",
+			}).Info("login")
+
+			output := out.String()
+
+			assert.Contains(t, output, "[redacted]")
+			assert.Contains(t, output, "admin")
+
+			for _, secret := range []string{"tag-secret", "context-secret", "nested-secret", "field-secret"} {
+				assert.NotContains(t, output, secret)
+			}
+		})
+	}
+}
+
+func TestLogger_RawJson(t *testing.T) {
+	logger, out := getLogger()
+
+	_, err := mon.NewRawJson([]byte(`{"a":`))
+	assert.EqualError(t, err, `the raw field value is not valid JSON: {"a":`)
+
+	raw, err := mon.NewRawJson([]byte(`{"id":12345678901234567890,"tags":["a","b"]}`))
+	assert.NoError(t, err)
+
+	logger.WithFields(mon.Fields{
+		"payload": raw,
+	}).Info("msg")
+
+	expected := `{"fields":{"payload":{"id":12345678901234567890,"tags":["a","b"]}},"context":{},"channel":"default","level":2,"level_name":"info","message":"msg","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+	assert.Contains(t, out.String(), `"payload":{"id":12345678901234567890,"tags":["a","b"]}`)
+}
+
+func TestEvent(t *testing.T) {
+	logger, out := getLogger()
+
+	mon.Event(context.Background(), logger, "order.%s", mon.Fields{
+		"event":    "overwritten",
+		"order_id": 5,
+	})
+
+	expected := `{"fields":{"event":"order.%s","order_id":5},"context":{},"channel":"events","level":2,"level_name":"info","message":"order.%s","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String())
+}
+
+type lockedBuffer struct {
+	lck sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	return b.buf.String()
+}
+
+func TestLogger_WithBufferedOutput(t *testing.T) {
+	logger, out := getLogger()
+	child := logger.WithChannel("child")
+
+	err := logger.Option(mon.WithBufferedOutput(0, 0))
+	assert.EqualError(t, err, "the size of the output buffer has to be positive")
+
+	err = logger.Option(mon.WithBufferedOutput(4096, 0))
+	assert.NoError(t, err)
+
+	logger.Info("buffered")
+	assert.Empty(t, out.String(), "the entry should be buffered")
+
+	child.Error(fmt.Errorf("failure"), "an error")
+	assert.Contains(t, out.String(), `"message":"buffered"`, "the error should flush the buffered entries")
+	assert.Contains(t, out.String(), `"message":"an error"`)
+
+	out.Reset()
+	logger.Info("before flush")
+	assert.Empty(t, out.String())
+
+	assert.NoError(t, logger.Flush())
+	assert.Contains(t, out.String(), `"message":"before flush"`)
+
+	out.Reset()
+	err = logger.Option(mon.WithBufferedOutput(64, 0))
+	assert.NoError(t, err)
+
+	logger.Info("an entry bigger than the buffer")
+	assert.Contains(t, out.String(), `"message":"an entry bigger than the buffer"`)
+}
+
+func TestLogger_WithBufferedOutput_FlushInterval(t *testing.T) {
+	out := &lockedBuffer{}
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), out)
+
+	err := logger.Option(mon.WithFormat(mon.FormatJson), mon.WithBufferedOutput(4096, 10*time.Millisecond))
+	assert.NoError(t, err)
+
+	logger.Info("flushed periodically")
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), `"message":"flushed periodically"`)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestContextWithLogBuffer(t *testing.T) {
+	logger, out := getLogger()
+
+	ctx := mon.ContextWithLogBuffer(context.Background(), false)
+	ctxLogger := logger.WithContext(ctx)
+
+	ctxLogger.Info("buffered")
+	ctxLogger.Warn("written")
+	logger.Info("not bound")
+
+	assert.NotContains(t, out.String(), `"message":"buffered"`)
+	assert.Contains(t, out.String(), `"message":"written"`)
+	assert.Contains(t, out.String(), `"message":"not bound"`)
+
+	mon.FlushBuffered(ctx)
+	assert.Contains(t, out.String(), `"message":"buffered"`)
+
+	out.Reset()
+	ctxLogger.WithFields(mon.Fields{"a": 1}).Info("discarded")
+	mon.DiscardBuffered(ctx)
+	mon.FlushBuffered(ctx)
+	assert.Empty(t, out.String())
+
+	ctx = mon.ContextWithLogBuffer(context.Background(), true)
+	ctxLogger = logger.WithContext(ctx)
+
+	ctxLogger.Info("before the error")
+	assert.Empty(t, out.String())
+
+	ctxLogger.Error(fmt.Errorf("failure"), "the error")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"message":"before the error"`)
+	assert.Contains(t, lines[1], `"message":"the error"`)
+}
+
+func TestLogger_WithOmitEmpty(t *testing.T) {
+	logger, out := getLogger()
+	err := logger.Option(mon.WithOmitEmpty())
+	assert.NoError(t, err)
+
+	var nilPointer *int
+
+	logger.WithFields(mon.Fields{
+		"id":      42,
+		"name":    "",
+		"pointer": nilPointer,
+		"tags":    []string{},
+		"nested": map[string]interface{}{
+			"status": "ok",
+			"reason": nil,
+			"empty": map[string]interface{}{
+				"list": []int{},
+			},
+		},
+		"missing": nil,
+	}).Info("msg")
+
+	parsed := struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{}
+
+	err = json.Unmarshal(out.Bytes(), &parsed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"id": float64(42),
+		"nested": map[string]interface{}{
+			"status": "ok",
+		},
+	}, parsed.Fields)
+}
+
+type dedupTestEntry struct {
+	Fields  map[string]interface{} `json:"fields"`
+	Level   string                 `json:"level_name"`
+	Message string                 `json:"message"`
+	Err     string                 `json:"err"`
+}
+
+func parseDedupTestEntries(t *testing.T, out string) []dedupTestEntry {
+	entries := make([]dedupTestEntry, 0)
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		entry := dedupTestEntry{}
+		err := json.Unmarshal([]byte(line), &entry)
+		assert.NoError(t, err)
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestLogger_WithDeduplication(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(1984, 4, 4, 0, 0, 0, 0, time.UTC))
+	out := &lockedBuffer{}
+	logger := mon.NewLoggerWithInterfaces(clock, out)
+
+	err := logger.Option(mon.WithDeduplication(0))
+	assert.EqualError(t, err, "the deduplication window has to be positive")
+
+	err = logger.Option(mon.WithFormat(mon.FormatJson), mon.WithTimestampFormat(time.RFC3339), mon.WithDeduplication(time.Minute))
+	assert.NoError(t, err)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		logger.WithFields(mon.Fields{
+			"attempt": attempt,
+		}).Warn("retrying")
+
+		clock.Advance(time.Second)
+	}
+
+	logger.Error(fmt.Errorf("connection refused"), "giving up")
+	logger.Error(fmt.Errorf("connection reset"), "giving up")
+
+	entries := parseDedupTestEntries(t, out.String())
+	assert.Len(t, entries, 4)
+
+	assert.Equal(t, "retrying", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"attempt": float64(1)}, entries[0].Fields, "the first occurrence should be written right away")
+
+	assert.Equal(t, "retrying", entries[1].Message)
+	assert.Equal(t, mon.Warn, entries[1].Level)
+	assert.Equal(t, map[string]interface{}{
+		"attempt":          float64(3),
+		"occurrences":      float64(3),
+		"first_occurrence": "1984-04-04T00:00:00Z",
+		"last_occurrence":  "1984-04-04T00:00:02Z",
+	}, entries[1].Fields, "a different entry should write the summary of the repeated one")
+
+	assert.Equal(t, "connection refused", entries[2].Err)
+	assert.Equal(t, "connection reset", entries[3].Err, "entries with a different error should not be collapsed")
+	assert.NotContains(t, entries[3].Fields, "occurrences")
+}
+
+func TestLogger_WithDeduplication_WindowClosed(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	out := &lockedBuffer{}
+	logger := mon.NewLoggerWithInterfaces(clock, out)
+
+	err := logger.Option(mon.WithFormat(mon.FormatJson), mon.WithDeduplication(time.Minute))
+	assert.NoError(t, err)
+
+	logger.Info("polling")
+	logger.Info("polling")
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), `"message":"polling"`) == 2
+	}, time.Second, 5*time.Millisecond, "the summary should be written once the window closed")
+
+	entries := parseDedupTestEntries(t, out.String())
+	assert.Equal(t, float64(2), entries[1].Fields["occurrences"])
+
+	logger.Info("polling")
+	logger.Info("polling")
+	assert.NoError(t, logger.Flush())
+
+	entries = parseDedupTestEntries(t, out.String())
+	assert.Len(t, entries, 4, "an entry after the window should be written again and flushed with its summary")
+	assert.Equal(t, float64(2), entries[3].Fields["occurrences"])
+}
+
+func TestLogger_WithDeduplication_SingleSweep(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	out := &lockedBuffer{}
+	logger := mon.NewLoggerWithInterfaces(clock, out)
+
+	err := logger.Option(mon.WithFormat(mon.FormatJson), mon.WithDeduplication(time.Minute))
+	assert.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		logger.Infof("entry %d", i)
+	}
+
+	logger.Info("polling")
+	logger.Info("polling")
+
+	blocked := make(chan struct{})
+	go func() {
+		clock.BlockUntil(1)
+		close(blocked)
+	}()
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "there should be a single sweep waiting for the window to close")
+	}
+
+	clock.Advance(time.Minute)
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), `"message":"polling"`) == 2
+	}, time.Second, 5*time.Millisecond, "the summary should be written once the window closed")
+}
@@ -0,0 +1,31 @@
+package mon
+
+import (
+	"github.com/jonboulle/clockwork"
+	"strings"
+)
+
+// TestingT is the part of testing.TB the test logger needs
+type TestingT interface {
+	Helper()
+	Log(args ...interface{})
+}
+
+type testOutput struct {
+	t TestingT
+}
+
+func (o testOutput) Write(p []byte) (int, error) {
+	o.t.Helper()
+	o.t.Log(strings.TrimRight(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// NewTestLogger returns a logger writing every line to t.Log, so the output only shows up for failed or verbose tests
+func NewTestLogger(t TestingT) GosoLog {
+	logger := NewLoggerWithInterfaces(clockwork.NewRealClock(), testOutput{t: t})
+	logger.level = levelPriority(Trace)
+
+	return logger
+}
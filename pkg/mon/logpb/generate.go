@@ -0,0 +1,4 @@
+// Package logpb contains the protobuf schema of the log entries written by the protobuf format of mon.
+package logpb
+
+//go:generate protoc --go_out=paths=source_relative:. log_entry.proto
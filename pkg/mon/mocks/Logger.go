@@ -18,6 +18,11 @@ func (_m *Logger) Debug(args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// DebugTemplate provides a mock function with given fields: msg, fields
+func (_m *Logger) DebugTemplate(msg string, fields mon.Fields) {
+	_m.Called(msg, fields)
+}
+
 // Debugf provides a mock function with given fields: format, args
 func (_m *Logger) Debugf(format string, args ...interface{}) {
 	var _ca []interface{}
@@ -46,6 +51,11 @@ func (_m *Logger) Info(args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// InfoTemplate provides a mock function with given fields: msg, fields
+func (_m *Logger) InfoTemplate(msg string, fields mon.Fields) {
+	_m.Called(msg, fields)
+}
+
 // Infof provides a mock function with given fields: format, args
 func (_m *Logger) Infof(format string, args ...interface{}) {
 	var _ca []interface{}
@@ -61,6 +71,11 @@ func (_m *Logger) Warn(args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// WarnTemplate provides a mock function with given fields: msg, fields
+func (_m *Logger) WarnTemplate(msg string, fields mon.Fields) {
+	_m.Called(msg, fields)
+}
+
 // Warnf provides a mock function with given fields: format, args
 func (_m *Logger) Warnf(format string, args ...interface{}) {
 	var _ca []interface{}
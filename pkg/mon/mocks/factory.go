@@ -43,6 +43,10 @@ func NewLoggerMockedUntilLevel(level string) *Logger {
 	mockLoggerMethod(logger, "Warn", "Warnf", mon.Warn, levelIndex >= levelMap[mon.Warn])
 	mockLoggerMethod(logger, "Error", "Errorf", mon.Error, levelIndex >= levelMap[mon.Error])
 
+	mockLoggerTemplateMethod(logger, "DebugTemplate", mon.Debug, levelIndex >= levelMap[mon.Debug])
+	mockLoggerTemplateMethod(logger, "InfoTemplate", mon.Info, levelIndex >= levelMap[mon.Info])
+	mockLoggerTemplateMethod(logger, "WarnTemplate", mon.Warn, levelIndex >= levelMap[mon.Warn])
+
 	return logger
 }
 
@@ -74,6 +78,10 @@ func mockLoggerMethod(logger *Logger, method string, methodWithFormat string, le
 	}
 }
 
+func mockLoggerTemplateMethod(logger *Logger, method string, level string, allowed bool) {
+	logger.On(method, mock.Anything, mock.Anything).Run(inspectLogFunction(level, false, allowed)).Maybe()
+}
+
 func NewMetricWriterMockedAll() *MetricWriter {
 	mw := new(MetricWriter)
 	mw.On("GetPriority").Return(mon.PriorityLow).Maybe()
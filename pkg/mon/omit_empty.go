@@ -0,0 +1,45 @@
+package mon
+
+import "reflect"
+
+// omitEmptyFields returns a copy of the fields without nil values, empty strings, empty slices and empty maps. Nested
+// maps are cleaned the same way, so a map which is empty afterwards is dropped as well.
+func omitEmptyFields(fields map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{}, len(fields))
+
+	for key, value := range fields {
+		if value, ok := omitEmptyValue(value); ok {
+			cleaned[key] = value
+		}
+	}
+
+	return cleaned
+}
+
+func omitEmptyValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return v, v != ""
+	case map[string]interface{}:
+		cleaned := omitEmptyFields(v)
+
+		return cleaned, len(cleaned) > 0
+	case Fields:
+		cleaned := omitEmptyFields(v)
+
+		return cleaned, len(cleaned) > 0
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value, !rv.IsNil()
+	case reflect.Slice, reflect.Map:
+		return value, rv.Len() > 0
+	}
+
+	return value, true
+}
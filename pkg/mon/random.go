@@ -0,0 +1,36 @@
+package mon
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// randomAware is implemented by hooks taking random decisions, like sampling. They are handed the random source of
+// the logger when they are added and whenever the source is replaced by WithRandSource.
+type randomAware interface {
+	setRandom(random *random)
+}
+
+// random guards a rand.Rand, which isn't safe for concurrent use on its own
+type random struct {
+	lck  sync.Mutex
+	rand *rand.Rand
+}
+
+func newRandom(source rand.Source) *random {
+	return &random{
+		rand: rand.New(source),
+	}
+}
+
+func newTimeSeededRandom() *random {
+	return newRandom(rand.NewSource(time.Now().UnixNano()))
+}
+
+func (r *random) Float64() float64 {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	return r.rand.Float64()
+}
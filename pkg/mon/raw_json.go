@@ -0,0 +1,37 @@
+package mon
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RawJson is a field value which is already serialized as JSON. Unlike other values, it is neither copied nor
+// inspected by reflection when the entry is written, but embedded into the output as it is. This makes it the cheap
+// choice for big payloads on hot paths. As its content isn't looked at, neither WithRedactedFields nor
+// WithFlattenFields apply to it. Formats not based on JSON write it as string.
+type RawJson struct {
+	raw json.RawMessage
+}
+
+// NewRawJson validates the JSON once, so writing the entries containing it can't fail.
+func NewRawJson(raw json.RawMessage) (RawJson, error) {
+	if !json.Valid(raw) {
+		return RawJson{}, fmt.Errorf("the raw field value is not valid JSON: %s", raw)
+	}
+
+	return RawJson{
+		raw: raw,
+	}, nil
+}
+
+func (r RawJson) MarshalJSON() ([]byte, error) {
+	if r.raw == nil {
+		return []byte("null"), nil
+	}
+
+	return r.raw, nil
+}
+
+func (r RawJson) String() string {
+	return string(r.raw)
+}
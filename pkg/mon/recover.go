@@ -0,0 +1,31 @@
+package mon
+
+import "fmt"
+
+// RecoverLog is meant to be deferred: it recovers a panic and logs the recovered value as error together with the
+// stacktrace of the panic. The field "recovered" tells if the panic ends there or if it is passed on because
+// rePanic is set, in which case the recovered value is panicked again after logging it.
+//
+//	defer mon.RecoverLog(logger, false)
+func RecoverLog(logger Logger, rePanic bool) {
+	recovered := recover()
+
+	if recovered == nil {
+		return
+	}
+
+	err, ok := recovered.(error)
+
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+
+	// the stacktrace added to error logs still contains the frames of the panic, as we are running deferred
+	logger.WithFields(Fields{
+		"recovered": !rePanic,
+	}).Error(err, "panic")
+
+	if rePanic {
+		panic(recovered)
+	}
+}
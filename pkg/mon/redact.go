@@ -0,0 +1,81 @@
+package mon
+
+import "strings"
+
+const redactedValue = "[redacted]"
+
+// redactMetadata replaces the values of the redacted keys in the fields, context fields and tags of an entry. It runs
+// before the hooks and the formatter, so none of them sees the original values. Maps containing a redacted key are
+// copied, as they are shared with other entries.
+func (l *logger) redactMetadata(data *Metadata) {
+	if len(l.redactedKeys) == 0 {
+		return
+	}
+
+	data.Fields, _ = l.redactFields(data.Fields)
+	data.ContextFields, _ = l.redactFields(data.ContextFields)
+	data.Tags, _ = l.redactFields(data.Tags)
+}
+
+func (l *logger) redactFields(fields map[string]interface{}) (map[string]interface{}, bool) {
+	var redacted map[string]interface{}
+
+	for key, value := range fields {
+		newValue, changed := l.redactValue(key, value)
+
+		if !changed {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = make(map[string]interface{}, len(fields))
+
+			for k, v := range fields {
+				redacted[k] = v
+			}
+		}
+
+		redacted[key] = newValue
+	}
+
+	if redacted == nil {
+		return fields, false
+	}
+
+	return redacted, true
+}
+
+func (l *logger) redactValue(key string, value interface{}) (interface{}, bool) {
+	if l.redactedKeys[strings.ToLower(key)] {
+		return redactedValue, true
+	}
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		return l.redactFields(t)
+	case []interface{}:
+		var redacted []interface{}
+
+		for i, elem := range t {
+			newElem, changed := l.redactValue("", elem)
+
+			if !changed {
+				continue
+			}
+
+			if redacted == nil {
+				redacted = append([]interface{}{}, t...)
+			}
+
+			redacted[i] = newElem
+		}
+
+		if redacted == nil {
+			return value, false
+		}
+
+		return redacted, true
+	}
+
+	return value, false
+}
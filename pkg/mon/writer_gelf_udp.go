@@ -0,0 +1,157 @@
+package mon
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	gelfChunkHeaderSize  = 12
+	gelfDefaultChunkSize = 1420
+	gelfMaxChunks        = 128
+)
+
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+type GelfUdpSettings struct {
+	// Address of the gelf udp input, e.g. graylog:12201
+	Address string `cfg:"address"`
+	// ChunkSize is the maximum size of a datagram including the chunk header, 0 uses 1420 bytes which fit into the
+	// usual mtu of a wan
+	ChunkSize int  `cfg:"chunk_size" default:"1420"`
+	Compress  bool `cfg:"compress"`
+}
+
+type gelfUdpWriter struct {
+	conn     io.WriteCloser
+	settings GelfUdpSettings
+}
+
+// NewGelfUdpWriter returns a writer sending every written message as gelf udp datagram to the configured address.
+// Messages not fitting into a single datagram are split into chunks following the gelf chunking spec.
+func NewGelfUdpWriter(settings GelfUdpSettings) (io.WriteCloser, error) {
+	conn, err := net.Dial("udp", settings.Address)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to gelf udp input %s: %w", settings.Address, err)
+	}
+
+	return NewGelfUdpWriterWithInterfaces(conn, settings)
+}
+
+func NewGelfUdpWriterWithInterfaces(conn io.WriteCloser, settings GelfUdpSettings) (io.WriteCloser, error) {
+	if settings.ChunkSize == 0 {
+		settings.ChunkSize = gelfDefaultChunkSize
+	}
+
+	if settings.ChunkSize <= gelfChunkHeaderSize {
+		return nil, fmt.Errorf("the gelf chunk size has to be bigger than the chunk header of %d bytes", gelfChunkHeaderSize)
+	}
+
+	return &gelfUdpWriter{
+		conn:     conn,
+		settings: settings,
+	}, nil
+}
+
+// WithGelfUdp writes gelf messages directly to a gelf udp input, see NewGelfUdpWriter.
+func WithGelfUdp(settings GelfUdpSettings) LoggerOption {
+	return func(logger *logger) error {
+		writer, err := NewGelfUdpWriter(settings)
+
+		if err != nil {
+			return err
+		}
+
+		if err = WithFormat(FormatGelf)(logger); err != nil {
+			return err
+		}
+
+		return WithWriter(writer)(logger)
+	}
+}
+
+func (w *gelfUdpWriter) Write(p []byte) (int, error) {
+	message := bytes.TrimSuffix(p, []byte("\n"))
+
+	if w.settings.Compress {
+		var err error
+
+		if message, err = gzipMessage(message); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(message) <= w.settings.ChunkSize {
+		if _, err := w.conn.Write(message); err != nil {
+			return 0, fmt.Errorf("can not write gelf message: %w", err)
+		}
+
+		return len(p), nil
+	}
+
+	if err := w.writeChunked(message); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *gelfUdpWriter) Close() error {
+	return w.conn.Close()
+}
+
+func (w *gelfUdpWriter) writeChunked(message []byte) error {
+	dataSize := w.settings.ChunkSize - gelfChunkHeaderSize
+	count := (len(message) + dataSize - 1) / dataSize
+
+	if count > gelfMaxChunks {
+		return fmt.Errorf("the gelf message of %d bytes needs %d chunks, but at most %d are allowed", len(message), count, gelfMaxChunks)
+	}
+
+	messageId := make([]byte, 8)
+
+	if _, err := rand.Read(messageId); err != nil {
+		return fmt.Errorf("can not create gelf message id: %w", err)
+	}
+
+	chunk := make([]byte, 0, w.settings.ChunkSize)
+
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataSize
+
+		if end > len(message) {
+			end = len(message)
+		}
+
+		chunk = append(chunk[:0], gelfChunkMagic...)
+		chunk = append(chunk, messageId...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, message[i*dataSize:end]...)
+
+		if _, err := w.conn.Write(chunk); err != nil {
+			return fmt.Errorf("can not write gelf chunk %d of %d: %w", i+1, count, err)
+		}
+	}
+
+	return nil
+}
+
+func gzipMessage(message []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+
+	if _, err := gz.Write(message); err != nil {
+		return nil, fmt.Errorf("can not compress gelf message: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("can not compress gelf message: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,130 @@
+package mon_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+type datagramRecorder struct {
+	datagrams [][]byte
+	closed    bool
+}
+
+func (r *datagramRecorder) Write(p []byte) (int, error) {
+	r.datagrams = append(r.datagrams, append([]byte{}, p...))
+
+	return len(p), nil
+}
+
+func (r *datagramRecorder) Close() error {
+	r.closed = true
+
+	return nil
+}
+
+func TestGelfUdpWriter_Single(t *testing.T) {
+	conn := &datagramRecorder{}
+	writer, err := mon.NewGelfUdpWriterWithInterfaces(conn, mon.GelfUdpSettings{})
+	assert.NoError(t, err)
+
+	n, err := writer.Write([]byte("{\"short_message\":\"msg\"}\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 24, n)
+
+	assert.Equal(t, [][]byte{[]byte(`{"short_message":"msg"}`)}, conn.datagrams)
+
+	assert.NoError(t, writer.Close())
+	assert.True(t, conn.closed)
+}
+
+func TestGelfUdpWriter_Chunked(t *testing.T) {
+	conn := &datagramRecorder{}
+	writer, err := mon.NewGelfUdpWriterWithInterfaces(conn, mon.GelfUdpSettings{
+		ChunkSize: 22,
+	})
+	assert.NoError(t, err)
+
+	message := []byte("0123456789abcdefghijklmnopqrstu")
+	_, err = writer.Write(message)
+	assert.NoError(t, err)
+
+	assert.Len(t, conn.datagrams, 4)
+
+	messageId := conn.datagrams[0][2:10]
+	reassembled := make([]byte, 0)
+
+	for i, chunk := range conn.datagrams {
+		assert.LessOrEqual(t, len(chunk), 22)
+		assert.Equal(t, []byte{0x1e, 0x0f}, chunk[:2], "chunk %d should start with the magic bytes", i)
+		assert.Equal(t, messageId, chunk[2:10], "all chunks should have the same message id")
+		assert.Equal(t, byte(i), chunk[10], "sequence number")
+		assert.Equal(t, byte(4), chunk[11], "sequence count")
+
+		reassembled = append(reassembled, chunk[12:]...)
+	}
+
+	assert.Equal(t, message, reassembled)
+}
+
+func TestGelfUdpWriter_Compressed(t *testing.T) {
+	conn := &datagramRecorder{}
+	writer, err := mon.NewGelfUdpWriterWithInterfaces(conn, mon.GelfUdpSettings{
+		ChunkSize: 13,
+		Compress:  true,
+	})
+	assert.NoError(t, err)
+
+	_, err = writer.Write([]byte("msg"))
+	assert.NoError(t, err)
+
+	compressed := make([]byte, 0)
+	for _, chunk := range conn.datagrams {
+		compressed = append(compressed, chunk[12:]...)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	assert.NoError(t, err)
+
+	decompressed, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, "msg", string(decompressed))
+
+	writer, err = mon.NewGelfUdpWriterWithInterfaces(conn, mon.GelfUdpSettings{
+		ChunkSize: 13,
+	})
+	assert.NoError(t, err)
+
+	_, err = writer.Write(bytes.Repeat([]byte("a"), 129))
+	assert.Error(t, err, "a message needing more than 128 chunks can not be sent")
+}
+
+func TestGelfUdpWriter_BufferedOutput(t *testing.T) {
+	conn := &datagramRecorder{}
+	writer, err := mon.NewGelfUdpWriterWithInterfaces(conn, mon.GelfUdpSettings{})
+	assert.NoError(t, err)
+
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), writer)
+	err = logger.Option(mon.WithFormat(mon.FormatGelf), mon.WithBufferedOutput(300, 0))
+	assert.NoError(t, err)
+
+	for _, msg := range []string{"first", "second", "third", "fourth"} {
+		logger.Info(msg)
+	}
+
+	assert.NoError(t, logger.Flush())
+	assert.Len(t, conn.datagrams, 4, "every entry should be sent as its own datagram")
+
+	for i, msg := range []string{"first", "second", "third", "fourth"} {
+		message := make(map[string]interface{})
+		err := json.Unmarshal(conn.datagrams[i], &message)
+
+		assert.NoError(t, err, "datagram %d should be a single gelf message", i)
+		assert.Equal(t, msg, message["short_message"])
+	}
+}
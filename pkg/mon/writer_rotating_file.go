@@ -0,0 +1,273 @@
+package mon
+
+import (
+	"compress/gzip"
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotatingFileTimestampFormat = "20060102T150405.000000000"
+
+type RotatingFileSettings struct {
+	// Path of the active log file, rotated files are stored next to it
+	Path string `cfg:"path"`
+	// MaxSize in bytes after which the file is rotated, 0 disables size based rotation
+	MaxSize int64 `cfg:"max_size"`
+	// MaxAge after which the file is rotated, 0 disables age based rotation
+	MaxAge time.Duration `cfg:"max_age"`
+	// MaxBackups is the number of rotated files to keep, 0 keeps all of them
+	MaxBackups int  `cfg:"max_backups"`
+	Compress   bool `cfg:"compress"`
+}
+
+type rotatingFileWriter struct {
+	lck      sync.Mutex
+	clock    clock.Clock
+	settings RotatingFileSettings
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	compressing sync.WaitGroup
+	compressLck sync.Mutex
+	compressErr error
+}
+
+// NewRotatingFileWriter returns a writer appending to the configured file and rotating it by size and age. Every
+// write either goes to the old or to the new file as a whole, so entries are never split across files. Rotated files
+// are compressed in the background, Close waits for it and returns the last error of a compression.
+func NewRotatingFileWriter(settings RotatingFileSettings) (io.WriteCloser, error) {
+	return NewRotatingFileWriterWithInterfaces(clock.NewRealClock(), settings)
+}
+
+func NewRotatingFileWriterWithInterfaces(clock clock.Clock, settings RotatingFileSettings) (io.WriteCloser, error) {
+	if settings.Path == "" {
+		return nil, fmt.Errorf("the path of the rotating log file is missing")
+	}
+
+	w := &rotatingFileWriter{
+		clock:    clock,
+		settings: settings,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// WithRotatingFile writes json lines to a rotating file, see NewRotatingFileWriter.
+func WithRotatingFile(settings RotatingFileSettings) LoggerOption {
+	return func(logger *logger) error {
+		writer, err := NewRotatingFileWriter(settings)
+
+		if err != nil {
+			return fmt.Errorf("can not create rotating log file: %w", err)
+		}
+
+		if err = WithFormat(FormatJson)(logger); err != nil {
+			return err
+		}
+
+		return WithWriter(writer)(logger)
+	}
+}
+
+func (w *rotatingFileWriter) Write(p []byte) (int, error) {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+func (w *rotatingFileWriter) Close() error {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	w.compressing.Wait()
+
+	if err == nil {
+		w.compressLck.Lock()
+		err = w.compressErr
+		w.compressLck.Unlock()
+	}
+
+	return err
+}
+
+func (w *rotatingFileWriter) shouldRotate(writeSize int64) bool {
+	if w.size == 0 {
+		return false
+	}
+
+	if w.settings.MaxSize > 0 && w.size+writeSize > w.settings.MaxSize {
+		return true
+	}
+
+	return w.settings.MaxAge > 0 && w.clock.Now().Sub(w.openedAt) >= w.settings.MaxAge
+}
+
+func (w *rotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.settings.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return fmt.Errorf("can not open log file %s: %w", w.settings.Path, err)
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("can not stat log file %s: %w", w.settings.Path, err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.clock.Now()
+
+	return nil
+}
+
+func (w *rotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("can not close log file %s: %w", w.settings.Path, err)
+	}
+
+	ext := filepath.Ext(w.settings.Path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(w.settings.Path, ext), w.clock.Now().Format(rotatingFileTimestampFormat), ext)
+
+	// a rename is atomic, so the file is either still active or completely rotated
+	if err := os.Rename(w.settings.Path, backup); err != nil {
+		return fmt.Errorf("can not rotate log file %s: %w", w.settings.Path, err)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	if w.settings.Compress {
+		w.compressing.Add(1)
+		go w.compress(backup)
+
+		return nil
+	}
+
+	return w.removeBackups()
+}
+
+// compress compresses a rotated file without holding the lock of the writer, so writes to the new file don't wait for
+// it. The old backups are removed afterwards to count the compressed file like the others. Compressions run one after
+// another.
+func (w *rotatingFileWriter) compress(backup string) {
+	defer w.compressing.Done()
+
+	w.compressLck.Lock()
+	defer w.compressLck.Unlock()
+
+	err := compressFile(backup)
+
+	if err == nil {
+		err = w.removeBackups()
+	}
+
+	if err != nil {
+		w.compressErr = err
+	}
+}
+
+func (w *rotatingFileWriter) removeBackups() error {
+	if w.settings.MaxBackups <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(w.settings.Path)
+	backups, err := filepath.Glob(fmt.Sprintf("%s-*%s*", strings.TrimSuffix(w.settings.Path, ext), ext))
+
+	if err != nil {
+		return fmt.Errorf("can not list log file backups: %w", err)
+	}
+
+	if len(backups) <= w.settings.MaxBackups {
+		return nil
+	}
+
+	// the timestamp in the file names sorts the backups from old to new
+	sort.Strings(backups)
+
+	for _, backup := range backups[:len(backups)-w.settings.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("can not remove log file backup %s: %w", backup, err)
+		}
+	}
+
+	return nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+
+	if err != nil {
+		return fmt.Errorf("can not open log file backup %s: %w", path, err)
+	}
+
+	defer src.Close()
+
+	// write to a temporary file first to never leave a truncated archive behind
+	tmpPath := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return fmt.Errorf("can not create compressed log file backup %s: %w", tmpPath, err)
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath)
+
+		return fmt.Errorf("can not compress log file backup %s: %w", path, err)
+	}
+
+	if err = os.Rename(tmpPath, path+".gz"); err != nil {
+		return fmt.Errorf("can not rename compressed log file backup %s: %w", tmpPath, err)
+	}
+
+	return os.Remove(path)
+}
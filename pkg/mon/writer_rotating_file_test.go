@@ -0,0 +1,90 @@
+package mon_test
+
+import (
+	"compress/gzip"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileWriter_Size(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotating")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clk := clock.NewFakeClockAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	path := filepath.Join(dir, "app.jsonl")
+
+	writer, err := mon.NewRotatingFileWriterWithInterfaces(clk, mon.RotatingFileSettings{
+		Path:       path,
+		MaxSize:    10,
+		MaxBackups: 2,
+	})
+	assert.NoError(t, err)
+
+	for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		_, err = writer.Write([]byte(line))
+		assert.NoError(t, err)
+		clk.Advance(time.Second)
+	}
+
+	assert.NoError(t, writer.Close())
+
+	assertFileContent(t, path, "line4\n")
+	assertFileContent(t, filepath.Join(dir, "app-20200101T000002.000000000.jsonl"), "line2\n")
+	assertFileContent(t, filepath.Join(dir, "app-20200101T000003.000000000.jsonl"), "line3\n")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 3, "the oldest backup should have been removed")
+}
+
+func TestRotatingFileWriter_AgeCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotating")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clk := clock.NewFakeClockAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	path := filepath.Join(dir, "app.jsonl")
+
+	writer, err := mon.NewRotatingFileWriterWithInterfaces(clk, mon.RotatingFileSettings{
+		Path:     path,
+		MaxAge:   time.Hour,
+		Compress: true,
+	})
+	assert.NoError(t, err)
+
+	_, err = writer.Write([]byte("old\n"))
+	assert.NoError(t, err)
+
+	clk.Advance(time.Hour)
+
+	_, err = writer.Write([]byte("new\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	assertFileContent(t, path, "new\n")
+
+	file, err := os.Open(filepath.Join(dir, "app-20200101T010000.000000000.jsonl.gz"))
+	assert.NoError(t, err)
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, "old\n", string(content))
+}
+
+func assertFileContent(t *testing.T, path string, expected string) {
+	content, err := ioutil.ReadFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
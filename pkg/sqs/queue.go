@@ -2,6 +2,7 @@ package sqs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/cloud"
@@ -171,8 +172,11 @@ func (q *queue) SendBatch(ctx context.Context, messages []*Message) error {
 		return q.client.SendMessageBatchRequest(input)
 	})
 	if err != nil {
-		if err, ok := err.(awserr.Error); ok &&
-			err.Code() == sqs.ErrCodeBatchRequestTooLong &&
+		// the executor wraps the error of the last attempt, see exec.AttemptsError
+		var awsErr awserr.Error
+
+		if errors.As(err, &awsErr) &&
+			awsErr.Code() == sqs.ErrCodeBatchRequestTooLong &&
 			len(messages) > 1 {
 			logger.Info("messages were bigger than the allowed max, splitting them up")
 
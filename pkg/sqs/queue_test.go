@@ -3,6 +3,7 @@ package sqs_test
 import (
 	"context"
 	awsMocks "github.com/applike/gosoline/pkg/cloud/aws/mocks"
+	"github.com/applike/gosoline/pkg/exec"
 	"github.com/applike/gosoline/pkg/mon/mocks"
 	gosoSqs "github.com/applike/gosoline/pkg/sqs"
 	sqsMocks "github.com/applike/gosoline/pkg/sqs/mocks"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 func TestRunQueueTestSuite(t *testing.T) {
@@ -91,3 +93,23 @@ func (qs *queueTestSuite) TestSendBatch_ThreeMsgRequestTooLongError() {
 	err := qs.queue.SendBatch(context.Background(), msgs)
 	qs.Nil(err)
 }
+
+func (qs *queueTestSuite) TestSendBatch_WrappedRequestTooLongError() {
+	msgs := []*gosoSqs.Message{
+		{Body: aws.String("foo")},
+		{Body: aws.String("bar")},
+	}
+
+	awsErr := exec.NewAttemptsError(3, time.Second, awserr.New(sqs.ErrCodeBatchRequestTooLong, "foo", nil))
+	qs.executor.
+		On("Execute", context.Background(), mock.AnythingOfType("aws.RequestFunction")).
+		Once().
+		Return(nil, awsErr)
+	qs.executor.
+		On("Execute", context.Background(), mock.AnythingOfType("aws.RequestFunction")).
+		Twice().
+		Return(nil, nil)
+	err := qs.queue.SendBatch(context.Background(), msgs)
+	qs.Nil(err)
+	qs.executor.AssertNumberOfCalls(qs.T(), "Execute", 3)
+}
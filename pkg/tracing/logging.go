@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 	"github.com/applike/gosoline/pkg/mon"
 )
 
@@ -48,3 +49,16 @@ func (l LoggerErrorHook) Fire(_ string, _ string, err error, data *mon.Metadata)
 
 	return nil
 }
+
+// ErrorWithTags logs the error with the tags as fields and attaches the same tags as annotations to the span of the
+// context, so traces and logs can be searched by the same values, e.g. the http status or an error code. The error
+// itself is added to the span by the LoggerErrorHook. Without a span in the context the error is only logged.
+func ErrorWithTags(ctx context.Context, logger mon.Logger, err error, msg string, tags mon.Fields) {
+	if span := GetSpanFromContext(ctx); span != nil {
+		for key, value := range tags {
+			span.AddAnnotation(key, fmt.Sprint(value))
+		}
+	}
+
+	logger.WithContext(ctx).WithFields(tags).Error(err, msg)
+}
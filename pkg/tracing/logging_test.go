@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/tracing"
 	"github.com/applike/gosoline/pkg/tracing/mocks"
 	"github.com/stretchr/testify/suite"
@@ -53,6 +54,44 @@ func (s *LoggingSuite) TestLoggerErrorHook() {
 	s.span.AssertExpectations(s.T())
 }
 
+func (s *LoggingSuite) TestErrorWithTags() {
+	errToLog := fmt.Errorf("unexpected error")
+	tags := mon.Fields{
+		"error_code":  "not_found",
+		"http_status": 404,
+	}
+
+	s.span.On("AddAnnotation", "error_code", "not_found").Once()
+	s.span.On("AddAnnotation", "http_status", "404").Once()
+
+	logger := new(monMocks.Logger)
+	logger.On("WithContext", s.ctx).Return(logger).Once()
+	logger.On("WithFields", tags).Return(logger).Once()
+	logger.On("Error", errToLog, "request failed").Once()
+
+	tracing.ErrorWithTags(s.ctx, logger, errToLog, "request failed", tags)
+
+	s.span.AssertExpectations(s.T())
+	logger.AssertExpectations(s.T())
+}
+
+func (s *LoggingSuite) TestErrorWithTags_WithoutSpan() {
+	ctx := context.Background()
+	errToLog := fmt.Errorf("unexpected error")
+	tags := mon.Fields{
+		"http_status": 500,
+	}
+
+	logger := new(monMocks.Logger)
+	logger.On("WithContext", ctx).Return(logger).Once()
+	logger.On("WithFields", tags).Return(logger).Once()
+	logger.On("Error", errToLog, "request failed").Once()
+
+	tracing.ErrorWithTags(ctx, logger, errToLog, "request failed", tags)
+
+	logger.AssertExpectations(s.T())
+}
+
 func TestLoggingSuite(t *testing.T) {
 	suite.Run(t, new(LoggingSuite))
 }